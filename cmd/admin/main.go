@@ -0,0 +1,57 @@
+// Утилита управления ролями: go run ./cmd/admin grant|revoke <login> [role]
+// Нужна для назначения первого администратора, когда в системе ещё нет админов.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"tic-tac-toe/internal/config"
+	model "tic-tac-toe/internal/domain/model/user"
+	userService "tic-tac-toe/internal/service/user_service"
+	"tic-tac-toe/internal/storage/postgres"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println(".env file not found, using system env")
+	}
+	if len(os.Args) < 3 || len(os.Args) > 4 {
+		fmt.Fprintln(os.Stderr, "usage: admin grant|revoke <login> [role]")
+		os.Exit(2)
+	}
+	command, login := os.Args[1], os.Args[2]
+	role := model.RoleAdmin
+	if len(os.Args) == 4 {
+		role = model.Role(os.Args[3])
+	}
+
+	pool, err := postgres.NewDB(config.NewConfig())
+	if err != nil {
+		log.Fatalf("Ошибка подключения к БД: %v", err)
+	}
+	defer pool.Close()
+
+	ctx := context.Background()
+	users := userService.NewUserServices(postgres.NewUserRepository(pool), postgres.NewTxManager(pool))
+	user, err := users.GetByLogin(ctx, login)
+	if err != nil {
+		log.Fatalf("Пользователь %q: %v", login, err)
+	}
+
+	switch command {
+	case "grant":
+		err = users.GrantRole(ctx, user.UUID, role)
+	case "revoke":
+		err = users.RevokeRole(ctx, user.UUID, role)
+	default:
+		log.Fatalf("Неизвестная команда %q", command)
+	}
+	if err != nil {
+		log.Fatalf("Ошибка изменения роли: %v", err)
+	}
+}
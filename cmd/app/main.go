@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"tic-tac-toe/internal/config"
 	"tic-tac-toe/internal/di"
 
 	"github.com/joho/godotenv"
@@ -14,6 +15,6 @@ func main() {
 	}
 
 	fx.New(
-		di.Module,
+		di.Module(config.NewConfig()),
 	).Run()
 }
@@ -3,14 +3,23 @@ package app
 import (
 	"context"
 	"log"
+	"time"
 
+	"tic-tac-toe/internal/config"
+	authModel "tic-tac-toe/internal/domain/model/auth"
+	idempotency "tic-tac-toe/internal/domain/model/idempotency"
+	ratelimit "tic-tac-toe/internal/domain/model/ratelimit"
 	"tic-tac-toe/internal/server"
+	adminService "tic-tac-toe/internal/service/admin_service"
+	eventService "tic-tac-toe/internal/service/event_service"
+	notificationService "tic-tac-toe/internal/service/notification_service"
+	seasonService "tic-tac-toe/internal/service/season_service"
+	webhookService "tic-tac-toe/internal/service/webhook_service"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/fx"
 )
 
-func NewApp(lc fx.Lifecycle, server *server.Server, pool *pgxpool.Pool) {
+func NewApp(lc fx.Lifecycle, server *server.Server) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			go func() {
@@ -22,8 +31,183 @@ func NewApp(lc fx.Lifecycle, server *server.Server, pool *pgxpool.Pool) {
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
-			pool.Close()
 			return server.Shutdown(ctx)
 		},
 	})
 }
+
+// runBackground запускает run в горутине на время жизни приложения. При остановке
+// контекст run отменяется, и остановка ждёт его завершения: начатая работа успевает сохраниться
+func runBackground(lc fx.Lifecycle, run func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// runPeriodic вызывает fn сразу после старта и затем раз в interval
+func runPeriodic(lc fx.Lifecycle, interval time.Duration, fn func(ctx context.Context)) {
+	runTriggered(lc, interval, nil, fn)
+}
+
+// runTriggered как runPeriodic, но вызывает fn и по сигналу wake, не дожидаясь тикера
+func runTriggered(lc fx.Lifecycle, interval time.Duration, wake <-chan struct{}, fn func(ctx context.Context)) {
+	runBackground(lc, func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			fn(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-wake:
+			}
+		}
+	})
+}
+
+// как часто проверять окончание сезона
+const seasonCheckInterval = time.Minute
+
+// NewSeasonScheduler периодически закрывает завершившийся сезон
+func NewSeasonScheduler(lc fx.Lifecycle, seasons seasonService.SeasonService) {
+	runPeriodic(lc, seasonCheckInterval, func(ctx context.Context) {
+		if err := seasons.Rollover(ctx, time.Now()); err != nil {
+			log.Printf("Ошибка смены сезона: %v", err)
+		}
+	})
+}
+
+// как часто удалять неиспользуемые бакеты ограничителя запросов
+const rateLimitCleanupInterval = time.Hour
+
+// NewRateLimitCleaner удаляет бакеты, не менявшиеся дольше максимального периода:
+// такой бакет всё равно уже полон
+func NewRateLimitCleaner(lc fx.Lifecycle, limiter ratelimit.Limiter) {
+	runPeriodic(lc, rateLimitCleanupInterval, func(ctx context.Context) {
+		if err := limiter.Cleanup(ctx, time.Now().Add(-ratelimit.MaxPeriod)); err != nil {
+			log.Printf("Ошибка очистки ограничителя запросов: %v", err)
+		}
+	})
+}
+
+// как часто удалять истёкшие ключи идемпотентности
+const idempotencyCleanupInterval = time.Hour
+
+// NewIdempotencyCleaner удаляет ключи идемпотентности, срок которых истёк
+func NewIdempotencyCleaner(lc fx.Lifecycle, keys idempotency.Repository) {
+	runPeriodic(lc, idempotencyCleanupInterval, func(ctx context.Context) {
+		if _, err := keys.DeleteExpired(ctx, time.Now()); err != nil {
+			log.Printf("Ошибка очистки ключей идемпотентности: %v", err)
+		}
+	})
+}
+
+// как часто удалять истёкшие refresh токены
+const tokenCleanupInterval = time.Hour
+
+// NewTokenCleaner удаляет refresh токены истёкших сессий: каждая ротация
+// добавляет строку, и без очистки таблица растёт бесконечно
+func NewTokenCleaner(lc fx.Lifecycle, tokens authModel.TokenRepository) {
+	runPeriodic(lc, tokenCleanupInterval, func(ctx context.Context) {
+		if _, err := tokens.DeleteExpired(ctx, time.Now()); err != nil {
+			log.Printf("Ошибка очистки refresh токенов: %v", err)
+		}
+	})
+}
+
+const (
+	// как часто разбирать outbox, если сервис не будил диспетчер
+	eventDispatchInterval = time.Second
+	// как часто удалять старые обработанные события
+	eventCleanupInterval = time.Hour
+)
+
+// NewEventDispatcher доставляет события игр из outbox подписчикам шины; сервис игр
+// будит его после сохранения, тикер подбирает события других инстансов и повторы
+func NewEventDispatcher(lc fx.Lifecycle, bus eventService.EventBus) {
+	runTriggered(lc, eventDispatchInterval, bus.Wakeups(), func(ctx context.Context) {
+		// полная пачка - в outbox могут быть ещё события
+		for ctx.Err() == nil {
+			n, err := bus.Dispatch(ctx)
+			if err != nil {
+				log.Printf("Ошибка доставки событий: %v", err)
+			}
+			if err != nil || n < eventService.DispatchBatch {
+				return
+			}
+		}
+	})
+	runPeriodic(lc, eventCleanupInterval, func(ctx context.Context) {
+		if _, err := bus.Cleanup(ctx); err != nil {
+			log.Printf("Ошибка очистки outbox: %v", err)
+		}
+	})
+}
+
+// как часто отправлять доставки вебхуков
+const webhookDispatchInterval = 5 * time.Second
+
+// NewWebhookDispatcher отправляет доставки вебхуков, время которых пришло;
+// несколько инстансов разбирают разные записи
+func NewWebhookDispatcher(lc fx.Lifecycle, webhooks webhookService.WebhookService) {
+	runPeriodic(lc, webhookDispatchInterval, func(ctx context.Context) {
+		if _, err := webhooks.DeliverDue(ctx); err != nil {
+			log.Printf("Ошибка доставки вебхуков: %v", err)
+		}
+	})
+}
+
+// пауза перед повторной подпиской на уведомления после обрыва соединения
+const notificationReconnectDelay = time.Second
+
+// NewNotificationListener раздаёт потокам SSE этого инстанса уведомления,
+// созданные любым инстансом; после обрыва соединения подписка возобновляется
+func NewNotificationListener(lc fx.Lifecycle, notifications notificationService.NotificationService) {
+	runBackground(lc, func(ctx context.Context) {
+		for {
+			err := notifications.Listen(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Ошибка подписки на уведомления: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(notificationReconnectDelay):
+			}
+		}
+	})
+}
+
+// NewAdminBootstrap выдаёт роль admin пользователю из BOOTSTRAP_ADMIN при старте;
+// пользователь должен быть уже зарегистрирован
+func NewAdminBootstrap(lc fx.Lifecycle, cfg *config.Config, admin adminService.AdminService) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if cfg.BootstrapAdmin == "" {
+				return nil
+			}
+			if err := admin.BootstrapAdmin(ctx, cfg.BootstrapAdmin); err != nil {
+				log.Printf("Не удалось назначить администратора %q: %v", cfg.BootstrapAdmin, err)
+			}
+			return nil
+		},
+	})
+}
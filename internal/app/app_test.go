@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/fx/fxtest"
+)
+
+func TestRunPeriodicWaitsOnStop(t *testing.T) {
+	lc := fxtest.NewLifecycle(t)
+	var calls, saved atomic.Int32
+	started := make(chan struct{})
+	runPeriodic(lc, time.Hour, func(ctx context.Context) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		// работа, которая сохраняет результат уже после отмены контекста
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		saved.Add(1)
+	})
+
+	lc.RequireStart()
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fn was not called on start")
+	}
+	lc.RequireStop()
+	if calls.Load() != 1 || saved.Load() != 1 {
+		t.Errorf("calls = %d, saved = %d; want stop to wait for the running call", calls.Load(), saved.Load())
+	}
+}
+
+func TestRunTriggeredWakes(t *testing.T) {
+	lc := fxtest.NewLifecycle(t)
+	wake := make(chan struct{}, 1)
+	calls := make(chan struct{}, 10)
+	runTriggered(lc, time.Hour, wake, func(context.Context) { calls <- struct{}{} })
+
+	lc.RequireStart()
+	defer lc.RequireStop()
+	// первый вызов - при старте, второй - по сигналу, задолго до тикера
+	for i := range 2 {
+		if i == 1 {
+			wake <- struct{}{}
+		}
+		select {
+		case <-calls:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("call %d not made", i+1)
+		}
+	}
+}
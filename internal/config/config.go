@@ -2,12 +2,73 @@ package config
 
 import (
 	"os"
+	"strings"
 )
 
 type Config struct {
 	ServerPort string
 	DB         ConfigDB
 	JWT        []byte
+	JWTKeys    ConfigJWTKeys
+	// логин пользователя, которому при старте выдаётся роль admin
+	BootstrapAdmin string
+	RateLimit      ConfigRateLimit
+	Notifier       ConfigNotifier
+	OIDC           ConfigOIDC
+	Cookies        ConfigCookies
+	Webhooks       ConfigWebhooks
+	// postgres или memory - всё в памяти процесса (тесты и локальная разработка)
+	Storage string
+	// сети прокси (CIDR), которым разрешено передавать X-Forwarded-For
+	TrustedProxies []string
+}
+
+// доставка вебхуков
+type ConfigWebhooks struct {
+	// разрешить адреса в локальной сети (только разработка и тесты)
+	AllowPrivate bool
+}
+
+// авторизация через HttpOnly cookie (по выбору клиента)
+type ConfigCookies struct {
+	Enabled bool
+	// false - только для локальной разработки по http
+	Secure bool
+	// Strict, Lax или None
+	SameSite string
+}
+
+// вход через OIDC-провайдера; пустой Issuer - отключён
+type ConfigOIDC struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// доставка сообщений пользователям (токены сброса пароля)
+type ConfigNotifier struct {
+	// log - в лог сервера, file - в файл Path
+	Type string
+	Path string
+}
+
+// лимиты запросов по группам маршрутов в формате "<запросы>/<период>"
+type ConfigRateLimit struct {
+	// memory - в памяти процесса, postgres - общие для всех инстансов
+	Backend    string
+	Auth       string
+	GameCreate string
+	Moves      string
+	Reads      string
+}
+
+// асимметричные ключи JWT; если не заданы, используется JWT_SECRET (HS256)
+type ConfigJWTKeys struct {
+	Dir        string
+	Files      []string
+	SigningKID string
 }
 
 type ConfigDB struct {
@@ -21,6 +82,40 @@ func NewConfig() *Config {
 			URL: getEnv("DATABASE_URL", ""),
 		},
 		JWT: []byte(getEnv("JWT_SECRET", "")),
+		JWTKeys: ConfigJWTKeys{
+			Dir:        getEnv("JWT_KEYS_DIR", ""),
+			Files:      splitList(getEnv("JWT_KEY_FILES", "")),
+			SigningKID: getEnv("JWT_SIGNING_KID", ""),
+		},
+		BootstrapAdmin: getEnv("BOOTSTRAP_ADMIN", ""),
+		RateLimit: ConfigRateLimit{
+			Backend:    getEnv("RATE_LIMIT_BACKEND", "memory"),
+			Auth:       getEnv("RATE_LIMIT_AUTH", "20/1m"),
+			GameCreate: getEnv("RATE_LIMIT_GAME_CREATE", "10/1m"),
+			Moves:      getEnv("RATE_LIMIT_MOVES", "120/1m"),
+			Reads:      getEnv("RATE_LIMIT_READS", "300/1m"),
+		},
+		Notifier: ConfigNotifier{
+			Type: getEnv("NOTIFIER", "log"),
+			Path: getEnv("NOTIFIER_FILE", "notifications.log"),
+		},
+		OIDC: ConfigOIDC{
+			Issuer:       getEnv("OIDC_ISSUER", ""),
+			ClientID:     getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:  getEnv("OIDC_REDIRECT_URL", "http://localhost:8081/auth/oidc/callback"),
+			Scopes:       splitList(getEnv("OIDC_SCOPES", "openid,profile,email")),
+		},
+		Cookies: ConfigCookies{
+			Enabled:  getEnv("AUTH_COOKIES", "false") == "true",
+			Secure:   getEnv("AUTH_COOKIE_SECURE", "true") == "true",
+			SameSite: getEnv("AUTH_COOKIE_SAMESITE", "Strict"),
+		},
+		Webhooks: ConfigWebhooks{
+			AllowPrivate: getEnv("WEBHOOK_ALLOW_PRIVATE", "false") == "true",
+		},
+		Storage:        getEnv("STORAGE", "postgres"),
+		TrustedProxies: splitList(getEnv("TRUSTED_PROXIES", "")),
 	}
 }
 
@@ -30,3 +125,13 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -1,40 +1,224 @@
 package di
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+
 	"tic-tac-toe/internal/app"
 	"tic-tac-toe/internal/config"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	ratelimit "tic-tac-toe/internal/domain/model/ratelimit"
+	webhookModel "tic-tac-toe/internal/domain/model/webhook"
 	"tic-tac-toe/internal/server"
+	achievementService "tic-tac-toe/internal/service/achievement_service"
+	adminService "tic-tac-toe/internal/service/admin_service"
 	authService "tic-tac-toe/internal/service/auth_service"
+	eventService "tic-tac-toe/internal/service/event_service"
 	gameService "tic-tac-toe/internal/service/game_service"
 	jwtService "tic-tac-toe/internal/service/jwt_service"
+	moderationService "tic-tac-toe/internal/service/moderation_service"
+	notificationService "tic-tac-toe/internal/service/notification_service"
+	notifierService "tic-tac-toe/internal/service/notifier_service"
+	oidcService "tic-tac-toe/internal/service/oidc_service"
+	presenceService "tic-tac-toe/internal/service/presence_service"
+	seasonService "tic-tac-toe/internal/service/season_service"
+	tournamentService "tic-tac-toe/internal/service/tournament_service"
 	userService "tic-tac-toe/internal/service/user_service"
+	webhookService "tic-tac-toe/internal/service/webhook_service"
+	"tic-tac-toe/internal/storage/memory"
 	"tic-tac-toe/internal/storage/postgres"
 	"tic-tac-toe/internal/web/handler"
+	"tic-tac-toe/internal/web/middleware"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/fx"
 )
 
 // FX сам определяет порядок создания объектов
 // FX управляет жизненным циклом компонентов
 // Проверяет что все зависимости могут быть созданы
-var Module = fx.Module("tic-tac-toe",
+func Module(cfg *config.Config) fx.Option {
+	return fx.Module("tic-tac-toe",
+		fx.Supply(cfg),
+		storage(cfg),
+		services,
+	)
+}
+
+// storage выбирает хранилище по STORAGE: postgres или memory (данные в памяти процесса)
+func storage(cfg *config.Config) fx.Option {
+	switch cfg.Storage {
+	case "postgres":
+		return fx.Provide(
+			func(lc fx.Lifecycle, cfg *config.Config) (*pgxpool.Pool, error) {
+				pool, err := postgres.NewDB(cfg)
+				if err != nil {
+					return nil, err
+				}
+				// пул создаётся раньше зависящих от него компонентов и закрывается после них
+				lc.Append(fx.Hook{
+					OnStop: func(context.Context) error {
+						pool.Close()
+						return nil
+					},
+				})
+				return pool, nil
+			},
+			postgres.NewTxManager,
+			postgres.NewGameRepository,
+			postgres.NewOutboxRepository,
+			postgres.NewUserRepository,
+			postgres.NewTokenRepository,
+			postgres.NewLoginAttemptRepository,
+			postgres.NewPasswordResetRepository,
+			postgres.NewTwoFactorRepository,
+			postgres.NewIdentityRepository,
+			postgres.NewLoginStateRepository,
+			postgres.NewAchievementRepository,
+			postgres.NewTournamentRepository,
+			postgres.NewSeasonRepository,
+			postgres.NewStatsRepository,
+			postgres.NewModerationRepository,
+			postgres.NewFriendRepository,
+			postgres.NewPresenceRepository,
+			postgres.NewNotificationRepository,
+			postgres.NewNotificationBroker,
+			postgres.NewWebhookRepository,
+			postgres.NewIdempotencyRepository,
+			func(cfg *config.Config, pool *pgxpool.Pool) (ratelimit.Limiter, error) {
+				switch cfg.RateLimit.Backend {
+				case "memory":
+					return memory.NewRateLimiter(), nil
+				case "postgres":
+					return postgres.NewRateLimiter(pool), nil
+				default:
+					return nil, fmt.Errorf("неизвестный RATE_LIMIT_BACKEND %q", cfg.RateLimit.Backend)
+				}
+			},
+		)
+	case "memory":
+		return fx.Provide(
+			memory.NewStore,
+			memory.NewTxManager,
+			memory.NewGameRepository,
+			memory.NewOutboxRepository,
+			memory.NewUserRepository,
+			memory.NewTokenRepository,
+			memory.NewLoginAttemptRepository,
+			memory.NewPasswordResetRepository,
+			memory.NewTwoFactorRepository,
+			memory.NewIdentityRepository,
+			memory.NewLoginStateRepository,
+			memory.NewAchievementRepository,
+			memory.NewTournamentRepository,
+			memory.NewSeasonRepository,
+			memory.NewStatsRepository,
+			memory.NewModerationRepository,
+			memory.NewFriendRepository,
+			memory.NewPresenceRepository,
+			memory.NewNotificationRepository,
+			memory.NewNotificationBroker,
+			memory.NewWebhookRepository,
+			memory.NewIdempotencyRepository,
+			func(cfg *config.Config) (ratelimit.Limiter, error) {
+				if cfg.RateLimit.Backend != "memory" {
+					return nil, fmt.Errorf("RATE_LIMIT_BACKEND %q недоступен при STORAGE=memory", cfg.RateLimit.Backend)
+				}
+				return memory.NewRateLimiter(), nil
+			},
+		)
+	default:
+		return fx.Error(fmt.Errorf("неизвестный STORAGE %q", cfg.Storage))
+	}
+}
+
+// сервисы, обработчики и фоновые задачи поверх хранилища
+var services = fx.Options(
 	fx.Provide(
-		config.NewConfig,
-		func(cfg *config.Config) []byte {
-			return cfg.JWT
+		func(cfg *config.Config) (*jwtService.KeySet, error) {
+			return jwtService.LoadKeySet(cfg.JWTKeys.Dir, cfg.JWTKeys.Files, cfg.JWTKeys.SigningKID, cfg.JWT)
+		},
+		func(cfg *config.Config) (notifierService.Notifier, error) {
+			switch cfg.Notifier.Type {
+			case "log":
+				return notifierService.NewLogNotifier(), nil
+			case "file":
+				return notifierService.NewFileNotifier(cfg.Notifier.Path), nil
+			default:
+				return nil, fmt.Errorf("неизвестный NOTIFIER %q", cfg.Notifier.Type)
+			}
+		},
+		func(cfg *config.Config) oidcService.Config {
+			return oidcService.Config(cfg.OIDC)
+		},
+		oidcService.NewOIDCService,
+		func(cfg *config.Config) (middleware.CookieConfig, error) {
+			cookies := middleware.CookieConfig{Enabled: cfg.Cookies.Enabled, Secure: cfg.Cookies.Secure}
+			switch cfg.Cookies.SameSite {
+			case "Strict":
+				cookies.SameSite = http.SameSiteStrictMode
+			case "Lax":
+				cookies.SameSite = http.SameSiteLaxMode
+			case "None":
+				cookies.SameSite = http.SameSiteNoneMode
+			default:
+				return cookies, fmt.Errorf("неизвестный AUTH_COOKIE_SAMESITE %q", cfg.Cookies.SameSite)
+			}
+			return cookies, nil
 		},
-		postgres.NewDB,
-		postgres.NewGameRepository,
-		postgres.NewUserRepository,
-		postgres.NewTokenRepository,
 		jwtService.NewJwtProvider,
+		eventService.NewEventBus,
+		achievementService.NewAchievementService,
+		seasonService.NewSeasonService,
+		notificationService.NewNotificationService,
+		func(cfg *config.Config, repo webhookModel.WebhookRepository) webhookService.WebhookService {
+			return webhookService.NewWebhookService(repo, cfg.Webhooks.AllowPrivate)
+		},
+		userService.NewFriendService,
+		presenceService.NewPresenceService,
 		gameService.NewGameService,
+		tournamentService.NewTournamentService,
 		userService.NewUserServices,
+		moderationService.NewModerationService,
 		authService.NewAuthServices,
+		adminService.NewAdminService,
 		handler.NewGameAPI,
 		handler.NewAuthAPI,
+		handler.NewTournamentAPI,
+		handler.NewSeasonAPI,
+		handler.NewOIDCAPI,
+		handler.NewAdminAPI,
+		handler.NewModerationAPI,
+		handler.NewFriendAPI,
+		handler.NewPresenceAPI,
+		handler.NewNotificationAPI,
+		handler.NewWebhookAPI,
 		server.NewServer,
 	),
+	// подписчики событий игр; имя подписчика хранится в outbox, менять его нельзя
+	fx.Invoke(func(bus eventService.EventBus, achievements achievementService.AchievementService,
+		tournaments tournamentService.TournamentService, notifications notificationService.NotificationService,
+		webhooks webhookService.WebhookService) {
+		bus.Subscribe("achievements", func(ctx context.Context, e gameModel.Event) error {
+			_, err := achievements.Evaluate(ctx, e.Game())
+			return err
+		}, gameModel.EventGameFinished)
+		bus.Subscribe("tournaments", func(ctx context.Context, e gameModel.Event) error {
+			return tournaments.HandleGameFinished(ctx, e.Game())
+		}, gameModel.EventGameFinished)
+		bus.Subscribe("notifications", notifications.HandleGameEvent,
+			gameModel.EventPlayerJoined, gameModel.EventMoveMade, gameModel.EventGameFinished)
+		bus.Subscribe("webhooks", webhooks.HandleEvent)
+	}),
 	//запуск
 	fx.Invoke(app.NewApp),
+	fx.Invoke(app.NewSeasonScheduler),
+	fx.Invoke(app.NewRateLimitCleaner),
+	fx.Invoke(app.NewIdempotencyCleaner),
+	fx.Invoke(app.NewTokenCleaner),
+	fx.Invoke(app.NewEventDispatcher),
+	fx.Invoke(app.NewWebhookDispatcher),
+	fx.Invoke(app.NewNotificationListener),
+	fx.Invoke(app.NewAdminBootstrap),
 )
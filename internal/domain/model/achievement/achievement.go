@@ -0,0 +1,48 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Code string
+
+// описание достижения из каталога
+type Achievement struct {
+	Code        Code
+	Title       string
+	Description string
+}
+
+// полученное пользователем достижение
+type UserAchievement struct {
+	UserID     uuid.UUID
+	Code       Code
+	UnlockedAt time.Time
+}
+
+// PlayerStats - счётчики по завершённым играм пользователя
+type PlayerStats struct {
+	GamesPlayed      int
+	Wins             int
+	Draws            int
+	BotWins          int
+	CurrentWinStreak int
+	// наименьшее число ходов победителя среди побед пользователя, 0 - побед нет
+	FastestWin int
+}
+
+type AchievementRepository interface {
+	// Grant выдаёт достижение, возвращает false, если оно уже было получено
+	Grant(ctx context.Context, userID uuid.UUID, code Code) (bool, error)
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]UserAchievement, error)
+	// LockStats возвращает счётчики пользователя и блокирует их до конца транзакции;
+	// nil - счётчики ещё не заведены
+	LockStats(ctx context.Context, userID uuid.UUID) (*PlayerStats, error)
+	SaveStats(ctx context.Context, userID uuid.UUID, stats PlayerStats) error
+	// CountGames отмечает игры учтёнными в счётчиках пользователя
+	// и возвращает те, что ещё не были учтены
+	CountGames(ctx context.Context, userID uuid.UUID, gameIDs []uuid.UUID) ([]uuid.UUID, error)
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// SystemStats сводка по системе для администраторов
+type SystemStats struct {
+	Users          int
+	Admins         int
+	GamesWaiting   int
+	GamesPlaying   int
+	GamesFinished  int
+	ActiveSessions int
+	Tournaments    int
+	LockedLogins   int
+	CollectedAt    time.Time
+}
+
+type StatsRepository interface {
+	GetSystemStats(ctx context.Context) (SystemStats, error)
+}
@@ -2,14 +2,32 @@ package model
 
 import (
 	"context"
+	"time"
+
 	dto "tic-tac-toe/internal/storage/postgres/dto"
 
 	"github.com/google/uuid"
 )
 
+// данные клиента, с которого выполнен вход
+type Client struct {
+	UserAgent string
+	IP        string
+}
+
 type TokenRepository interface {
 	Save(ctx context.Context, token dto.RefreshToken) error
 	FindByHash(ctx context.Context, hash string) (dto.RefreshToken, error)
 	DeleteByHash(ctx context.Context, hash string) error
 	DeleteAllByUser(ctx context.Context, userID uuid.UUID) error
+	// ListByUser возвращает действующие (не ротированные) refresh токены пользователя
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]dto.RefreshToken, error)
+	// DeleteFamily удаляет все токены сессии, false - сессия не найдена
+	DeleteFamily(ctx context.Context, userID, familyID uuid.UUID) (bool, error)
+	// Rotate помечает токен oldHash ротированным и сохраняет next одной транзакцией;
+	// false - токен уже был ротирован (повторное использование)
+	Rotate(ctx context.Context, oldHash string, next dto.RefreshToken) (bool, error)
+	// DeleteExpired удаляет токены сессий, все токены которых истекли до before:
+	// ротированные токены живой сессии остаются для обнаружения повторного использования
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// по какому признаку считаются неудачные попытки входа
+type AttemptScope string
+
+const (
+	AttemptScopeLogin AttemptScope = "login"
+	AttemptScopeIP    AttemptScope = "ip"
+)
+
+type LoginAttempt struct {
+	Scope         AttemptScope
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	// до этого момента попытки входа отклоняются без проверки пароля
+	LockedUntil *time.Time
+}
+
+type LoginAttemptRepository interface {
+	// Get возвращает nil, если неудачных попыток не было
+	Get(ctx context.Context, scope AttemptScope, key string) (*LoginAttempt, error)
+	// RecordFailure атомарно увеличивает счётчик; если последняя неудача
+	// была раньше window, счёт начинается заново
+	RecordFailure(ctx context.Context, scope AttemptScope, key string, window time.Duration) (LoginAttempt, error)
+	// Lock блокирует попытки до until (существующая более долгая блокировка сохраняется)
+	Lock(ctx context.Context, scope AttemptScope, key string, until time.Time) error
+	// Reset сбрасывает счётчик и блокировку, false - записи не было
+	Reset(ctx context.Context, scope AttemptScope, key string) (bool, error)
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// токен сброса пароля; как и refresh токены, хранится только хэш
+type PasswordResetToken struct {
+	TokenHash string
+	UserID    uuid.UUID
+	ExpiresAt time.Time
+}
+
+type PasswordResetRepository interface {
+	Save(ctx context.Context, token PasswordResetToken) error
+	// Consume одноразово погашает действующий токен и возвращает его владельца;
+	// false - токен не найден, уже использован или истёк
+	Consume(ctx context.Context, hash string) (uuid.UUID, bool, error)
+	// DeleteByUser удаляет все токены сброса пользователя
+	DeleteByUser(ctx context.Context, userID uuid.UUID) error
+}
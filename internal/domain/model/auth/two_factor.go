@@ -0,0 +1,40 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// настройки TOTP пользователя; до подтверждения первым кодом EnabledAt == nil
+type TwoFactor struct {
+	UserID    uuid.UUID
+	Secret    string
+	EnabledAt *time.Time
+	// последний принятый шаг TOTP: один код нельзя использовать дважды
+	LastUsedStep int64
+}
+
+func (t TwoFactor) Enabled() bool {
+	return t.EnabledAt != nil
+}
+
+type TwoFactorRepository interface {
+	// Get возвращает nil, если 2FA не настраивалась
+	Get(ctx context.Context, userID uuid.UUID) (*TwoFactor, error)
+	// SaveSecret сохраняет новый секрет неподтверждённой настройки;
+	// false - 2FA уже включена
+	SaveSecret(ctx context.Context, userID uuid.UUID, secret string) (bool, error)
+	// Enable включает 2FA и заменяет коды восстановления (хэши) одной транзакцией
+	Enable(ctx context.Context, userID uuid.UUID, step int64, recoveryHashes []string) error
+	// Disable удаляет настройку и коды восстановления
+	Disable(ctx context.Context, userID uuid.UUID) error
+	// UseStep принимает шаг TOTP, если он больше последнего принятого
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	// UseRecoveryCode одноразово гасит код восстановления
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string) (bool, error)
+	// UseChallenge одноразово гасит challenge токен входа по его jti;
+	// запись хранится до истечения токена
+	UseChallenge(ctx context.Context, userID uuid.UUID, jti string, expiresAt time.Time) (bool, error)
+}
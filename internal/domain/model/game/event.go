@@ -0,0 +1,128 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// тип доменного события игры; значение - имя события во внешних интеграциях (вебхуки)
+type EventType string
+
+const (
+	EventGameCreated  EventType = "game.created"
+	EventPlayerJoined EventType = "game.joined"
+	EventMoveMade     EventType = "move.made"
+	EventGameFinished EventType = "game.finished"
+)
+
+var EventTypes = []EventType{EventGameCreated, EventPlayerJoined, EventMoveMade, EventGameFinished}
+
+// доменное событие игры; сохраняется в outbox в одной транзакции с игрой
+// и доставляется подписчикам диспетчером событий
+type Event struct {
+	// номер события в outbox; заполняется при чтении из outbox
+	ID     int64
+	Type   EventType
+	GameID uuid.UUID
+	// игрок, совершивший действие; nil - системное событие (турнирная пара)
+	PlayerID   *uuid.UUID
+	PlayerX    uuid.UUID
+	PlayerO    *uuid.UUID
+	Status     GameStatus
+	Field      [][]int
+	Winner     *uuid.UUID
+	SeasonID   *uuid.UUID
+	OccurredAt time.Time
+}
+
+// GameCreated - создана игра; by - создатель, nil для турнирной пары
+func GameCreated(game Game, by *uuid.UUID) Event {
+	return newEvent(EventGameCreated, game, by)
+}
+
+// PlayerJoined - к открытой игре присоединился второй игрок
+func PlayerJoined(game Game, player uuid.UUID) Event {
+	return newEvent(EventPlayerJoined, game, &player)
+}
+
+// MoveMade - игрок сделал ход; в игре с ботом состояние включает ответ бота
+func MoveMade(game Game, player uuid.UUID) Event {
+	return newEvent(EventMoveMade, game, &player)
+}
+
+// GameFinished - игра завершена ходом mover
+func GameFinished(game Game, mover uuid.UUID) Event {
+	return newEvent(EventGameFinished, game, &mover)
+}
+
+// newEvent фиксирует состояние игры на момент события
+func newEvent(eventType EventType, game Game, playerID *uuid.UUID) Event {
+	return Event{
+		Type:       eventType,
+		GameID:     game.UUID,
+		PlayerID:   playerID,
+		PlayerX:    game.PlayerX,
+		PlayerO:    game.PlayerO,
+		Status:     game.Status,
+		Field:      game.Field.Field,
+		Winner:     game.Winner(),
+		SeasonID:   game.SeasonID,
+		OccurredAt: time.Now(),
+	}
+}
+
+// Players - участники игры
+func (e Event) Players() []uuid.UUID {
+	players := []uuid.UUID{e.PlayerX}
+	if e.PlayerO != nil {
+		players = append(players, *e.PlayerO)
+	}
+	return players
+}
+
+// Game восстанавливает игру на момент события (без символов и очерёдности хода)
+func (e Event) Game() Game {
+	return Game{
+		UUID:     e.GameID,
+		Field:    &GameField{Field: e.Field},
+		Status:   e.Status,
+		PlayerX:  e.PlayerX,
+		PlayerO:  e.PlayerO,
+		SeasonID: e.SeasonID,
+	}
+}
+
+// состояние события в outbox
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"
+	OutboxProcessed OutboxStatus = "processed"
+	// попытки исчерпаны: событие больше не выдаётся и хранится для разбора
+	OutboxDead OutboxStatus = "dead"
+)
+
+// событие в outbox вместе с состоянием обработки
+type OutboxRecord struct {
+	Event    Event
+	Attempts int
+	// подписчики, уже обработавшие событие
+	Done []string
+}
+
+type OutboxRepository interface {
+	// Claim забирает до limit необработанных событий, время которых пришло (по порядку id),
+	// и откладывает их на lease, чтобы их не взял другой инстанс
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]OutboxRecord, error)
+	// Complete отмечает событие обработанным всеми подписчиками
+	Complete(ctx context.Context, id int64, now time.Time) error
+	// Retry сохраняет частичный результат: кто обработал событие, ошибку и время следующей попытки
+	Retry(ctx context.Context, record OutboxRecord, lastError string, next time.Time) error
+	// DeadLetter сохраняет частичный результат и переводит событие в OutboxDead:
+	// Claim его больше не выдаёт, DeleteProcessed не удаляет
+	DeadLetter(ctx context.Context, record OutboxRecord, lastError string) error
+	// DeleteProcessed удаляет события, обработанные раньше before
+	DeleteProcessed(ctx context.Context, before time.Time) (int64, error)
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,11 +11,12 @@ import (
 type GameStatus int
 
 const (
-	Waiting GameStatus = iota //ожидание
-	Playing                   //игра
-	WonX                      //победа X
-	WonO                      //победа O
-	Draw                      //ничья
+	Waiting   GameStatus = iota //ожидание
+	Playing                     //игра
+	WonX                        //победа X
+	WonO                        //победа O
+	Draw                        //ничья
+	Cancelled                   //отменена (модерация)
 )
 
 type Char string
@@ -37,6 +39,34 @@ type Game struct {
 	CurrentTurn uuid.UUID
 	Symbols     map[uuid.UUID]Char
 	DateCreate  time.Time
+	// сезон, в котором завершена игра
+	SeasonID *uuid.UUID
+	// версия сохранённой игры; 0 - игра ещё не сохранялась
+	Version int
+}
+
+// Finished сообщает, завершена ли игра
+func (g Game) Finished() bool {
+	return g.Status == WonX || g.Status == WonO || g.Status == Draw
+}
+
+// Winner возвращает победителя игры
+// nil - ничья, незавершённая игра или победа бота
+func (g Game) Winner() *uuid.UUID {
+	switch g.Status {
+	case WonX:
+		winner := g.PlayerX
+		return &winner
+	case WonO:
+		return g.PlayerO
+	default:
+		return nil
+	}
+}
+
+// WithBot сообщает, играется ли партия против бота
+func (g Game) WithBot() bool {
+	return g.PlayerO == nil && g.Status != Waiting && g.Status != Cancelled
 }
 
 type UserLeaders struct {
@@ -45,10 +75,47 @@ type UserLeaders struct {
 	WinRate string
 }
 
+// статистика личных встреч двух игроков
+type HeadToHead struct {
+	PlayerID     uuid.UUID
+	OpponentID   uuid.UUID
+	Total        int
+	PlayerWins   int
+	OpponentWins int
+	Draws        int
+	LastGames    []Game
+	Streak       Streak
+}
+
+// текущая серия побед подряд (Winner == nil, если последняя игра - ничья)
+type Streak struct {
+	Winner *uuid.UUID
+	Count  int
+}
+
 type GameRepository interface {
-	SaveGame(ctx context.Context, game Game) error
+	// SaveGame сохраняет игру и в той же транзакции записывает events в outbox.
+	// Игра обновляется, только если в БД всё ещё game.Version, иначе - *ConflictError
+	SaveGame(ctx context.Context, game Game, events ...Event) error
 	GetCurrentGame(ctx context.Context, uuid uuid.UUID) (Game, error)
 	GetAvailableGames(ctx context.Context) ([]Game, error)
 	GetComplitedGames(ctx context.Context, userID uuid.UUID) ([]Game, error)
-	GetLeaderBoard(ctx context.Context, count int) ([]UserLeaders, error)
+	GetLeaderBoard(ctx context.Context, seasonID uuid.UUID, count int) ([]UserLeaders, error)
+	GetGamesBetween(ctx context.Context, playerID, opponentID uuid.UUID) ([]Game, error)
+	GetFinishedGamesByUser(ctx context.Context, userID uuid.UUID) ([]Game, error)
+	// DeleteGame удаляет игру; false - игры не было
+	DeleteGame(ctx context.Context, id uuid.UUID) (bool, error)
+	// CancelWaitingGames отменяет открытые (Waiting) игры создателя, кроме турнирных;
+	// возвращает число отменённых
+	CancelWaitingGames(ctx context.Context, playerX uuid.UUID) (int64, error)
+}
+
+// ConflictError - игру изменили параллельно: версия в БД уже не Version
+type ConflictError struct {
+	GameID  uuid.UUID
+	Version int
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("game %s was modified concurrently (version %d is stale)", e.GameID, e.Version)
 }
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// сколько хранится ответ на запрос с ключом идемпотентности
+	TTL = 24 * time.Hour
+	// MaxKeyLength - максимальная длина заголовка Idempotency-Key
+	MaxKeyLength = 255
+)
+
+// Response - сохранённый ответ, который повторяется на запрос с тем же ключом
+type Response struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// Record - ключ идемпотентности пользователя
+type Record struct {
+	UserID uuid.UUID
+	Key    string
+	// хэш метода, пути и тела запроса: тот же ключ с другим запросом - ошибка клиента
+	RequestHash string
+	// nil - запрос с этим ключом ещё выполняется
+	Response  *Response
+	ExpiresAt time.Time
+}
+
+type Repository interface {
+	// Reserve занимает ключ под выполняемый запрос. Если ключ уже занят и не истёк,
+	// возвращает существующую запись и false
+	Reserve(ctx context.Context, record Record, now time.Time) (Record, bool, error)
+	// Complete сохраняет ответ на запрос
+	Complete(ctx context.Context, userID uuid.UUID, key string, response Response) error
+	// Release освобождает ключ: запрос не выполнен, и его можно повторить
+	Release(ctx context.Context, userID uuid.UUID, key string) error
+	// DeleteExpired удаляет ключи, истёкшие до before
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
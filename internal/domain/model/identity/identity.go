@@ -0,0 +1,36 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// внешняя учётная запись пользователя у OIDC-провайдера
+type Identity struct {
+	Issuer  string
+	Subject string
+	UserID  uuid.UUID
+}
+
+// незавершённый вход через OIDC (между редиректом к провайдеру и callback)
+type LoginState struct {
+	State        string
+	CodeVerifier string
+	Nonce        string
+	ExpiresAt    time.Time
+}
+
+type IdentityRepository interface {
+	// Find возвращает nil, если учётная запись не связана
+	Find(ctx context.Context, issuer, subject string) (*Identity, error)
+	// Create связывает учётную запись; false - она уже связана (одновременный вход)
+	Create(ctx context.Context, identity Identity) (bool, error)
+}
+
+type LoginStateRepository interface {
+	Save(ctx context.Context, state LoginState) error
+	// Consume одноразово забирает действующий state; nil - не найден или истёк
+	Consume(ctx context.Context, state string) (*LoginState, error)
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// вид санкции
+type SanctionKind string
+
+const (
+	SanctionBan  SanctionKind = "ban"
+	SanctionMute SanctionKind = "mute"
+)
+
+// действие модератора в журнале
+type Action string
+
+const (
+	ActionBan    Action = "ban"
+	ActionUnban  Action = "unban"
+	ActionMute   Action = "mute"
+	ActionUnmute Action = "unmute"
+)
+
+// действующая санкция против пользователя
+type Sanction struct {
+	UserID    uuid.UUID
+	Kind      SanctionKind
+	Reason    string
+	CreatedBy uuid.UUID
+	CreatedAt time.Time
+	// nil - бессрочно
+	ExpiresAt *time.Time
+}
+
+// Active сообщает, действует ли санкция в момент now
+func (s Sanction) Active(now time.Time) bool {
+	return s.ExpiresAt == nil || now.Before(*s.ExpiresAt)
+}
+
+// запись журнала модерации
+type LogEntry struct {
+	UUID      uuid.UUID
+	ActorID   *uuid.UUID
+	TargetID  uuid.UUID
+	Action    Action
+	Reason    string
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+}
+
+type ModerationRepository interface {
+	// Apply заменяет санкцию того же вида и пишет запись журнала в одной транзакции
+	Apply(ctx context.Context, sanction Sanction, entry LogEntry) error
+	// Lift снимает действующую санкцию и пишет запись журнала; false - санкции не было
+	Lift(ctx context.Context, userID uuid.UUID, kind SanctionKind, entry LogEntry) (bool, error)
+	// Active возвращает действующую в момент now санкцию; nil - её нет
+	Active(ctx context.Context, userID uuid.UUID, kind SanctionKind, now time.Time) (*Sanction, error)
+	// GetLog возвращает записи журнала, новые первыми; target == nil - по всем пользователям
+	GetLog(ctx context.Context, target *uuid.UUID, limit int) ([]LogEntry, error)
+}
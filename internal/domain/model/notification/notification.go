@@ -0,0 +1,53 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Kind string
+
+const (
+	// к открытой игре присоединился соперник
+	KindOpponentJoined Kind = "opponent_joined"
+	// соперник сделал ход, теперь ход пользователя
+	KindOpponentMoved Kind = "opponent_moved"
+	KindGameFinished  Kind = "game_finished"
+	// начался тур турнира; без game_id - пропуск тура
+	KindRoundStarted  Kind = "tournament_round_started"
+	KindFriendRequest Kind = "friend_request"
+)
+
+type Notification struct {
+	UUID   uuid.UUID
+	UserID uuid.UUID
+	Kind   Kind
+	// связанные объекты: game_id, tournament_id, from и т.п.
+	Data      map[string]string
+	CreatedAt time.Time
+	ReadAt    *time.Time
+}
+
+func (n Notification) Read() bool {
+	return n.ReadAt != nil
+}
+
+type NotificationRepository interface {
+	Save(ctx context.Context, notification Notification) error
+	// List возвращает уведомления от новых к старым; unreadOnly - только непрочитанные
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]Notification, error)
+	// MarkRead отмечает прочитанными уведомления ids пользователя (пустой ids - все)
+	// и возвращает количество отмеченных
+	MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, at time.Time) (int64, error)
+}
+
+// Broker рассылает новые уведомления всем инстансам приложения
+type Broker interface {
+	// Publish отправляет уведомление; внутри транзакции - после её фиксации
+	Publish(ctx context.Context, notification Notification) error
+	// Listen передаёт в handler уведомления всех инстансов, пока не отменён ctx
+	// или не оборвалось соединение
+	Listen(ctx context.Context, handler func(Notification)) error
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// присутствие пользователя; LastSeen нулевой - активности не было
+type Presence struct {
+	UserID   uuid.UUID
+	Login    string
+	LastSeen time.Time
+	Online   bool
+	// в сети и участвует в идущей игре, в которой ходили позже onlineSince
+	InGame bool
+}
+
+type PresenceRepository interface {
+	// Touch отмечает активность пользователя в момент now
+	Touch(ctx context.Context, userID uuid.UUID, now time.Time) error
+	// Get возвращает присутствие; online - активность позже onlineSince
+	Get(ctx context.Context, userID uuid.UUID, onlineSince time.Time) (Presence, error)
+	// ListOnline возвращает пользователей с активностью позже onlineSince
+	ListOnline(ctx context.Context, onlineSince time.Time, limit int) ([]Presence, error)
+}
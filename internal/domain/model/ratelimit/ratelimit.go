@@ -0,0 +1,74 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// самый длинный допустимый период: бакет, не менявшийся дольше, снова полон
+// и может быть удалён
+const MaxPeriod = 24 * time.Hour
+
+// Limit - не больше Requests запросов за Period (token bucket:
+// ёмкость Requests, пополнение Requests/Period)
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit разбирает лимит вида "20/1m"
+func ParseLimit(value string) (Limit, error) {
+	requests, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("лимит %q: ожидается формат <запросы>/<период>", value)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("лимит %q: некорректное число запросов", value)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || d <= 0 || d > MaxPeriod {
+		return Limit{}, fmt.Errorf("лимит %q: некорректный период", value)
+	}
+	return Limit{Requests: n, Period: d}, nil
+}
+
+// Bucket - состояние бакета на момент UpdatedAt
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// FullBucket - бакет нового ключа
+func (l Limit) FullBucket(now time.Time) Bucket {
+	return Bucket{Tokens: float64(l.Requests), UpdatedAt: now}
+}
+
+// Take пополняет бакет на прошедшее время и забирает один токен;
+// если токена нет, возвращает время до его появления
+func (l Limit) Take(b Bucket, now time.Time) (Bucket, bool, time.Duration) {
+	rate := float64(l.Requests) / l.Period.Seconds()
+	elapsed := now.Sub(b.UpdatedAt).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	tokens := math.Min(float64(l.Requests), b.Tokens+elapsed*rate)
+	next := Bucket{Tokens: tokens, UpdatedAt: now}
+	if tokens >= 1 {
+		next.Tokens--
+		return next, true, 0
+	}
+	wait := time.Duration((1 - tokens) / rate * float64(time.Second))
+	return next, false, wait
+}
+
+type Limiter interface {
+	// Allow расходует токен ключа key; при отказе возвращает время до повтора
+	Allow(ctx context.Context, key string, limit Limit) (bool, time.Duration, error)
+	// Cleanup удаляет бакеты, не менявшиеся с before
+	Cleanup(ctx context.Context, before time.Time) error
+}
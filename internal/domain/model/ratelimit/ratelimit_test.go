@@ -0,0 +1,69 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimitTakeBurstAndRefill(t *testing.T) {
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket := limit.FullBucket(now)
+
+	// полный бакет пропускает Requests запросов подряд
+	for i := range limit.Requests {
+		var allowed bool
+		bucket, allowed, _ = limit.Take(bucket, now)
+		if !allowed {
+			t.Fatalf("request %d of burst rejected", i+1)
+		}
+	}
+	bucket, allowed, wait := limit.Take(bucket, now)
+	if allowed || wait != time.Second {
+		t.Fatalf("request after burst: allowed = %v, wait = %v, want rejected with 1s", allowed, wait)
+	}
+
+	// за полсекунды накопилась половина токена
+	bucket, allowed, wait = limit.Take(bucket, now.Add(500*time.Millisecond))
+	if allowed || wait != 500*time.Millisecond {
+		t.Fatalf("after 0.5s: allowed = %v, wait = %v, want rejected with 0.5s", allowed, wait)
+	}
+	bucket, allowed, _ = limit.Take(bucket, now.Add(time.Second))
+	if !allowed {
+		t.Fatal("after 1s: request rejected, want one refilled token")
+	}
+
+	// пополнение не превышает ёмкость
+	bucket, _, _ = limit.Take(bucket, now.Add(time.Hour))
+	if bucket.Tokens != float64(limit.Requests-1) {
+		t.Errorf("tokens after long idle = %v, want %d", bucket.Tokens, limit.Requests-1)
+	}
+
+	// время назад не добавляет токенов
+	stale := Bucket{Tokens: 0, UpdatedAt: now}
+	if _, allowed, _ := limit.Take(stale, now.Add(-time.Minute)); allowed {
+		t.Error("clock going back refilled the bucket")
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Limit
+		wantErr bool
+	}{
+		{value: "20/1m", want: Limit{Requests: 20, Period: time.Minute}},
+		{value: " 5 / 10s ", want: Limit{Requests: 5, Period: 10 * time.Second}},
+		{value: "20", wantErr: true},
+		{value: "0/1m", wantErr: true},
+		{value: "x/1m", wantErr: true},
+		{value: "20/0s", wantErr: true},
+		{value: "20/48h", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLimit(%q) = %+v, %v; want %+v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
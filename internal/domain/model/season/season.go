@@ -0,0 +1,40 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+
+	"github.com/google/uuid"
+)
+
+type Status int
+
+const (
+	Active   Status = iota //текущий сезон
+	Archived               //завершён, таблица в архиве
+)
+
+type Season struct {
+	UUID     uuid.UUID
+	Name     string
+	StartsAt time.Time
+	EndsAt   time.Time
+	Status   Status
+}
+
+type SeasonRepository interface {
+	// GetActive возвращает nil, если активного сезона нет
+	GetActive(ctx context.Context) (*Season, error)
+	GetSeason(ctx context.Context, id uuid.UUID) (Season, error)
+	ListSeasons(ctx context.Context) ([]Season, error)
+	// CreateActive создаёт активный сезон, если другого активного ещё нет
+	CreateActive(ctx context.Context, season Season) error
+	// Archive закрывает сезон и открывает следующий; возвращает false, если сезон
+	// уже закрыт. В транзакции строка сезона остаётся заблокированной до её конца
+	Archive(ctx context.Context, seasonID uuid.UUID, next Season) (bool, error)
+	// SaveStandings сохраняет итоговую таблицу закрытого сезона
+	SaveStandings(ctx context.Context, seasonID uuid.UUID, standings []gameModel.UserLeaders) error
+	GetArchivedLeaderBoard(ctx context.Context, seasonID uuid.UUID, count int) ([]gameModel.UserLeaders, error)
+}
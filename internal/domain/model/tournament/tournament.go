@@ -0,0 +1,110 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Format string
+
+const (
+	RoundRobin        Format = "round_robin"
+	SingleElimination Format = "single_elimination"
+	Swiss             Format = "swiss"
+)
+
+type Status int
+
+const (
+	Registration Status = iota //набор участников
+	Running                    //идёт
+	Finished                   //завершён
+)
+
+type Result int
+
+const (
+	Pending Result = iota //игра не сыграна
+	WonX                  //победа X
+	WonO                  //победа O
+	Draw                  //ничья
+	Bye                   //пропуск тура без соперника
+)
+
+type Tournament struct {
+	UUID         uuid.UUID
+	Name         string
+	Format       Format
+	Status       Status
+	OwnerID      uuid.UUID
+	CurrentRound int
+	// общее число туров, 0 - определяется при старте
+	Rounds    int
+	CreatedAt time.Time
+}
+
+type Participant struct {
+	TournamentID uuid.UUID
+	UserID       uuid.UUID
+	Seed         int
+}
+
+// пара соперников в туре; PlayerO == nil означает пропуск тура (bye)
+type Pairing struct {
+	UUID         uuid.UUID
+	TournamentID uuid.UUID
+	Round        int
+	Board        int
+	PlayerX      uuid.UUID
+	PlayerO      *uuid.UUID
+	GameID       *uuid.UUID
+	Result       Result
+}
+
+// Winner возвращает победителя пары, nil - ничья или результата ещё нет
+func (p Pairing) Winner() *uuid.UUID {
+	switch p.Result {
+	case WonX, Bye:
+		winner := p.PlayerX
+		return &winner
+	case WonO:
+		return p.PlayerO
+	default:
+		return nil
+	}
+}
+
+// строка турнирной таблицы
+type Standing struct {
+	UserID uuid.UUID
+	Played int
+	Wins   int
+	Draws  int
+	Losses int
+	Byes   int
+	Points float64
+	// коэффициент Бухгольца (только для швейцарской системы)
+	Buchholz float64
+}
+
+type TournamentRepository interface {
+	CreateTournament(ctx context.Context, t Tournament) error
+	GetTournament(ctx context.Context, id uuid.UUID) (Tournament, error)
+	ListTournaments(ctx context.Context) ([]Tournament, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status Status) error
+	UpdateRounds(ctx context.Context, id uuid.UUID, rounds int) error
+	// AdvanceRound переводит турнир из тура from в тур to,
+	// возвращает false, если тур уже был переключён другим запросом
+	AdvanceRound(ctx context.Context, id uuid.UUID, from, to int) (bool, error)
+
+	AddParticipant(ctx context.Context, p Participant) error
+	GetParticipants(ctx context.Context, tournamentID uuid.UUID) ([]Participant, error)
+
+	SavePairings(ctx context.Context, pairings []Pairing) error
+	GetPairings(ctx context.Context, tournamentID uuid.UUID) ([]Pairing, error)
+	// FindPairingByGame возвращает nil, если игра не относится к турниру
+	FindPairingByGame(ctx context.Context, gameID uuid.UUID) (*Pairing, error)
+	UpdatePairing(ctx context.Context, p Pairing) error
+}
@@ -0,0 +1,12 @@
+package model
+
+import "context"
+
+// Manager выполняет операции нескольких репозиториев в одной транзакции
+type Manager interface {
+	// WithinTx вызывает fn с контекстом, через который репозитории работают в общей транзакции:
+	// ошибка fn откатывает все изменения, иначе они фиксируются вместе.
+	// Вложенный вызов выполняется в точке сохранения внешней транзакции.
+	// Транзакция - одно соединение, поэтому внутри fn запросы нельзя выполнять параллельно
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FriendshipStatus string
+
+const (
+	FriendshipPending  FriendshipStatus = "pending"
+	FriendshipAccepted FriendshipStatus = "accepted"
+)
+
+// связь пары пользователей: заявка (pending) или дружба (accepted)
+type Friendship struct {
+	RequesterID uuid.UUID
+	AddresseeID uuid.UUID
+	Status      FriendshipStatus
+	CreatedAt   time.Time
+	AcceptedAt  *time.Time
+}
+
+// друг пользователя
+type Friend struct {
+	UserID uuid.UUID
+	Login  string
+	Since  time.Time
+}
+
+// заявка в друзья; Incoming - заявка к пользователю, иначе от него
+type FriendRequest struct {
+	UserID    uuid.UUID
+	Login     string
+	Incoming  bool
+	CreatedAt time.Time
+}
+
+// пользователь из чёрного списка
+type BlockedUser struct {
+	UserID    uuid.UUID
+	Login     string
+	CreatedAt time.Time
+}
+
+type FriendRepository interface {
+	// GetFriendship ищет связь пары в любом направлении; nil - её нет
+	GetFriendship(ctx context.Context, a, b uuid.UUID) (*Friendship, error)
+	// CreateRequest создаёт заявку; false - у пары уже есть заявка или дружба
+	CreateRequest(ctx context.Context, requester, addressee uuid.UUID) (bool, error)
+	// Accept принимает заявку requester -> addressee; false - такой заявки нет
+	Accept(ctx context.Context, requester, addressee uuid.UUID) (bool, error)
+	// DeleteFriendship удаляет связь пары в любом направлении; false - её не было
+	DeleteFriendship(ctx context.Context, a, b uuid.UUID) (bool, error)
+	ListFriends(ctx context.Context, userID uuid.UUID) ([]Friend, error)
+	ListRequests(ctx context.Context, userID uuid.UUID) ([]FriendRequest, error)
+	FriendIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+
+	// Block добавляет в чёрный список и удаляет связь пары; false - уже заблокирован
+	Block(ctx context.Context, blocker, blocked uuid.UUID) (bool, error)
+	Unblock(ctx context.Context, blocker, blocked uuid.UUID) (bool, error)
+	ListBlocked(ctx context.Context, blocker uuid.UUID) ([]BlockedUser, error)
+	// BlockedIDs возвращает тех, кого userID заблокировал, и тех, кто заблокировал его
+	BlockedIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	// IsBlockedBetween сообщает, заблокировал ли кто-то из пары другого
+	IsBlockedBetween(ctx context.Context, a, b uuid.UUID) (bool, error)
+}
@@ -2,18 +2,53 @@ package model
 
 import (
 	"context"
+	"errors"
+	"slices"
 
 	"github.com/google/uuid"
 )
 
+// Role роль пользователя для разграничения доступа
+type Role string
+
+const (
+	RoleAdmin Role = "admin"
+)
+
+// Valid сообщает, известна ли роль системе
+func (r Role) Valid() bool {
+	return r == RoleAdmin
+}
+
 type User struct {
 	UUID     uuid.UUID
 	Login    string
 	Password string
+	Roles    []Role
 }
 
+// HasRole проверяет наличие роли у пользователя
+func (u User) HasRole(role Role) bool {
+	return slices.Contains(u.Roles, role)
+}
+
+// RoleNames возвращает роли в виде строк (для клеймов токена)
+func (u User) RoleNames() []string {
+	names := make([]string, 0, len(u.Roles))
+	for _, r := range u.Roles {
+		names = append(names, string(r))
+	}
+	return names
+}
+
+// ErrUserNotFound - пользователя нет в хранилище
+var ErrUserNotFound = errors.New("user not found")
+
 type UserRepository interface {
 	CreateUser(ctx context.Context, user User) error
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
 	GetUserByLogin(ctx context.Context, login string) (*User, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	AddRole(ctx context.Context, id uuid.UUID, role Role) error
+	RemoveRole(ctx context.Context, id uuid.UUID, role Role) error
 }
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+
+	"github.com/google/uuid"
+)
+
+type Webhook struct {
+	UUID    uuid.UUID
+	OwnerID uuid.UUID
+	URL     string
+	// ключ подписи HMAC-SHA256, показывается владельцу только при создании
+	Secret string
+	Events []gameModel.EventType
+	// все игры (администратор); иначе только игры владельца
+	AllGames  bool
+	CreatedAt time.Time
+}
+
+func (w Webhook) Subscribed(event gameModel.EventType) bool {
+	return slices.Contains(w.Events, event)
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// попытки исчерпаны
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// доставка события одному вебхуку
+type Delivery struct {
+	UUID      uuid.UUID
+	WebhookID uuid.UUID
+	EventID   int64
+	Event     gameModel.EventType
+	// JSON события (поле data тела запроса)
+	Payload       []byte
+	Status        DeliveryStatus
+	Attempts      int
+	ResponseCode  *int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	DeliveredAt   *time.Time
+	// адрес и ключ вебхука; заполняются в ClaimDue
+	URL    string
+	Secret string
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook Webhook) error
+	ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]Webhook, error)
+	CountByOwner(ctx context.Context, ownerID uuid.UUID) (int, error)
+	// Delete удаляет вебхук владельца; false - такого нет
+	Delete(ctx context.Context, id, ownerID uuid.UUID) (bool, error)
+	// ListDeliveries - журнал доставки вебхука владельца, от новых к старым
+	ListDeliveries(ctx context.Context, id, ownerID uuid.UUID, limit int) ([]Delivery, error)
+
+	// Enqueue создаёт доставки события всем подписанным вебхукам;
+	// повторный вызов для того же события доставок не дублирует
+	Enqueue(ctx context.Context, event gameModel.Event, now time.Time) error
+	// ClaimDue забирает до limit доставок, время которых пришло, и откладывает
+	// их на lease, чтобы их не взял другой инстанс, пока идёт отправка
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Delivery, error)
+	// SaveAttempt сохраняет результат попытки: статус, счётчик, код ответа, ошибку, время следующей попытки
+	SaveAttempt(ctx context.Context, delivery Delivery) error
+}
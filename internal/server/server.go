@@ -2,88 +2,291 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"strings"
 
 	"tic-tac-toe/internal/config"
+	idempotency "tic-tac-toe/internal/domain/model/idempotency"
+	ratelimit "tic-tac-toe/internal/domain/model/ratelimit"
+	userModel "tic-tac-toe/internal/domain/model/user"
 	jwt "tic-tac-toe/internal/service/jwt_service"
+	moderation "tic-tac-toe/internal/service/moderation_service"
+	presence "tic-tac-toe/internal/service/presence_service"
 	"tic-tac-toe/internal/web/handler"
 	"tic-tac-toe/internal/web/middleware"
 )
 
 type Server struct {
-	config  *config.Config
-	gameAPI *handler.GameAPI
-	userAPI *handler.AuthAPI
-	jwt     jwt.JwtProvider
+	config        *config.Config
+	gameAPI       *handler.GameAPI
+	userAPI       *handler.AuthAPI
+	tournamentAPI *handler.TournamentAPI
+	seasonAPI     *handler.SeasonAPI
+	oidcAPI       *handler.OIDCAPI
+	adminAPI      *handler.AdminAPI
+	moderationAPI *handler.ModerationAPI
+	friendAPI     *handler.FriendAPI
+	presenceAPI   *handler.PresenceAPI
+	notification  *handler.NotificationAPI
+	webhookAPI    *handler.WebhookAPI
+	presence      presence.PresenceService
+	moderation    moderation.ModerationService
+	jwt           jwt.JwtProvider
+	limiter       ratelimit.Limiter
+	idempotency   idempotency.Repository
+	cookies       middleware.CookieConfig
 }
 
-func NewServer(conf *config.Config, api *handler.GameAPI, user *handler.AuthAPI, jwt jwt.JwtProvider) *Server {
+func NewServer(conf *config.Config, api *handler.GameAPI, user *handler.AuthAPI, tournament *handler.TournamentAPI, season *handler.SeasonAPI, oidc *handler.OIDCAPI, admin *handler.AdminAPI, moderationAPI *handler.ModerationAPI, friend *handler.FriendAPI, presenceAPI *handler.PresenceAPI,
+	notification *handler.NotificationAPI, webhook *handler.WebhookAPI, moderation moderation.ModerationService, presence presence.PresenceService, jwt jwt.JwtProvider, limiter ratelimit.Limiter,
+	idempotencyKeys idempotency.Repository, cookies middleware.CookieConfig) *Server {
 	return &Server{
-		config:  conf,
-		gameAPI: api,
-		userAPI: user,
-		jwt:     jwt,
+		config:        conf,
+		gameAPI:       api,
+		userAPI:       user,
+		tournamentAPI: tournament,
+		seasonAPI:     season,
+		oidcAPI:       oidc,
+		adminAPI:      admin,
+		moderationAPI: moderationAPI,
+		friendAPI:     friend,
+		presenceAPI:   presenceAPI,
+		notification:  notification,
+		webhookAPI:    webhook,
+		presence:      presence,
+		moderation:    moderation,
+		jwt:           jwt,
+		limiter:       limiter,
+		idempotency:   idempotencyKeys,
+		cookies:       cookies,
 	}
 }
 
 func (s *Server) Start() error {
-	// middleware авторизации
-	requireAuth := middleware.MiddlewareAuth(s.jwt)
+	// middleware авторизации; заодно отмечает присутствие пользователя
+	requireAuth := func(next http.HandlerFunc) http.HandlerFunc {
+		return middleware.Chain(next,
+			middleware.TrackPresence(s.presence),
+			middleware.MiddlewareAuth(s.jwt, s.cookies, s.moderation),
+		)
+	}
+	// повтор запроса с тем же Idempotency-Key (создание игры, присоединение, ход)
+	idempotent := middleware.Idempotency(s.idempotency)
+
+	// ограничение частоты запросов по группам маршрутов
+	limits := s.config.RateLimit
+	authLimit, err := s.rateLimit("auth", limits.Auth)
+	if err != nil {
+		return err
+	}
+	createLimit, err := s.rateLimit("game_create", limits.GameCreate)
+	if err != nil {
+		return err
+	}
+	moveLimit, err := s.rateLimit("moves", limits.Moves)
+	if err != nil {
+		return err
+	}
+	readLimit, err := s.rateLimit("reads", limits.Reads)
+	if err != nil {
+		return err
+	}
 
 	// без авторизации
 	userRegistrationHandler := middleware.Chain(
 		s.userAPI.HandlerRegistration,
 		middleware.EnableCORS,
 		middleware.ContentTypeJSON,
+		authLimit,
 	)
 	userAuthHandler := middleware.Chain(
 		s.userAPI.HandlerAuthorization,
 		middleware.EnableCORS,
 		middleware.ContentTypeJSON,
+		authLimit,
 	)
 	refreshTokenHandler := middleware.Chain(
 		s.userAPI.HandlerRefreshAccessToken,
 		middleware.EnableCORS,
 		middleware.ContentTypeJSON,
+		authLimit,
+	)
+
+	logoutHandler := middleware.Chain(
+		s.userAPI.HandlerLogout,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		authLimit,
+	)
+	twoFactorLoginHandler := middleware.Chain(
+		s.userAPI.HandlerTwoFactorLogin,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		authLimit,
+	)
+	oidcLoginHandler := middleware.Chain(
+		s.oidcAPI.HandlerLogin,
+		middleware.EnableCORS,
+		authLimit,
+	)
+	oidcCallbackHandler := middleware.Chain(
+		s.oidcAPI.HandlerCallback,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		authLimit,
+	)
+	passwordResetHandler := middleware.Chain(
+		s.userAPI.HandlerRequestPasswordReset,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		authLimit,
+	)
+	passwordResetConfirmHandler := middleware.Chain(
+		s.userAPI.HandlerConfirmPasswordReset,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		authLimit,
+	)
+	jwksHandler := middleware.Chain(
+		s.userAPI.HandlerJWKS,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		readLimit,
+	)
+
+	// администрирование: роль admin проверяется после авторизации
+	requireAdmin := middleware.RequireRole(string(userModel.RoleAdmin))
+	unlockLoginHandler := middleware.Chain(
+		s.userAPI.HandlerUnlockLogin,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		requireAdmin,
+		requireAuth,
+	)
+	adminStatsHandler := middleware.Chain(
+		s.adminAPI.HandlerStats,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		requireAdmin,
+		requireAuth,
+	)
+	adminGameHandler := middleware.Chain(
+		s.adminAPI.HandlerDeleteGame,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		requireAdmin,
+		requireAuth,
+	)
+	adminUserHandler := middleware.Chain(
+		s.adminUsersHandler,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		requireAdmin,
+		requireAuth,
+	)
+
+	moderationLogHandler := middleware.Chain(
+		s.moderationAPI.HandlerLog,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		requireAdmin,
+		requireAuth,
 	)
 
 	// с авторизацией
+	logoutAllHandler := middleware.Chain(
+		s.userAPI.HandlerLogoutAll,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		authLimit,
+		requireAuth,
+	)
+	changePasswordHandler := middleware.Chain(
+		s.userAPI.HandlerChangePassword,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		authLimit,
+		requireAuth,
+	)
+	twoFactorSetupHandler := middleware.Chain(
+		s.userAPI.HandlerTwoFactorSetup,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		authLimit,
+		requireAuth,
+	)
+	twoFactorEnableHandler := middleware.Chain(
+		s.userAPI.HandlerTwoFactorEnable,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		authLimit,
+		requireAuth,
+	)
+	twoFactorDisableHandler := middleware.Chain(
+		s.userAPI.HandlerTwoFactorDisable,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		authLimit,
+		requireAuth,
+	)
+	sessionsHandler := middleware.Chain(
+		s.userAPI.HandlerGetSessions,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		readLimit,
+		requireAuth,
+	)
+	revokeSessionHandler := middleware.Chain(
+		s.userAPI.HandlerRevokeSession,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		authLimit,
+		requireAuth,
+	)
 	gameMainHandler := middleware.Chain(
 		s.mainHandler,
+		idempotent,
 		middleware.EnableCORS,
 		middleware.ContentTypeJSON,
+		middleware.ByMethod(readLimit, moveLimit),
 		requireAuth,
 	)
 	gameNewHandler := middleware.Chain(
 		s.gameAPI.HandlerNewGame,
+		idempotent,
 		middleware.EnableCORS,
 		middleware.ContentTypeJSON,
+		createLimit,
 		requireAuth,
 	)
 	gamesListHandler := middleware.Chain(
 		s.gameAPI.HandlerGetAvailableGames,
 		middleware.EnableCORS,
 		middleware.ContentTypeJSON,
+		readLimit,
 		requireAuth,
 	)
 	userInfoHandler := middleware.Chain(
-		s.userAPI.HandlerGetUserUUID,
+		s.userHandler,
 		middleware.EnableCORS,
 		middleware.ContentTypeJSON,
+		readLimit,
 		requireAuth,
 	)
 	getUserHandler := middleware.Chain(
 		s.userAPI.HandlerGetCurrentUser,
 		middleware.EnableCORS,
 		middleware.ContentTypeJSON,
+		readLimit,
 		requireAuth,
 	)
 	getHistoryHandler := middleware.Chain(
 		s.gameAPI.HandlerGetComplitedGames,
 		middleware.EnableCORS,
 		middleware.ContentTypeJSON,
+		readLimit,
 		requireAuth,
 	)
 
@@ -91,6 +294,124 @@ func (s *Server) Start() error {
 		s.gameAPI.HandlerGetLeaderBoard,
 		middleware.EnableCORS,
 		middleware.ContentTypeJSON,
+		readLimit,
+		requireAuth,
+	)
+
+	tournamentNewHandler := middleware.Chain(
+		s.tournamentAPI.HandlerNewTournament,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		createLimit,
+		requireAuth,
+	)
+	tournamentListHandler := middleware.Chain(
+		s.tournamentAPI.HandlerGetTournaments,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		readLimit,
+		requireAuth,
+	)
+	tournamentMainHandler := middleware.Chain(
+		s.tournamentHandler,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		middleware.ByMethod(readLimit, createLimit),
+		requireAuth,
+	)
+	seasonsListHandler := middleware.Chain(
+		s.seasonAPI.HandlerGetSeasons,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		readLimit,
+		requireAuth,
+	)
+	seasonMainHandler := middleware.Chain(
+		s.seasonHandler,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		readLimit,
+		requireAuth,
+	)
+
+	friendsListHandler := middleware.Chain(
+		s.friendAPI.HandlerGetFriends,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		readLimit,
+		requireAuth,
+	)
+	friendsMainHandler := middleware.Chain(
+		s.friendsHandler,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		middleware.ByMethod(readLimit, createLimit),
+		requireAuth,
+	)
+	blocksListHandler := middleware.Chain(
+		s.friendAPI.HandlerGetBlocked,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		readLimit,
+		requireAuth,
+	)
+	blocksMainHandler := middleware.Chain(
+		s.friendAPI.HandlerBlock,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		createLimit,
+		requireAuth,
+	)
+
+	onlineUsersHandler := middleware.Chain(
+		s.presenceAPI.HandlerGetOnline,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		readLimit,
+		requireAuth,
+	)
+	heartbeatHandler := middleware.Chain(
+		s.presenceAPI.HandlerHeartbeat,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		readLimit,
+		requireAuth,
+	)
+
+	notificationsListHandler := middleware.Chain(
+		s.notification.HandlerGetNotifications,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		readLimit,
+		requireAuth,
+	)
+	notificationsReadHandler := middleware.Chain(
+		s.notification.HandlerMarkRead,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		readLimit,
+		requireAuth,
+	)
+	// поток без ContentTypeJSON: ответ в формате text/event-stream
+	notificationsStreamHandler := middleware.Chain(
+		s.notification.HandlerStream,
+		middleware.EnableCORS,
+		readLimit,
+		requireAuth,
+	)
+
+	webhooksListHandler := middleware.Chain(
+		s.webhooksHandler,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		middleware.ByMethod(readLimit, createLimit),
+		requireAuth,
+	)
+	webhooksMainHandler := middleware.Chain(
+		s.webhookHandler,
+		middleware.EnableCORS,
+		middleware.ContentTypeJSON,
+		middleware.ByMethod(readLimit, createLimit),
 		requireAuth,
 	)
 
@@ -102,11 +423,61 @@ func (s *Server) Start() error {
 	http.HandleFunc("/user/", userInfoHandler)
 	http.HandleFunc("/auth/refresh", refreshTokenHandler)
 	http.HandleFunc("/auth/me", getUserHandler)
+	http.HandleFunc("/auth/logout", logoutHandler)
+	http.HandleFunc("/auth/logout-all", logoutAllHandler)
+	http.HandleFunc("/auth/sessions", sessionsHandler)
+	http.HandleFunc("/auth/sessions/", revokeSessionHandler)
+	http.HandleFunc("/auth/oidc/login", oidcLoginHandler)
+	http.HandleFunc("/auth/oidc/callback", oidcCallbackHandler)
+	http.HandleFunc("/auth/2fa", twoFactorLoginHandler)
+	http.HandleFunc("/auth/2fa/setup", twoFactorSetupHandler)
+	http.HandleFunc("/auth/2fa/enable", twoFactorEnableHandler)
+	http.HandleFunc("/auth/2fa/disable", twoFactorDisableHandler)
+	http.HandleFunc("/auth/password", changePasswordHandler)
+	http.HandleFunc("/auth/password/reset", passwordResetHandler)
+	http.HandleFunc("/auth/password/reset/confirm", passwordResetConfirmHandler)
+	http.HandleFunc("/.well-known/jwks.json", jwksHandler)
+	http.HandleFunc("/admin/unlock", unlockLoginHandler)
+	http.HandleFunc("/admin/stats", adminStatsHandler)
+	http.HandleFunc("/admin/games/", adminGameHandler)
+	http.HandleFunc("/admin/users/", adminUserHandler)
+	http.HandleFunc("/admin/moderation/log", moderationLogHandler)
 	http.HandleFunc("/game/history", getHistoryHandler)
 	http.HandleFunc("/game/leaders", getLeadersHandler)
+	http.HandleFunc("/tournament/new", tournamentNewHandler)
+	http.HandleFunc("/tournament/list", tournamentListHandler)
+	http.HandleFunc("/tournament/", tournamentMainHandler)
+	http.HandleFunc("/seasons", seasonsListHandler)
+	http.HandleFunc("/users/online", onlineUsersHandler)
+	http.HandleFunc("/presence/heartbeat", heartbeatHandler)
+	http.HandleFunc("/notifications", notificationsListHandler)
+	http.HandleFunc("/notifications/read", notificationsReadHandler)
+	http.HandleFunc("/notifications/stream", notificationsStreamHandler)
+	http.HandleFunc("/webhooks", webhooksListHandler)
+	http.HandleFunc("/webhooks/", webhooksMainHandler)
+	http.HandleFunc("/friends", friendsListHandler)
+	http.HandleFunc("/friends/", friendsMainHandler)
+	http.HandleFunc("/blocks", blocksListHandler)
+	http.HandleFunc("/blocks/", blocksMainHandler)
+	http.HandleFunc("/seasons/", seasonMainHandler)
+
+	// адрес клиента для лимитов и блокировок входа; X-Forwarded-For - только от доверенных прокси
+	proxies, err := middleware.ParseTrustedProxies(s.config.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("TRUSTED_PROXIES: %w", err)
+	}
+	root := middleware.RealIP(proxies)(http.DefaultServeMux.ServeHTTP)
 
 	log.Printf("Server starting on port %s", s.config.ServerPort)
-	return http.ListenAndServe(":"+s.config.ServerPort, nil)
+	return http.ListenAndServe(":"+s.config.ServerPort, root)
+}
+
+func (s *Server) rateLimit(group, value string) (func(http.HandlerFunc) http.HandlerFunc, error) {
+	limit, err := ratelimit.ParseLimit(value)
+	if err != nil {
+		return nil, fmt.Errorf("группа %s: %w", group, err)
+	}
+	return middleware.RateLimit(s.limiter, group, limit), nil
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
@@ -135,3 +506,86 @@ func (s *Server) mainHandler(w http.ResponseWriter, r *http.Request) {
 
 	http.NotFound(w, r)
 }
+
+func (s *Server) userHandler(w http.ResponseWriter, r *http.Request) {
+	if strings.Contains(r.URL.Path, "/vs/") {
+		s.gameAPI.HandlerGetHeadToHead(w, r)
+		return
+	}
+
+	s.userAPI.HandlerGetUserUUID(w, r)
+}
+
+func (s *Server) adminUsersHandler(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	switch {
+	case strings.Contains(path, "/roles/"):
+		s.adminAPI.HandlerUserRole(w, r)
+	case strings.HasSuffix(path, "/ban"):
+		s.moderationAPI.HandlerBan(w, r)
+	case strings.HasSuffix(path, "/mute"):
+		s.moderationAPI.HandlerMute(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) tournamentHandler(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	switch {
+	case strings.HasSuffix(path, "/join"):
+		s.tournamentAPI.HandlerJoinTournament(w, r)
+	case strings.HasSuffix(path, "/start"):
+		s.tournamentAPI.HandlerStartTournament(w, r)
+	case strings.HasSuffix(path, "/standings"):
+		s.tournamentAPI.HandlerGetStandings(w, r)
+	case strings.HasSuffix(path, "/bracket"):
+		s.tournamentAPI.HandlerGetBracket(w, r)
+	default:
+		s.tournamentAPI.HandlerGetTournament(w, r)
+	}
+}
+
+func (s *Server) friendsHandler(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	switch {
+	case path == "/friends/requests":
+		s.friendAPI.HandlerGetRequests(w, r)
+	case strings.HasPrefix(path, "/friends/requests/") && strings.HasSuffix(path, "/accept"):
+		s.friendAPI.HandlerAcceptRequest(w, r)
+	case strings.HasPrefix(path, "/friends/requests/") && strings.HasSuffix(path, "/decline"):
+		s.friendAPI.HandlerDeclineRequest(w, r)
+	case strings.HasPrefix(path, "/friends/requests/"):
+		s.friendAPI.HandlerSendRequest(w, r)
+	default:
+		s.friendAPI.HandlerRemoveFriend(w, r)
+	}
+}
+
+func (s *Server) webhooksHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		s.webhookAPI.HandlerCreateWebhook(w, r)
+		return
+	}
+	s.webhookAPI.HandlerGetWebhooks(w, r)
+}
+
+func (s *Server) webhookHandler(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/deliveries") {
+		s.webhookAPI.HandlerGetDeliveries(w, r)
+		return
+	}
+	s.webhookAPI.HandlerDeleteWebhook(w, r)
+}
+
+func (s *Server) seasonHandler(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/leaderboard") {
+		s.seasonAPI.HandlerGetSeasonLeaderBoard(w, r)
+		return
+	}
+
+	http.NotFound(w, r)
+}
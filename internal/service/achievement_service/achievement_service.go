@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"log"
+	model "tic-tac-toe/internal/domain/model/achievement"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	txModel "tic-tac-toe/internal/domain/model/transaction"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type achievementService struct {
+	repo  model.AchievementRepository
+	games gameModel.GameRepository
+	tx    txModel.Manager
+}
+
+func NewAchievementService(repo model.AchievementRepository, games gameModel.GameRepository, tx txModel.Manager) AchievementService {
+	return &achievementService{
+		repo:  repo,
+		games: games,
+		tx:    tx,
+	}
+}
+
+func (s *achievementService) Evaluate(ctx context.Context, game gameModel.Game) ([]model.UserAchievement, error) {
+	if !game.Finished() {
+		return nil, nil
+	}
+	players := []uuid.UUID{game.PlayerX}
+	if game.PlayerO != nil {
+		players = append(players, *game.PlayerO)
+	}
+
+	var granted []model.UserAchievement
+	for _, userID := range players {
+		// счётчики и выданные по ним достижения сохраняются вместе
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			stats, err := s.addGame(ctx, userID, game)
+			if err != nil {
+				return err
+			}
+			for _, code := range Unlocked(stats) {
+				ok, err := s.repo.Grant(ctx, userID, code)
+				if err != nil {
+					return err
+				}
+				if ok {
+					log.Printf("Достижение %s выдано user_id=%s", code, userID)
+					granted = append(granted, model.UserAchievement{
+						UserID:     userID,
+						Code:       code,
+						UnlockedAt: time.Now(),
+					})
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return granted, err
+		}
+	}
+	return granted, nil
+}
+
+// addGame учитывает игру в счётчиках пользователя. Повторная доставка события
+// игру не учитывает второй раз. Историю игр читает только первый вызов для
+// пользователя - он заводит счётчики
+func (s *achievementService) addGame(ctx context.Context, userID uuid.UUID, game gameModel.Game) (model.PlayerStats, error) {
+	stats, err := s.repo.LockStats(ctx, userID)
+	if err != nil {
+		return model.PlayerStats{}, err
+	}
+	if stats == nil {
+		history, err := s.games.GetFinishedGamesByUser(ctx, userID)
+		if err != nil {
+			return model.PlayerStats{}, err
+		}
+		ids := make([]uuid.UUID, 0, len(history))
+		for _, g := range history {
+			ids = append(ids, g.UUID)
+		}
+		if _, err := s.repo.CountGames(ctx, userID, ids); err != nil {
+			return model.PlayerStats{}, err
+		}
+		computed := ComputeStats(userID, history)
+		stats = &computed
+	}
+
+	counted, err := s.repo.CountGames(ctx, userID, []uuid.UUID{game.UUID})
+	if err != nil {
+		return model.PlayerStats{}, err
+	}
+	if len(counted) > 0 {
+		*stats = AddGame(*stats, userID, game)
+	}
+	return *stats, s.repo.SaveStats(ctx, userID, *stats)
+}
+
+func (s *achievementService) GetUserAchievements(ctx context.Context, userID uuid.UUID) ([]model.UserAchievement, error) {
+	return s.repo.GetByUser(ctx, userID)
+}
+
+func (s *achievementService) Catalog() []model.Achievement {
+	achievements := make([]model.Achievement, 0, len(catalog))
+	for _, r := range catalog {
+		achievements = append(achievements, r.achievement)
+	}
+	return achievements
+}
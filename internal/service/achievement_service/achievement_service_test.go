@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	model "tic-tac-toe/internal/domain/model/achievement"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	txModel "tic-tac-toe/internal/domain/model/transaction"
+	userModel "tic-tac-toe/internal/domain/model/user"
+	"tic-tac-toe/internal/storage/contract"
+	"tic-tac-toe/internal/storage/memory"
+	"tic-tac-toe/internal/storage/postgres"
+
+	"github.com/google/uuid"
+)
+
+type repositories struct {
+	achievements model.AchievementRepository
+	games        gameModel.GameRepository
+	users        userModel.UserRepository
+	tx           txModel.Manager
+}
+
+// countingGames считает чтения истории игр
+type countingGames struct {
+	gameModel.GameRepository
+	reads *int
+}
+
+func (r countingGames) GetFinishedGamesByUser(ctx context.Context, userID uuid.UUID) ([]gameModel.Game, error) {
+	*r.reads++
+	return r.GameRepository.GetFinishedGamesByUser(ctx, userID)
+}
+
+func TestEvaluate(t *testing.T) {
+	backends := map[string]func(t *testing.T) repositories{
+		"Memory": func(t *testing.T) repositories {
+			store := memory.NewStore()
+			return repositories{
+				achievements: memory.NewAchievementRepository(store),
+				games:        memory.NewGameRepository(store),
+				users:        memory.NewUserRepository(store),
+				tx:           memory.NewTxManager(store),
+			}
+		},
+		"Postgres": func(t *testing.T) repositories {
+			pool := contract.PostgresPool(t)
+			return repositories{
+				achievements: postgres.NewAchievementRepository(pool),
+				games:        postgres.NewGameRepository(pool),
+				users:        postgres.NewUserRepository(pool),
+				tx:           postgres.NewTxManager(pool),
+			}
+		},
+	}
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			testEvaluate(t, backend(t))
+		})
+	}
+}
+
+func testEvaluate(t *testing.T, repos repositories) {
+	ctx := context.Background()
+	userID := uuid.New()
+	user := userModel.User{UUID: userID, Login: "ach_" + userID.String()[:8], Password: "hash"}
+	if err := repos.users.CreateUser(ctx, user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	reads := 0
+	s := NewAchievementService(repos.achievements, countingGames{repos.games, &reads}, repos.tx)
+
+	// партии с ботом: результат сохранён, событие приходит после сохранения
+	play := func(result string, moves int) gameModel.Game {
+		t.Helper()
+		game := finishedGame(result, true, moves)
+		game.PlayerX, game.CurrentTurn = userID, userID
+		game.Symbols = map[uuid.UUID]gameModel.Char{userID: gameModel.CharX}
+		if err := repos.games.SaveGame(ctx, game); err != nil {
+			t.Fatalf("SaveGame: %v", err)
+		}
+		return game
+	}
+	evaluate := func(game gameModel.Game) []model.Code {
+		t.Helper()
+		granted, err := s.Evaluate(ctx, game)
+		if err != nil {
+			t.Fatalf("Evaluate: %v", err)
+		}
+		var codes []model.Code
+		for _, a := range granted {
+			codes = append(codes, a.Code)
+		}
+		return codes
+	}
+	stats := func() model.PlayerStats {
+		t.Helper()
+		var got *model.PlayerStats
+		err := repos.tx.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			got, err = repos.achievements.LockStats(ctx, userID)
+			return err
+		})
+		if err != nil || got == nil {
+			t.Fatalf("LockStats: %+v, %v", got, err)
+		}
+		return *got
+	}
+
+	// игры до появления счётчиков: первое событие заводит их по истории
+	play("win", 4)
+	play("win", 4)
+	third := play("win", 4)
+	got := evaluate(third)
+	if want := []model.Code{FirstGame, FirstWin, BeatTheBot, WinStreak3}; !slices.Equal(got, want) {
+		t.Errorf("granted = %v, want %v", got, want)
+	}
+	want := model.PlayerStats{GamesPlayed: 3, Wins: 3, BotWins: 3, CurrentWinStreak: 3, FastestWin: 4}
+	if got := stats(); got != want {
+		t.Errorf("stats = %+v, want %+v", got, want)
+	}
+
+	// повторная доставка события не учитывает игру второй раз
+	if got := evaluate(third); len(got) != 0 {
+		t.Errorf("redelivery granted %v, want nothing", got)
+	}
+	if got := stats(); got != want {
+		t.Errorf("stats after redelivery = %+v, want %+v", got, want)
+	}
+
+	// дальше счётчики обновляются без чтения истории
+	evaluate(play("loss", 3))
+	if got := evaluate(play("win", 3)); !slices.Equal(got, []model.Code{QuickWin}) {
+		t.Errorf("granted = %v, want [%s]", got, QuickWin)
+	}
+	want = model.PlayerStats{GamesPlayed: 5, Wins: 4, BotWins: 4, CurrentWinStreak: 1, FastestWin: 3}
+	if got := stats(); got != want {
+		t.Errorf("stats = %+v, want %+v", got, want)
+	}
+	if reads != 1 {
+		t.Errorf("history read %d times, want once", reads)
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	model "tic-tac-toe/internal/domain/model/achievement"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+
+	"github.com/google/uuid"
+)
+
+const (
+	FirstGame   model.Code = "first_game"
+	FirstWin    model.Code = "first_win"
+	FirstDraw   model.Code = "first_draw"
+	BeatTheBot  model.Code = "beat_the_bot"
+	WinStreak3  model.Code = "win_streak_3"
+	WinStreak10 model.Code = "win_streak_10"
+	QuickWin    model.Code = "quick_win"
+	Games10     model.Code = "games_10"
+	Games100    model.Code = "games_100"
+)
+
+// минимальное число собственных ходов, за которое можно выиграть на поле 3x3
+const minMovesToWin = 3
+
+// значения клеток поля (совпадают с game_service)
+const (
+	cellX = 1
+	cellO = 2
+)
+
+type rule struct {
+	achievement model.Achievement
+	unlocked    func(s model.PlayerStats) bool
+}
+
+var catalog = []rule{
+	{
+		achievement: model.Achievement{Code: FirstGame, Title: "Первая партия", Description: "Сыграть первую игру"},
+		unlocked:    func(s model.PlayerStats) bool { return s.GamesPlayed >= 1 },
+	},
+	{
+		achievement: model.Achievement{Code: FirstWin, Title: "Первая победа", Description: "Выиграть первую игру"},
+		unlocked:    func(s model.PlayerStats) bool { return s.Wins >= 1 },
+	},
+	{
+		achievement: model.Achievement{Code: FirstDraw, Title: "Боевая ничья", Description: "Сыграть вничью"},
+		unlocked:    func(s model.PlayerStats) bool { return s.Draws >= 1 },
+	},
+	{
+		achievement: model.Achievement{Code: BeatTheBot, Title: "Сильнее машины", Description: "Обыграть бота"},
+		unlocked:    func(s model.PlayerStats) bool { return s.BotWins >= 1 },
+	},
+	{
+		achievement: model.Achievement{Code: WinStreak3, Title: "Хет-трик", Description: "Выиграть 3 игры подряд"},
+		unlocked:    func(s model.PlayerStats) bool { return s.CurrentWinStreak >= 3 },
+	},
+	{
+		achievement: model.Achievement{Code: WinStreak10, Title: "Непобедимый", Description: "Выиграть 10 игр подряд"},
+		unlocked:    func(s model.PlayerStats) bool { return s.CurrentWinStreak >= 10 },
+	},
+	{
+		achievement: model.Achievement{Code: QuickWin, Title: "Блиц", Description: "Выиграть за минимальное число ходов"},
+		unlocked:    func(s model.PlayerStats) bool { return s.FastestWin == minMovesToWin },
+	},
+	{
+		achievement: model.Achievement{Code: Games10, Title: "Завсегдатай", Description: "Сыграть 10 игр"},
+		unlocked:    func(s model.PlayerStats) bool { return s.GamesPlayed >= 10 },
+	},
+	{
+		achievement: model.Achievement{Code: Games100, Title: "Ветеран", Description: "Сыграть 100 игр"},
+		unlocked:    func(s model.PlayerStats) bool { return s.GamesPlayed >= 100 },
+	},
+}
+
+// Unlocked возвращает коды всех достижений, условия которых выполнены
+func Unlocked(stats model.PlayerStats) []model.Code {
+	var codes []model.Code
+	for _, r := range catalog {
+		if r.unlocked(stats) {
+			codes = append(codes, r.achievement.Code)
+		}
+	}
+	return codes
+}
+
+// ComputeStats считает статистику игрока по завершённым играм (от новых к старым)
+func ComputeStats(userID uuid.UUID, games []gameModel.Game) model.PlayerStats {
+	var stats model.PlayerStats
+	for i := len(games) - 1; i >= 0; i-- {
+		stats = AddGame(stats, userID, games[i])
+	}
+	return stats
+}
+
+// AddGame учитывает в статистике очередную завершённую игру игрока
+func AddGame(stats model.PlayerStats, userID uuid.UUID, game gameModel.Game) model.PlayerStats {
+	if !game.Finished() {
+		return stats
+	}
+	stats.GamesPlayed++
+
+	switch {
+	case isWinner(userID, game):
+		stats.Wins++
+		stats.CurrentWinStreak++
+		if game.WithBot() {
+			stats.BotWins++
+		}
+		if moves := winnerMoves(game); stats.FastestWin == 0 || moves < stats.FastestWin {
+			stats.FastestWin = moves
+		}
+	case game.Status == gameModel.Draw:
+		stats.Draws++
+		stats.CurrentWinStreak = 0
+	default:
+		stats.CurrentWinStreak = 0
+	}
+	return stats
+}
+
+func isWinner(userID uuid.UUID, game gameModel.Game) bool {
+	winner := game.Winner()
+	return winner != nil && *winner == userID
+}
+
+// число ходов победителя - количество его символов на поле
+func winnerMoves(game gameModel.Game) int {
+	cell := cellX
+	if game.Status == gameModel.WonO {
+		cell = cellO
+	}
+	moves := 0
+	for _, row := range game.Field.Field {
+		for _, c := range row {
+			if c == cell {
+				moves++
+			}
+		}
+	}
+	return moves
+}
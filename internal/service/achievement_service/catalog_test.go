@@ -0,0 +1,147 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	model "tic-tac-toe/internal/domain/model/achievement"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+
+	"github.com/google/uuid"
+)
+
+var (
+	me       = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	opponent = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+)
+
+// finishedGame - игра me против opponent (или бота при vsBot) с результатом
+// для me; winnerMoves - число символов победителя на поле
+func finishedGame(result string, vsBot bool, winnerMoves int) gameModel.Game {
+	game := gameModel.Game{
+		UUID:    uuid.New(),
+		Field:   &gameModel.GameField{Field: [][]int{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}}},
+		PlayerX: me,
+	}
+	if !vsBot {
+		o := opponent
+		game.PlayerO = &o
+	}
+	cell := cellX
+	switch result {
+	case "win":
+		game.Status = gameModel.WonX
+	case "loss":
+		game.Status, cell = gameModel.WonO, cellO
+	case "draw":
+		game.Status = gameModel.Draw
+	case "playing":
+		game.Status = gameModel.Playing
+	}
+	for i := range winnerMoves {
+		game.Field.Field[i/3][i%3] = cell
+	}
+	return game
+}
+
+func win(moves int) gameModel.Game { return finishedGame("win", false, moves) }
+func loss() gameModel.Game         { return finishedGame("loss", false, 3) }
+func draw() gameModel.Game         { return finishedGame("draw", false, 0) }
+
+func TestComputeStats(t *testing.T) {
+	// как в GetFinishedGamesByUser: от новых игр к старым
+	tests := []struct {
+		name    string
+		history []gameModel.Game
+		want    model.PlayerStats
+	}{
+		{"no games", nil, model.PlayerStats{}},
+		{"loss", []gameModel.Game{loss()}, model.PlayerStats{GamesPlayed: 1}},
+		{"draw", []gameModel.Game{draw()}, model.PlayerStats{GamesPlayed: 1, Draws: 1}},
+		{
+			"win against the bot",
+			[]gameModel.Game{finishedGame("win", true, 3)},
+			model.PlayerStats{GamesPlayed: 1, Wins: 1, BotWins: 1, CurrentWinStreak: 1, FastestWin: 3},
+		},
+		{
+			"win as O",
+			[]gameModel.Game{{Status: gameModel.WonO, PlayerX: opponent, PlayerO: &me,
+				Field: &gameModel.GameField{Field: [][]int{{2, 2, 2}, {1, 1, 0}, {1, 0, 1}}}}},
+			model.PlayerStats{GamesPlayed: 1, Wins: 1, CurrentWinStreak: 1, FastestWin: 3},
+		},
+		{
+			"streak after a loss",
+			[]gameModel.Game{win(4), win(4), loss(), win(4), win(4), win(4)},
+			model.PlayerStats{GamesPlayed: 6, Wins: 5, CurrentWinStreak: 2, FastestWin: 4},
+		},
+		{
+			"streak broken by a draw",
+			[]gameModel.Game{win(4), draw(), win(4)},
+			model.PlayerStats{GamesPlayed: 3, Wins: 2, Draws: 1, CurrentWinStreak: 1, FastestWin: 4},
+		},
+		{
+			"streak ended by the latest loss",
+			[]gameModel.Game{loss(), win(4), win(4), win(4)},
+			model.PlayerStats{GamesPlayed: 4, Wins: 3, FastestWin: 4},
+		},
+		{
+			"fastest win",
+			[]gameModel.Game{win(5), win(3), win(4)},
+			model.PlayerStats{GamesPlayed: 3, Wins: 3, CurrentWinStreak: 3, FastestWin: 3},
+		},
+		{
+			"unfinished games ignored",
+			[]gameModel.Game{finishedGame("playing", false, 2), win(4)},
+			model.PlayerStats{GamesPlayed: 1, Wins: 1, CurrentWinStreak: 1, FastestWin: 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeStats(me, tt.history); got != tt.want {
+				t.Errorf("ComputeStats = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddGameMatchesComputeStats(t *testing.T) {
+	history := []gameModel.Game{win(4), loss(), draw(), win(3), finishedGame("win", true, 5), win(4), loss()}
+
+	// счётчики, накопленные по одной игре, совпадают с пересчётом всей истории
+	var stats model.PlayerStats
+	for i := len(history) - 1; i >= 0; i-- {
+		stats = AddGame(stats, me, history[i])
+		if want := ComputeStats(me, history[i:]); stats != want {
+			t.Fatalf("after %d games: AddGame = %+v, ComputeStats = %+v", len(history)-i, stats, want)
+		}
+	}
+}
+
+func TestUnlocked(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats model.PlayerStats
+		want  []model.Code
+	}{
+		{"nothing", model.PlayerStats{}, nil},
+		{"first game", model.PlayerStats{GamesPlayed: 1}, []model.Code{FirstGame}},
+		{"first win", model.PlayerStats{GamesPlayed: 1, Wins: 1, CurrentWinStreak: 1, FastestWin: 4}, []model.Code{FirstGame, FirstWin}},
+		{"first draw", model.PlayerStats{GamesPlayed: 1, Draws: 1}, []model.Code{FirstGame, FirstDraw}},
+		{"beat the bot", model.PlayerStats{GamesPlayed: 1, Wins: 1, BotWins: 1, FastestWin: 4}, []model.Code{FirstGame, FirstWin, BeatTheBot}},
+		{"streak of 2", model.PlayerStats{GamesPlayed: 2, Wins: 2, CurrentWinStreak: 2, FastestWin: 4}, []model.Code{FirstGame, FirstWin}},
+		{"streak of 3", model.PlayerStats{GamesPlayed: 3, Wins: 3, CurrentWinStreak: 3, FastestWin: 4}, []model.Code{FirstGame, FirstWin, WinStreak3}},
+		{"streak of 10", model.PlayerStats{GamesPlayed: 10, Wins: 10, CurrentWinStreak: 10, FastestWin: 4},
+			[]model.Code{FirstGame, FirstWin, WinStreak3, WinStreak10, Games10}},
+		{"quick win", model.PlayerStats{GamesPlayed: 1, Wins: 1, FastestWin: 3}, []model.Code{FirstGame, FirstWin, QuickWin}},
+		{"9 games", model.PlayerStats{GamesPlayed: 9}, []model.Code{FirstGame}},
+		{"10 games", model.PlayerStats{GamesPlayed: 10}, []model.Code{FirstGame, Games10}},
+		{"100 games", model.PlayerStats{GamesPlayed: 100}, []model.Code{FirstGame, Games10, Games100}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unlocked(tt.stats); !slices.Equal(got, tt.want) {
+				t.Errorf("Unlocked = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
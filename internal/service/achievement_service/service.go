@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+	model "tic-tac-toe/internal/domain/model/achievement"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+
+	"github.com/google/uuid"
+)
+
+type AchievementService interface {
+	// Evaluate проверяет правила каталога для участников завершённой игры
+	// и возвращает только что выданные достижения
+	Evaluate(ctx context.Context, game gameModel.Game) ([]model.UserAchievement, error)
+	GetUserAchievements(ctx context.Context, userID uuid.UUID) ([]model.UserAchievement, error)
+	Catalog() []model.Achievement
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"log"
+	model "tic-tac-toe/internal/domain/model/admin"
+	userModel "tic-tac-toe/internal/domain/model/user"
+	gameService "tic-tac-toe/internal/service/game_service"
+	userService "tic-tac-toe/internal/service/user_service"
+
+	"github.com/google/uuid"
+)
+
+type adminService struct {
+	stats model.StatsRepository
+	games gameService.GameServices
+	users userService.UserService
+}
+
+func NewAdminService(stats model.StatsRepository, games gameService.GameServices, users userService.UserService) AdminService {
+	return &adminService{
+		stats: stats,
+		games: games,
+		users: users,
+	}
+}
+
+func (s *adminService) Stats(ctx context.Context) (model.SystemStats, error) {
+	return s.stats.GetSystemStats(ctx)
+}
+
+func (s *adminService) DeleteGame(ctx context.Context, actor, gameID uuid.UUID) error {
+	if err := s.games.DeleteGame(ctx, gameID); err != nil {
+		return err
+	}
+	log.Printf("Администратор %s удалил игру %s", actor, gameID)
+	return nil
+}
+
+func (s *adminService) GrantRole(ctx context.Context, actor, userID uuid.UUID, role userModel.Role) error {
+	if err := s.users.GrantRole(ctx, userID, role); err != nil {
+		return err
+	}
+	log.Printf("Администратор %s выдал роль %s пользователю %s", actor, role, userID)
+	return nil
+}
+
+func (s *adminService) RevokeRole(ctx context.Context, actor, userID uuid.UUID, role userModel.Role) error {
+	// иначе последний администратор может случайно лишить систему админов
+	if actor == userID && role == userModel.RoleAdmin {
+		return ErrCannotRevokeOwnAdmin
+	}
+	if err := s.users.RevokeRole(ctx, userID, role); err != nil {
+		return err
+	}
+	log.Printf("Администратор %s снял роль %s с пользователя %s", actor, role, userID)
+	return nil
+}
+
+func (s *adminService) BootstrapAdmin(ctx context.Context, login string) error {
+	user, err := s.users.GetByLogin(ctx, login)
+	if err != nil {
+		return err
+	}
+	if user.HasRole(userModel.RoleAdmin) {
+		return nil
+	}
+	if err := s.users.GrantRole(ctx, user.UUID, userModel.RoleAdmin); err != nil {
+		return err
+	}
+	log.Printf("Первый администратор назначен: login=%s", login)
+	return nil
+}
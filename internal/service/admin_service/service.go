@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"errors"
+	model "tic-tac-toe/internal/domain/model/admin"
+	userModel "tic-tac-toe/internal/domain/model/user"
+
+	"github.com/google/uuid"
+)
+
+// администратор не может снять роль admin с самого себя
+var ErrCannotRevokeOwnAdmin = errors.New("cannot revoke own admin role")
+
+type AdminService interface {
+	Stats(ctx context.Context) (model.SystemStats, error)
+	DeleteGame(ctx context.Context, actor, gameID uuid.UUID) error
+	GrantRole(ctx context.Context, actor, userID uuid.UUID, role userModel.Role) error
+	RevokeRole(ctx context.Context, actor, userID uuid.UUID, role userModel.Role) error
+	// BootstrapAdmin выдаёт роль admin пользователю с логином login (первый администратор)
+	BootstrapAdmin(ctx context.Context, login string) error
+}
@@ -5,27 +5,50 @@ import (
 	"errors"
 	"log"
 	authModel "tic-tac-toe/internal/domain/model/auth"
+	identityModel "tic-tac-toe/internal/domain/model/identity"
+	txModel "tic-tac-toe/internal/domain/model/transaction"
 	model "tic-tac-toe/internal/domain/model/user"
 	jwtService "tic-tac-toe/internal/service/jwt_service"
+	moderationService "tic-tac-toe/internal/service/moderation_service"
+	notifier "tic-tac-toe/internal/service/notifier_service"
 	userService "tic-tac-toe/internal/service/user_service"
 	db "tic-tac-toe/internal/storage/postgres/dto"
 	"tic-tac-toe/internal/utils"
 	dto "tic-tac-toe/internal/web/dto"
 
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type authServices struct {
-	user   userService.UserService
-	jwt    jwtService.JwtProvider
-	tokens authModel.TokenRepository
+	user       userService.UserService
+	jwt        jwtService.JwtProvider
+	tokens     authModel.TokenRepository
+	attempts   authModel.LoginAttemptRepository
+	resets     authModel.PasswordResetRepository
+	notifier   notifier.Notifier
+	twoFactor  authModel.TwoFactorRepository
+	identities identityModel.IdentityRepository
+	moderation moderationService.ModerationService
+	tx         txModel.Manager
 }
 
-func NewAuthServices(user userService.UserService, jwt jwtService.JwtProvider, tokens authModel.TokenRepository) AuthService {
+func NewAuthServices(user userService.UserService, jwt jwtService.JwtProvider, tokens authModel.TokenRepository,
+	attempts authModel.LoginAttemptRepository, resets authModel.PasswordResetRepository, notifier notifier.Notifier,
+	twoFactor authModel.TwoFactorRepository, identities identityModel.IdentityRepository,
+	moderation moderationService.ModerationService, tx txModel.Manager) AuthService {
 	return &authServices{
-		user:   user,
-		jwt:    jwt,
-		tokens: tokens,
+		user:       user,
+		jwt:        jwt,
+		tokens:     tokens,
+		attempts:   attempts,
+		resets:     resets,
+		notifier:   notifier,
+		twoFactor:  twoFactor,
+		identities: identities,
+		moderation: moderation,
+		tx:         tx,
 	}
 }
 
@@ -33,16 +56,76 @@ func (a *authServices) Registration(ctx context.Context, account dto.SignUpReque
 	return a.user.Register(ctx, account)
 }
 
-func (a *authServices) Login(ctx context.Context, req dto.JwtRequest) (res dto.JwtResponse, err error) {
+// Login проверяет пароль; если у пользователя включена 2FA, вместо пары токенов
+// возвращается challenge токен для LoginTwoFactor
+func (a *authServices) Login(ctx context.Context, req dto.JwtRequest, client authModel.Client) (res dto.LoginResponse, err error) {
+
+	keys := throttleKeys(req.Login, client)
+	if err := a.checkThrottle(ctx, keys); err != nil {
+		return res, err
+	}
 
 	user, err := a.user.Authenticate(ctx, req.Login, req.Password)
 	if err != nil {
-		if errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrInvalidCredentials) {
+		if errors.Is(err, userService.ErrUserNotFound) || errors.Is(err, userService.ErrInvalidCredentials) {
+			// несуществующий логин считается так же, чтобы не раскрывать наличие учётной записи
+			a.recordFailure(ctx, keys)
 			return res, ErrInvalidCredentials
 		}
 		return res, err
 	}
 
+	return a.completeLogin(ctx, user, client)
+}
+
+// completeLogin завершает вход проверенного пользователя: выдаёт пару токенов
+// или challenge токен, если включена 2FA
+func (a *authServices) completeLogin(ctx context.Context, user model.User, client authModel.Client) (res dto.LoginResponse, err error) {
+	if err := a.checkBan(ctx, user.UUID); err != nil {
+		return res, err
+	}
+	twoFactor, err := a.twoFactor.Get(ctx, user.UUID)
+	if err != nil {
+		return res, err
+	}
+	if twoFactor != nil && twoFactor.Enabled() {
+		// счётчик неудач не сбрасываем до ввода кода: иначе пароль
+		// позволил бы бесконечно перебирать коды
+		challenge, err := a.jwt.GenerateChallengeToken(user)
+		if err != nil {
+			log.Printf("Ошибка генерации challenge token для user_id=%s: %v", user.UUID, err)
+			return res, ErrTokenGeneration
+		}
+		log.Printf("Первый фактор пройден, требуется код 2FA: user_id=%s", user.UUID)
+		return dto.LoginResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+		}, nil
+	}
+	a.resetFailures(ctx, user.Login)
+
+	tokens, err := a.issueTokens(ctx, user, client)
+	if err != nil {
+		return res, err
+	}
+	return dto.LoginResponse{JwtResponse: &tokens}, nil
+}
+
+// checkBan запрещает выдачу токенов забаненному пользователю
+func (a *authServices) checkBan(ctx context.Context, userID uuid.UUID) error {
+	ban, err := a.moderation.ActiveBan(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if ban != nil {
+		log.Printf("Вход отклонён: пользователь забанен, user_id=%s", userID)
+		return ErrUserBanned
+	}
+	return nil
+}
+
+// issueTokens выдаёт пару токенов и начинает новую сессию (семейство refresh токенов)
+func (a *authServices) issueTokens(ctx context.Context, user model.User, client authModel.Client) (res dto.JwtResponse, err error) {
 	accessToken, err := a.jwt.GenerateAccessToken(user)
 	if err != nil {
 		log.Printf("Ошибка генерации access token для user_id=%s: %v", user.UUID, err)
@@ -58,7 +141,10 @@ func (a *authServices) Login(ctx context.Context, req dto.JwtRequest) (res dto.J
 	token := db.RefreshToken{
 		TokenHash: utils.HashToken(refreshToken),
 		UserID:    user.UUID,
+		FamilyID:  uuid.New(),
 		ExpiresAt: time.Now().Add(jwtService.RefreshTokenTTL),
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
 	}
 
 	if err := a.tokens.Save(ctx, token); err != nil {
@@ -75,68 +161,61 @@ func (a *authServices) Login(ctx context.Context, req dto.JwtRequest) (res dto.J
 	}, nil
 }
 
-func (a *authServices) RefreshAccess(ctx context.Context, refreshToken string) (dto.JwtResponse, error) {
+// RotateRefreshToken выдаёт новую пару токенов и ротирует refresh токен.
+// Предъявление уже ротированного токена означает его кражу:
+// вся сессия (семейство токенов) отзывается и требуется повторный вход
+func (a *authServices) RotateRefreshToken(ctx context.Context, refreshToken string) (dto.JwtResponse, error) {
+	//валидируем
 	claims, err := a.jwt.ValidateRefreshToken(refreshToken)
 	if err != nil {
 		return dto.JwtResponse{}, ErrTokenInvalid
 	}
+	//хэшируем и находим в базе данных
 	hashToken := utils.HashToken(refreshToken)
 	refreshTokenDB, err := a.tokens.FindByHash(ctx, hashToken)
 	if err != nil {
 		return dto.JwtResponse{}, ErrTokenNotFound
 	}
+	//токен уже был заменён - повторное использование
+	if refreshTokenDB.RotatedAt != nil {
+		return dto.JwtResponse{}, a.revokeFamily(ctx, refreshTokenDB)
+	}
+	//удаляем просроченный токен
 	if time.Now().After(refreshTokenDB.ExpiresAt) {
 		_ = a.tokens.DeleteByHash(ctx, hashToken)
 		return dto.JwtResponse{}, ErrTokenExpired
 	}
-	user, err := a.user.GetByID(ctx, claims.UserID)
-	if err != nil {
-		return dto.JwtResponse{}, ErrUserNotFound
-	}
-	accessToken, err := a.jwt.GenerateAccessToken(user)
-	if err != nil {
-		return dto.JwtResponse{}, ErrTokenGeneration
-	}
-	return dto.JwtResponse{
-		Type:         "Bearer",
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
-}
-
-func (a *authServices) RotateRefreshToken(ctx context.Context, refreshToken string) (dto.JwtResponse, error) {
-	//валидируем
-	claims, err := a.jwt.ValidateRefreshToken(refreshToken)
-	if err != nil {
-		return dto.JwtResponse{}, ErrTokenNotFound
-	}
-	//хэшируем
-	hashToken := utils.HashToken(refreshToken)
-	// //находим в базе данных
-	refreshTokenDB, err := a.tokens.FindByHash(ctx, hashToken)
-	if err != nil {
-		return dto.JwtResponse{}, ErrTokenNotFound
-	}
 	//"вытаскиваем" пользователя
 	user, err := a.user.GetByID(ctx, claims.UserID)
 	if err != nil {
-		return dto.JwtResponse{}, ErrTokenInvalid
+		return dto.JwtResponse{}, ErrUserNotFound
 	}
-	//удаляем старый токен
-	if time.Now().After(refreshTokenDB.ExpiresAt) {
-		_ = a.tokens.DeleteByHash(ctx, hashToken)
-		return dto.JwtResponse{}, ErrTokenExpired
+	if err := a.checkBan(ctx, user.UUID); err != nil {
+		return dto.JwtResponse{}, err
 	}
 	//генерируем новый рефреш токен
 	newRefreshToken, err := a.jwt.GenerateRefreshToken(user)
 	if err != nil {
 		return dto.JwtResponse{}, ErrTokenGeneration
 	}
-	//сохраняем новый токен в бд
-	er := a.tokens.Save(ctx, db.RefreshToken{TokenHash: utils.HashToken(newRefreshToken), UserID: user.UUID})
-	if er != nil {
+	//заменяем старый токен новым в той же сессии
+	rotated, err := a.tokens.Rotate(ctx, hashToken, db.RefreshToken{
+		TokenHash: utils.HashToken(newRefreshToken),
+		UserID:    user.UUID,
+		FamilyID:  refreshTokenDB.FamilyID,
+		ExpiresAt: time.Now().Add(jwtService.RefreshTokenTTL),
+		CreatedAt: refreshTokenDB.CreatedAt,
+		UserAgent: refreshTokenDB.UserAgent,
+		IP:        refreshTokenDB.IP,
+	})
+	if err != nil {
+		log.Printf("Ошибка ротации refresh token для user_id=%s: %v", user.UUID, err)
 		return dto.JwtResponse{}, ErrTokenSaveFailed
 	}
+	//параллельный запрос успел ротировать этот же токен
+	if !rotated {
+		return dto.JwtResponse{}, a.revokeFamily(ctx, refreshTokenDB)
+	}
 	//генерируем новый аксес токен
 	newAccessToken, err := a.jwt.GenerateAccessToken(user)
 	if err != nil {
@@ -149,3 +228,45 @@ func (a *authServices) RotateRefreshToken(ctx context.Context, refreshToken stri
 		RefreshToken: newRefreshToken,
 	}, nil
 }
+
+// отзывает сессию при повторном использовании refresh токена
+func (a *authServices) revokeFamily(ctx context.Context, token db.RefreshToken) error {
+	log.Printf("Повторное использование refresh token: user_id=%s, family_id=%s - сессия отозвана",
+		token.UserID, token.FamilyID)
+	if _, err := a.tokens.DeleteFamily(ctx, token.UserID, token.FamilyID); err != nil {
+		log.Printf("Ошибка отзыва сессии family_id=%s: %v", token.FamilyID, err)
+	}
+	return ErrTokenReused
+}
+
+func (a *authServices) Logout(ctx context.Context, refreshToken string) error {
+	if _, err := a.jwt.ValidateRefreshToken(refreshToken); err != nil {
+		return ErrTokenInvalid
+	}
+	token, err := a.tokens.FindByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		return ErrTokenNotFound
+	}
+	_, err = a.tokens.DeleteFamily(ctx, token.UserID, token.FamilyID)
+	return err
+}
+
+func (a *authServices) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	log.Printf("Выход со всех устройств: user_id=%s", userID)
+	return a.tokens.DeleteAllByUser(ctx, userID)
+}
+
+func (a *authServices) ListSessions(ctx context.Context, userID uuid.UUID) ([]db.RefreshToken, error) {
+	return a.tokens.ListByUser(ctx, userID)
+}
+
+func (a *authServices) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	deleted, err := a.tokens.DeleteFamily(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrSessionNotFound
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	authModel "tic-tac-toe/internal/domain/model/auth"
+	identityModel "tic-tac-toe/internal/domain/model/identity"
+	model "tic-tac-toe/internal/domain/model/user"
+	dto "tic-tac-toe/internal/web/dto"
+)
+
+// учётную запись связал одновременный вход, созданный пользователь откатывается
+var errIdentityLinked = errors.New("identity already linked")
+
+func (a *authServices) LoginExternal(ctx context.Context, issuer, subject, preferredLogin string, client authModel.Client) (res dto.LoginResponse, err error) {
+	user, err := a.externalUser(ctx, issuer, subject, preferredLogin)
+	if err != nil {
+		return res, err
+	}
+	return a.completeLogin(ctx, user, client)
+}
+
+// externalUser находит пользователя, связанного с внешней учётной записью,
+// или создаёт нового при первом входе
+func (a *authServices) externalUser(ctx context.Context, issuer, subject, preferredLogin string) (model.User, error) {
+	identity, err := a.identities.Find(ctx, issuer, subject)
+	if err != nil {
+		return model.User{}, err
+	}
+	if identity != nil {
+		user, err := a.user.GetByID(ctx, identity.UserID)
+		if err != nil {
+			return model.User{}, ErrUserNotFound
+		}
+		return user, nil
+	}
+
+	// пользователь и связь создаются вместе: без связи пользователь недоступен
+	var user model.User
+	err = a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = a.user.CreateExternal(ctx, preferredLogin)
+		if err != nil {
+			return err
+		}
+		created, err := a.identities.Create(ctx, identityModel.Identity{Issuer: issuer, Subject: subject, UserID: user.UUID})
+		if err != nil {
+			return err
+		}
+		if !created {
+			return errIdentityLinked
+		}
+		return nil
+	})
+	if errors.Is(err, errIdentityLinked) {
+		// одновременный первый вход: учётную запись уже связал другой запрос
+		log.Printf("Внешняя учётная запись %s/%s уже связана другим запросом", issuer, subject)
+		return a.externalUser(ctx, issuer, subject, preferredLogin)
+	}
+	if err != nil {
+		return model.User{}, err
+	}
+	log.Printf("Связана внешняя учётная запись %s/%s с user_id=%s", issuer, subject, user.UUID)
+	return user, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	identityModel "tic-tac-toe/internal/domain/model/identity"
+	model "tic-tac-toe/internal/domain/model/user"
+	userService "tic-tac-toe/internal/service/user_service"
+	"tic-tac-toe/internal/storage/memory"
+
+	"github.com/google/uuid"
+)
+
+// lateIdentities не видит связь при первом поиске: другой запрос связал
+// учётную запись между поиском и созданием
+type lateIdentities struct {
+	identityModel.IdentityRepository
+	missed *bool
+}
+
+func (r lateIdentities) Find(ctx context.Context, issuer, subject string) (*identityModel.Identity, error) {
+	if !*r.missed {
+		*r.missed = true
+		return nil, nil
+	}
+	return r.IdentityRepository.Find(ctx, issuer, subject)
+}
+
+// brokenIdentities не может сохранить связь
+type brokenIdentities struct {
+	identityModel.IdentityRepository
+}
+
+func (brokenIdentities) Create(ctx context.Context, identity identityModel.Identity) (bool, error) {
+	return false, errors.New("db is down")
+}
+
+func newExternalAuth(store *memory.Store, identities identityModel.IdentityRepository) *authServices {
+	tx := memory.NewTxManager(store)
+	return &authServices{
+		user:       userService.NewUserServices(memory.NewUserRepository(store), tx),
+		identities: identities,
+		tx:         tx,
+	}
+}
+
+func TestExternalUserConcurrentLink(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	identities := memory.NewIdentityRepository(store)
+
+	linked := model.User{UUID: uuid.New(), Login: "first_request", Password: "hash"}
+	if err := users.CreateUser(ctx, linked); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if _, err := identities.Create(ctx, identityModel.Identity{Issuer: "iss", Subject: "sub", UserID: linked.UUID}); err != nil {
+		t.Fatalf("identities.Create: %v", err)
+	}
+
+	missed := false
+	a := newExternalAuth(store, lateIdentities{IdentityRepository: identities, missed: &missed})
+	user, err := a.externalUser(ctx, "iss", "sub", "alice")
+	if err != nil {
+		t.Fatalf("externalUser: %v", err)
+	}
+	if user.UUID != linked.UUID {
+		t.Errorf("user = %s, want the one linked by the other request %s", user.UUID, linked.UUID)
+	}
+	// пользователь, созданный проигравшим запросом, откатан
+	if existing, _ := users.GetUserByLogin(ctx, "alice"); existing != nil {
+		t.Errorf("orphan user %s left after lost race", existing.UUID)
+	}
+}
+
+func TestExternalUserRollback(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	a := newExternalAuth(store, brokenIdentities{memory.NewIdentityRepository(store)})
+
+	if _, err := a.externalUser(ctx, "iss", "sub", "alice"); err == nil {
+		t.Fatal("externalUser: want error when the link is not saved")
+	}
+	if existing, _ := memory.NewUserRepository(store).GetUserByLogin(ctx, "alice"); existing != nil {
+		t.Errorf("user %s created without a link", existing.UUID)
+	}
+}
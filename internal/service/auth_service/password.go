@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	authModel "tic-tac-toe/internal/domain/model/auth"
+	notifier "tic-tac-toe/internal/service/notifier_service"
+	userService "tic-tac-toe/internal/service/user_service"
+	"tic-tac-toe/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// срок действия токена сброса пароля
+const PasswordResetTTL = time.Hour
+
+func (a *authServices) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) error {
+	// пароль меняется только вместе с выходом на всех устройствах: иначе украденный
+	// refresh токен пережил бы смену пароля
+	return a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := a.user.ChangePassword(ctx, userID, oldPassword, newPassword); err != nil {
+			return err
+		}
+		return a.tokens.DeleteAllByUser(ctx, userID)
+	})
+}
+
+func (a *authServices) RequestPasswordReset(ctx context.Context, login string) error {
+	user, err := a.user.GetByLogin(ctx, login)
+	if err != nil {
+		if errors.Is(err, userService.ErrUserNotFound) {
+			// ответ не должен раскрывать, существует ли логин
+			log.Printf("Запрос сброса пароля для несуществующего login=%s", login)
+			return nil
+		}
+		return err
+	}
+
+	token, err := newResetToken()
+	if err != nil {
+		return err
+	}
+	// действует только последний выданный токен
+	if err := a.resets.DeleteByUser(ctx, user.UUID); err != nil {
+		return err
+	}
+	reset := authModel.PasswordResetToken{
+		TokenHash: utils.HashToken(token),
+		UserID:    user.UUID,
+		ExpiresAt: time.Now().Add(PasswordResetTTL),
+	}
+	if err := a.resets.Save(ctx, reset); err != nil {
+		return err
+	}
+
+	msg := notifier.Message{
+		UserID:  user.UUID,
+		Login:   user.Login,
+		Subject: "Сброс пароля",
+		Body: fmt.Sprintf("Токен для сброса пароля: %s\nДействует %s. Если вы не запрашивали сброс, проигнорируйте это сообщение.",
+			token, PasswordResetTTL),
+	}
+	if err := a.notifier.Notify(ctx, msg); err != nil {
+		return fmt.Errorf("ошибка отправки токена сброса пароля: %w", err)
+	}
+	return nil
+}
+
+func (a *authServices) ConfirmPasswordReset(ctx context.Context, token, newPassword string) error {
+	var userID uuid.UUID
+	// токен гасится вместе со сменой пароля: если пароль не сохранён, ссылкой можно воспользоваться снова
+	err := a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var (
+			ok  bool
+			err error
+		)
+		userID, ok, err = a.resets.Consume(ctx, utils.HashToken(token))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrResetTokenInvalid
+		}
+		if err := a.user.SetPassword(ctx, userID, newPassword); err != nil {
+			return err
+		}
+		if err := a.tokens.DeleteAllByUser(ctx, userID); err != nil {
+			return err
+		}
+		return a.resets.DeleteByUser(ctx, userID)
+	})
+	if err != nil {
+		return err
+	}
+	// владелец подтвердил доступ к учётной записи - блокировка входа больше не нужна
+	if user, err := a.user.GetByID(ctx, userID); err == nil {
+		a.resetFailures(ctx, user.Login)
+	}
+	return nil
+}
+
+func newResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("ошибка генерации токена сброса пароля: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	authModel "tic-tac-toe/internal/domain/model/auth"
+	notifier "tic-tac-toe/internal/service/notifier_service"
+	userService "tic-tac-toe/internal/service/user_service"
+	"tic-tac-toe/internal/storage/memory"
+	db "tic-tac-toe/internal/storage/postgres/dto"
+	"tic-tac-toe/internal/utils"
+	"tic-tac-toe/internal/web/dto"
+
+	"github.com/google/uuid"
+)
+
+// brokenTokens не может удалить refresh токены
+type brokenTokens struct {
+	authModel.TokenRepository
+}
+
+func (brokenTokens) DeleteAllByUser(ctx context.Context, userID uuid.UUID) error {
+	return errors.New("db is down")
+}
+
+func TestChangePasswordRollback(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	tx := memory.NewTxManager(store)
+	users := userService.NewUserServices(memory.NewUserRepository(store), tx)
+	user, err := users.Register(ctx, dto.SignUpRequest{Login: "change_pw", Password: "old-secret"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	a := &authServices{user: users, tokens: brokenTokens{memory.NewTokenRepository(store)}, tx: tx}
+
+	if err := a.ChangePassword(ctx, user.UUID, "old-secret", "new-secret"); err == nil {
+		t.Fatal("ChangePassword: want error when sessions are not revoked")
+	}
+	// пароль не сменился: иначе старые сессии пережили бы смену пароля
+	if _, err := users.Authenticate(ctx, "change_pw", "old-secret"); err != nil {
+		t.Errorf("old password rejected after failed change: %v", err)
+	}
+	if _, err := users.Authenticate(ctx, "change_pw", "new-secret"); err == nil {
+		t.Error("new password accepted after failed change")
+	}
+}
+
+// inbox запоминает отправленные сообщения
+type inbox struct {
+	messages []notifier.Message
+}
+
+func (i *inbox) Notify(ctx context.Context, msg notifier.Message) error {
+	i.messages = append(i.messages, msg)
+	return nil
+}
+
+// lastToken достаёт токен сброса из последнего сообщения
+func (i *inbox) lastToken(t *testing.T) string {
+	t.Helper()
+	if len(i.messages) == 0 {
+		t.Fatal("no reset message sent")
+	}
+	_, rest, ok := strings.Cut(i.messages[len(i.messages)-1].Body, "Токен для сброса пароля: ")
+	token, _, _ := strings.Cut(rest, "\n")
+	if !ok || token == "" {
+		t.Fatalf("no token in message %q", i.messages[len(i.messages)-1].Body)
+	}
+	return token
+}
+
+type resetFixture struct {
+	auth   *authServices
+	users  userService.UserService
+	resets authModel.PasswordResetRepository
+	inbox  *inbox
+	userID uuid.UUID
+}
+
+func newResetFixture(t *testing.T) resetFixture {
+	t.Helper()
+	store := memory.NewStore()
+	tx := memory.NewTxManager(store)
+	f := resetFixture{
+		users:  userService.NewUserServices(memory.NewUserRepository(store), tx),
+		resets: memory.NewPasswordResetRepository(store),
+		inbox:  &inbox{},
+	}
+	f.auth = &authServices{
+		user:     f.users,
+		tokens:   memory.NewTokenRepository(store),
+		attempts: memory.NewLoginAttemptRepository(store),
+		resets:   f.resets,
+		notifier: f.inbox,
+		tx:       tx,
+	}
+	user, err := f.users.Register(context.Background(), dto.SignUpRequest{Login: "forgetful", Password: "old-secret"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	f.userID = user.UUID
+	return f
+}
+
+func TestPasswordResetOnce(t *testing.T) {
+	ctx := context.Background()
+	f := newResetFixture(t)
+
+	if err := f.auth.RequestPasswordReset(ctx, "forgetful"); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	token := f.inbox.lastToken(t)
+	if err := f.auth.ConfirmPasswordReset(ctx, token, "new-secret"); err != nil {
+		t.Fatalf("ConfirmPasswordReset: %v", err)
+	}
+	if _, err := f.users.Authenticate(ctx, "forgetful", "new-secret"); err != nil {
+		t.Errorf("new password rejected: %v", err)
+	}
+	if err := f.auth.ConfirmPasswordReset(ctx, token, "third-secret"); !errors.Is(err, ErrResetTokenInvalid) {
+		t.Errorf("token reused: err = %v, want ErrResetTokenInvalid", err)
+	}
+}
+
+func TestPasswordResetExpired(t *testing.T) {
+	ctx := context.Background()
+	f := newResetFixture(t)
+
+	err := f.resets.Save(ctx, authModel.PasswordResetToken{
+		TokenHash: utils.HashToken("expired-token"),
+		UserID:    f.userID,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := f.auth.ConfirmPasswordReset(ctx, "expired-token", "new-secret"); !errors.Is(err, ErrResetTokenInvalid) {
+		t.Errorf("expired token: err = %v, want ErrResetTokenInvalid", err)
+	}
+	if _, err := f.users.Authenticate(ctx, "forgetful", "old-secret"); err != nil {
+		t.Errorf("password changed by an expired token: %v", err)
+	}
+}
+
+func TestPasswordResetLatestTokenOnly(t *testing.T) {
+	ctx := context.Background()
+	f := newResetFixture(t)
+
+	f.auth.RequestPasswordReset(ctx, "forgetful")
+	older := f.inbox.lastToken(t)
+	f.auth.RequestPasswordReset(ctx, "forgetful")
+	newer := f.inbox.lastToken(t)
+
+	if err := f.auth.ConfirmPasswordReset(ctx, older, "new-secret"); !errors.Is(err, ErrResetTokenInvalid) {
+		t.Errorf("older token: err = %v, want ErrResetTokenInvalid", err)
+	}
+	if err := f.auth.ConfirmPasswordReset(ctx, newer, "new-secret"); err != nil {
+		t.Errorf("newer token: %v", err)
+	}
+}
+
+func TestPasswordResetUnknownLogin(t *testing.T) {
+	f := newResetFixture(t)
+
+	// ответ тот же, что для существующего логина, и сообщение никому не уходит
+	if err := f.auth.RequestPasswordReset(context.Background(), "nobody"); err != nil {
+		t.Errorf("unknown login: err = %v, want nil", err)
+	}
+	if len(f.inbox.messages) != 0 {
+		t.Errorf("sent %d messages for an unknown login, want none", len(f.inbox.messages))
+	}
+}
+
+func TestPasswordResetRevokesSessions(t *testing.T) {
+	ctx := context.Background()
+	f := newResetFixture(t)
+
+	for range 2 {
+		err := f.auth.tokens.Save(ctx, db.RefreshToken{
+			TokenHash: utils.HashToken(uuid.NewString()),
+			UserID:    f.userID,
+			FamilyID:  uuid.New(),
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	f.auth.RequestPasswordReset(ctx, "forgetful")
+	if err := f.auth.ConfirmPasswordReset(ctx, f.inbox.lastToken(t), "new-secret"); err != nil {
+		t.Fatalf("ConfirmPasswordReset: %v", err)
+	}
+	if sessions, _ := f.auth.ListSessions(ctx, f.userID); len(sessions) != 0 {
+		t.Errorf("%d sessions left after reset, want 0", len(sessions))
+	}
+}
@@ -3,8 +3,14 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
+
+	authModel "tic-tac-toe/internal/domain/model/auth"
 	model "tic-tac-toe/internal/domain/model/user"
+	db "tic-tac-toe/internal/storage/postgres/dto"
 	dto "tic-tac-toe/internal/web/dto"
+
+	"github.com/google/uuid"
 )
 
 var (
@@ -15,11 +21,67 @@ var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrTokenGeneration    = errors.New("token generation failed")
 	ErrTokenSaveFailed    = errors.New("token save failed")
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrTokenReused        = errors.New("refresh token reuse detected")
+	ErrTooManyAttempts    = errors.New("too many login attempts")
+	ErrAccountLocked      = errors.New("account temporarily locked")
+	ErrNotLocked          = errors.New("no failed login attempts recorded")
+	ErrInvalidUnlockScope = errors.New("unlock scope must be login or ip")
+	ErrResetTokenInvalid  = errors.New("password reset token is invalid or expired")
+	ErrTwoFactorEnabled   = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorNotSetUp  = errors.New("two-factor authentication is not set up")
+	ErrInvalidCode        = errors.New("invalid two-factor code")
+	ErrUserBanned         = errors.New("user is banned")
 )
 
+// ThrottleError - вход отклонён из-за неудачных попыток;
+// RetryAfter - через сколько можно повторить
+type ThrottleError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *ThrottleError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ThrottleError) Unwrap() error {
+	return e.Err
+}
+
 type AuthService interface {
 	Registration(ctx context.Context, req dto.SignUpRequest) (user model.User, err error)
-	Login(ctx context.Context, req dto.JwtRequest) (res dto.JwtResponse, err error)
-	RefreshAccess(ctx context.Context, refreshToken string) (dto.JwtResponse, error)
+	Login(ctx context.Context, req dto.JwtRequest, client authModel.Client) (res dto.LoginResponse, err error)
+	// LoginTwoFactor обменивает challenge токен и код 2FA на пару токенов
+	LoginTwoFactor(ctx context.Context, challengeToken, code string, client authModel.Client) (dto.JwtResponse, error)
+	// LoginExternal выполняет вход через внешнего провайдера (OIDC): пользователь
+	// находится по issuer + subject или создаётся при первом входе
+	LoginExternal(ctx context.Context, issuer, subject, preferredLogin string, client authModel.Client) (dto.LoginResponse, error)
 	RotateRefreshToken(ctx context.Context, refreshToken string) (dto.JwtResponse, error)
+
+	// Logout отзывает сессию переданного refresh токена
+	Logout(ctx context.Context, refreshToken string) error
+	// LogoutAll отзывает все refresh токены пользователя
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]db.RefreshToken, error)
+	// RevokeSession отзывает сессию (семейство refresh токенов) по её идентификатору
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	// Unlock снимает блокировку входа по логину или IP (администрирование)
+	Unlock(ctx context.Context, scope authModel.AttemptScope, key string) error
+
+	// ChangePassword меняет пароль и отзывает все refresh токены пользователя
+	ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) error
+	// RequestPasswordReset отправляет одноразовый токен сброса через Notifier;
+	// для неизвестного логина молча ничего не делает
+	RequestPasswordReset(ctx context.Context, login string) error
+	// ConfirmPasswordReset гасит токен, устанавливает пароль и отзывает refresh токены
+	ConfirmPasswordReset(ctx context.Context, token, newPassword string) error
+
+	// SetupTwoFactor генерирует секрет TOTP и ссылку otpauth://; 2FA включается
+	// только после EnableTwoFactor с кодом из приложения
+	SetupTwoFactor(ctx context.Context, userID uuid.UUID) (secret, uri string, err error)
+	// EnableTwoFactor проверяет код и возвращает одноразовые коды восстановления
+	EnableTwoFactor(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	// DisableTwoFactor отключает 2FA по коду TOTP или коду восстановления
+	DisableTwoFactor(ctx context.Context, userID uuid.UUID, code string) error
 }
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	authModel "tic-tac-toe/internal/domain/model/auth"
+	txModel "tic-tac-toe/internal/domain/model/transaction"
+	model "tic-tac-toe/internal/domain/model/user"
+	jwtService "tic-tac-toe/internal/service/jwt_service"
+	userService "tic-tac-toe/internal/service/user_service"
+	"tic-tac-toe/internal/storage/contract"
+	"tic-tac-toe/internal/storage/memory"
+	"tic-tac-toe/internal/storage/postgres"
+	dto "tic-tac-toe/internal/web/dto"
+
+	"github.com/google/uuid"
+)
+
+type sessionRepositories struct {
+	users     model.UserRepository
+	tokens    authModel.TokenRepository
+	attempts  authModel.LoginAttemptRepository
+	twoFactor authModel.TwoFactorRepository
+	tx        txModel.Manager
+}
+
+func TestSessions(t *testing.T) {
+	backends := map[string]func(t *testing.T) sessionRepositories{
+		"Memory": func(t *testing.T) sessionRepositories {
+			store := memory.NewStore()
+			return sessionRepositories{
+				users:     memory.NewUserRepository(store),
+				tokens:    memory.NewTokenRepository(store),
+				attempts:  memory.NewLoginAttemptRepository(store),
+				twoFactor: memory.NewTwoFactorRepository(store),
+				tx:        memory.NewTxManager(store),
+			}
+		},
+		"Postgres": func(t *testing.T) sessionRepositories {
+			pool := contract.PostgresPool(t)
+			return sessionRepositories{
+				users:     postgres.NewUserRepository(pool),
+				tokens:    postgres.NewTokenRepository(pool),
+				attempts:  postgres.NewLoginAttemptRepository(pool),
+				twoFactor: postgres.NewTwoFactorRepository(pool),
+				tx:        postgres.NewTxManager(pool),
+			}
+		},
+	}
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			testSessions(t, backend(t))
+		})
+	}
+}
+
+// sessionLogin входит под логином login с устройства userAgent
+func sessionLogin(t *testing.T, a *authServices, login, userAgent string) dto.JwtResponse {
+	t.Helper()
+	res, err := a.Login(context.Background(), dto.JwtRequest{Login: login, Password: "secret-pw"}, authModel.Client{UserAgent: userAgent, IP: "203.0.113.7"})
+	if err != nil {
+		t.Fatalf("Login %s: %v", login, err)
+	}
+	if res.JwtResponse == nil {
+		t.Fatalf("Login %s: no tokens", login)
+	}
+	return *res.JwtResponse
+}
+
+// sessionByAgent ищет сессию пользователя по устройству
+func sessionByAgent(t *testing.T, a *authServices, userID uuid.UUID, userAgent string) uuid.UUID {
+	t.Helper()
+	sessions, err := a.ListSessions(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	for _, s := range sessions {
+		if s.UserAgent == userAgent {
+			return s.FamilyID
+		}
+	}
+	t.Fatalf("no session for %q in %+v", userAgent, sessions)
+	return uuid.Nil
+}
+
+func testSessions(t *testing.T, repos sessionRepositories) {
+	ctx := context.Background()
+	keys, err := jwtService.LoadKeySet("", nil, "", []byte("secret"))
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+	users := userService.NewUserServices(repos.users, repos.tx)
+	a := &authServices{
+		user:       users,
+		jwt:        jwtService.NewJwtProvider(keys),
+		tokens:     repos.tokens,
+		attempts:   repos.attempts,
+		twoFactor:  repos.twoFactor,
+		moderation: noBan{},
+		tx:         repos.tx,
+	}
+	suffix := uuid.NewString()[:8]
+	register := func(login string) uuid.UUID {
+		user, err := users.Register(ctx, dto.SignUpRequest{Login: login + "_" + suffix, Password: "secret-pw"})
+		if err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+		return user.UUID
+	}
+	alice, bob := register("alice"), register("bob")
+
+	phone := sessionLogin(t, a, "alice_"+suffix, "phone")
+	laptop := sessionLogin(t, a, "alice_"+suffix, "laptop")
+	bobTokens := sessionLogin(t, a, "bob_"+suffix, "desktop")
+
+	sessions, err := a.ListSessions(ctx, alice)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("alice has %d sessions, want 2", len(sessions))
+	}
+	for _, s := range sessions {
+		if s.UserID != alice || s.IP != "203.0.113.7" || s.CreatedAt.IsZero() {
+			t.Errorf("session = %+v, want alice's session from 203.0.113.7", s)
+		}
+	}
+	phoneID := sessionByAgent(t, a, alice, "phone")
+
+	t.Run("RevokeForeignSession", func(t *testing.T) {
+		// чужая сессия не найдена и не отозвана
+		if err := a.RevokeSession(ctx, bob, phoneID); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("RevokeSession by bob: err = %v, want ErrSessionNotFound", err)
+		}
+		if sessions, _ := a.ListSessions(ctx, alice); len(sessions) != 2 {
+			t.Errorf("alice has %d sessions, want 2", len(sessions))
+		}
+	})
+
+	t.Run("RevokeSession", func(t *testing.T) {
+		if err := a.RevokeSession(ctx, alice, phoneID); err != nil {
+			t.Fatalf("RevokeSession: %v", err)
+		}
+		if _, err := a.RotateRefreshToken(ctx, phone.RefreshToken); err == nil {
+			t.Error("refresh token of a revoked session accepted")
+		}
+		if err := a.RevokeSession(ctx, alice, phoneID); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("second RevokeSession: err = %v, want ErrSessionNotFound", err)
+		}
+		// остальные сессии работают
+		rotated, err := a.RotateRefreshToken(ctx, laptop.RefreshToken)
+		if err != nil {
+			t.Fatalf("RotateRefreshToken of another session: %v", err)
+		}
+		laptop = rotated
+	})
+
+	t.Run("Logout", func(t *testing.T) {
+		if err := a.Logout(ctx, "not-a-token"); !errors.Is(err, ErrTokenInvalid) {
+			t.Errorf("Logout with garbage: err = %v, want ErrTokenInvalid", err)
+		}
+		if err := a.Logout(ctx, laptop.RefreshToken); err != nil {
+			t.Fatalf("Logout: %v", err)
+		}
+		if _, err := a.RotateRefreshToken(ctx, laptop.RefreshToken); err == nil {
+			t.Error("refresh token accepted after logout")
+		}
+		if sessions, _ := a.ListSessions(ctx, alice); len(sessions) != 0 {
+			t.Errorf("alice has %d sessions after logout, want 0", len(sessions))
+		}
+		if err := a.Logout(ctx, laptop.RefreshToken); !errors.Is(err, ErrTokenNotFound) {
+			t.Errorf("second Logout: err = %v, want ErrTokenNotFound", err)
+		}
+	})
+
+	t.Run("LogoutAll", func(t *testing.T) {
+		second := sessionLogin(t, a, "bob_"+suffix, "tablet")
+		if err := a.LogoutAll(ctx, bob); err != nil {
+			t.Fatalf("LogoutAll: %v", err)
+		}
+		for _, refresh := range []string{bobTokens.RefreshToken, second.RefreshToken} {
+			if _, err := a.RotateRefreshToken(ctx, refresh); err == nil {
+				t.Error("refresh token accepted after LogoutAll")
+			}
+		}
+		if sessions, _ := a.ListSessions(ctx, bob); len(sessions) != 0 {
+			t.Errorf("bob has %d sessions after LogoutAll, want 0", len(sessions))
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	authModel "tic-tac-toe/internal/domain/model/auth"
+)
+
+// политика ограничения попыток входа
+type throttlePolicy struct {
+	// после стольких неудач каждая следующая откладывает вход экспоненциально
+	backoffAfter int
+	// после стольких неудач вход блокируется на lockDuration
+	lockAfter    int
+	baseDelay    time.Duration
+	maxDelay     time.Duration
+	lockDuration time.Duration
+}
+
+var (
+	// по логину: защита конкретной учётной записи
+	loginPolicy = throttlePolicy{
+		backoffAfter: 3,
+		lockAfter:    10,
+		baseDelay:    time.Second,
+		maxDelay:     5 * time.Minute,
+		lockDuration: time.Hour,
+	}
+	// по IP: перебор многих логинов с одного адреса; пороги выше из-за NAT
+	ipPolicy = throttlePolicy{
+		backoffAfter: 20,
+		lockAfter:    100,
+		baseDelay:    time.Second,
+		maxDelay:     5 * time.Minute,
+		lockDuration: time.Hour,
+	}
+)
+
+// неудачи старше этого окна не учитываются
+const failureWindow = time.Hour
+
+// delay - на сколько отложить следующую попытку после failures неудач
+func (p throttlePolicy) delay(failures int) time.Duration {
+	if failures >= p.lockAfter {
+		return p.lockDuration
+	}
+	if failures < p.backoffAfter {
+		return 0
+	}
+	delay := p.baseDelay
+	for i := p.backoffAfter; i < failures && delay < p.maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.maxDelay)
+}
+
+type throttleKey struct {
+	scope  authModel.AttemptScope
+	key    string
+	policy throttlePolicy
+}
+
+func throttleKeys(login string, client authModel.Client) []throttleKey {
+	keys := []throttleKey{{scope: authModel.AttemptScopeLogin, key: login, policy: loginPolicy}}
+	if client.IP != "" {
+		keys = append(keys, throttleKey{scope: authModel.AttemptScopeIP, key: client.IP, policy: ipPolicy})
+	}
+	return keys
+}
+
+// checkThrottle отклоняет вход, пока действует задержка или блокировка
+func (a *authServices) checkThrottle(ctx context.Context, keys []throttleKey) error {
+	now := time.Now()
+	for _, k := range keys {
+		attempt, err := a.attempts.Get(ctx, k.scope, k.key)
+		if err != nil {
+			return err
+		}
+		if attempt == nil || attempt.LockedUntil == nil || !attempt.LockedUntil.After(now) {
+			continue
+		}
+		retryAfter := attempt.LockedUntil.Sub(now)
+		// блокировку учётной записи отличаем от временной задержки
+		if k.scope == authModel.AttemptScopeLogin && attempt.Failures >= k.policy.lockAfter {
+			return &ThrottleError{Err: ErrAccountLocked, RetryAfter: retryAfter}
+		}
+		return &ThrottleError{Err: ErrTooManyAttempts, RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// recordFailure учитывает неудачную попытку и при необходимости откладывает следующие
+func (a *authServices) recordFailure(ctx context.Context, keys []throttleKey) {
+	for _, k := range keys {
+		attempt, err := a.attempts.RecordFailure(ctx, k.scope, k.key, failureWindow)
+		if err != nil {
+			log.Printf("Ошибка учёта неудачного входа %s=%s: %v", k.scope, k.key, err)
+			continue
+		}
+		delay := k.policy.delay(attempt.Failures)
+		if delay == 0 {
+			continue
+		}
+		if attempt.Failures >= k.policy.lockAfter {
+			log.Printf("Вход заблокирован %s=%s после %d неудач", k.scope, k.key, attempt.Failures)
+		}
+		if err := a.attempts.Lock(ctx, k.scope, k.key, time.Now().Add(delay)); err != nil {
+			log.Printf("Ошибка блокировки входа %s=%s: %v", k.scope, k.key, err)
+		}
+	}
+}
+
+// после успешного входа счётчик логина сбрасывается; счётчик IP - нет,
+// иначе перебор чужих логинов можно чередовать со входом в свой
+func (a *authServices) resetFailures(ctx context.Context, login string) {
+	if _, err := a.attempts.Reset(ctx, authModel.AttemptScopeLogin, login); err != nil {
+		log.Printf("Ошибка сброса неудачных входов login=%s: %v", login, err)
+	}
+}
+
+func (a *authServices) Unlock(ctx context.Context, scope authModel.AttemptScope, key string) error {
+	if scope != authModel.AttemptScopeLogin && scope != authModel.AttemptScopeIP {
+		return ErrInvalidUnlockScope
+	}
+	found, err := a.attempts.Reset(ctx, scope, key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotLocked
+	}
+	log.Printf("Блокировка входа снята: %s=%s", scope, key)
+	return nil
+}
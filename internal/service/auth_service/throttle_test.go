@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	authModel "tic-tac-toe/internal/domain/model/auth"
+	model "tic-tac-toe/internal/domain/model/user"
+	userService "tic-tac-toe/internal/service/user_service"
+	"tic-tac-toe/internal/storage/memory"
+	dto "tic-tac-toe/internal/web/dto"
+)
+
+// wrongPassword - любой вход завершается неверным паролем
+type wrongPassword struct {
+	userService.UserService
+}
+
+func (wrongPassword) Authenticate(ctx context.Context, login, password string) (model.User, error) {
+	return model.User{}, userService.ErrInvalidCredentials
+}
+
+func newThrottledAuth() *authServices {
+	return &authServices{
+		user:     wrongPassword{},
+		attempts: memory.NewLoginAttemptRepository(memory.NewStore()),
+	}
+}
+
+func login(a *authServices, login, ip string) error {
+	_, err := a.Login(context.Background(), dto.JwtRequest{Login: login, Password: "guess"}, authModel.Client{IP: ip})
+	return err
+}
+
+func TestThrottleOneIPManyAccounts(t *testing.T) {
+	a := newThrottledAuth()
+
+	// каждый логин пробуется один раз: счётчики логинов не растут, срабатывает счётчик IP
+	for i := range ipPolicy.backoffAfter {
+		if err := login(a, fmt.Sprintf("victim%d", i), "203.0.113.7"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("attempt %d: err = %v, want ErrInvalidCredentials", i, err)
+		}
+	}
+
+	var throttled *ThrottleError
+	err := login(a, "fresh_login", "203.0.113.7")
+	if !errors.As(err, &throttled) || !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("next login from the same IP: err = %v, want ErrTooManyAttempts", err)
+	}
+	if throttled.RetryAfter <= 0 || throttled.RetryAfter > ipPolicy.baseDelay {
+		t.Errorf("RetryAfter = %v, want up to %v", throttled.RetryAfter, ipPolicy.baseDelay)
+	}
+
+	// другой адрес не затронут
+	if err := login(a, "fresh_login", "198.51.100.1"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("login from another IP: err = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestThrottleManyIPsOneAccount(t *testing.T) {
+	// пороги прежние, задержки короткие, чтобы пережидать их в тесте
+	saved := loginPolicy
+	t.Cleanup(func() { loginPolicy = saved })
+	loginPolicy.baseDelay = time.Millisecond
+	loginPolicy.maxDelay = time.Millisecond
+
+	a := newThrottledAuth()
+	ctx := context.Background()
+	ip := func(i int) string { return fmt.Sprintf("198.51.100.%d", i+1) }
+
+	for i := range loginPolicy.backoffAfter {
+		if err := login(a, "victim", ip(i)); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("attempt %d: err = %v, want ErrInvalidCredentials", i, err)
+		}
+	}
+	// смена адреса не помогает: задержка действует на учётную запись
+	if err := login(a, "victim", "192.0.2.100"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("login from a new IP during backoff: err = %v, want ErrTooManyAttempts", err)
+	}
+
+	// остальные неудачи до блокировки, каждая после задержки
+	for i := loginPolicy.backoffAfter; i < loginPolicy.lockAfter; i++ {
+		time.Sleep(2 * loginPolicy.maxDelay)
+		if err := login(a, "victim", ip(i)); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("attempt %d: err = %v, want ErrInvalidCredentials", i, err)
+		}
+	}
+
+	var throttled *ThrottleError
+	err := login(a, "victim", "192.0.2.200")
+	if !errors.As(err, &throttled) || !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("login after %d failures: err = %v, want ErrAccountLocked", loginPolicy.lockAfter, err)
+	}
+	if throttled.RetryAfter < loginPolicy.lockDuration-time.Minute {
+		t.Errorf("RetryAfter = %v, want about %v", throttled.RetryAfter, loginPolicy.lockDuration)
+	}
+
+	// адреса атакующего по отдельности порогов не достигли
+	attempt, err := a.attempts.Get(ctx, authModel.AttemptScopeIP, ip(0))
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if attempt == nil || attempt.Failures != 1 || attempt.LockedUntil != nil {
+		t.Errorf("IP attempt = %+v, want one failure without lock", attempt)
+	}
+}
+
+func TestThrottleLockoutExpires(t *testing.T) {
+	a := newThrottledAuth()
+	ctx := context.Background()
+	policy := throttlePolicy{
+		backoffAfter: 1,
+		lockAfter:    2,
+		baseDelay:    10 * time.Millisecond,
+		maxDelay:     10 * time.Millisecond,
+		lockDuration: 50 * time.Millisecond,
+	}
+	keys := []throttleKey{{scope: authModel.AttemptScopeLogin, key: "victim", policy: policy}}
+
+	a.recordFailure(ctx, keys)
+	a.recordFailure(ctx, keys)
+	if err := a.checkThrottle(ctx, keys); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("checkThrottle after lock: err = %v, want ErrAccountLocked", err)
+	}
+
+	time.Sleep(policy.lockDuration + 10*time.Millisecond)
+	if err := a.checkThrottle(ctx, keys); err != nil {
+		t.Errorf("checkThrottle after lock expired: err = %v, want nil", err)
+	}
+}
+
+func TestThrottlePolicyDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{2, 0},
+		{3, time.Second},
+		{4, 2 * time.Second},
+		{5, 4 * time.Second},
+		{9, 64 * time.Second},
+		{10, time.Hour},
+		{50, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := loginPolicy.delay(tt.failures); got != tt.want {
+			t.Errorf("loginPolicy.delay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+	if got := ipPolicy.delay(60); got != ipPolicy.maxDelay {
+		t.Errorf("ipPolicy.delay(60) = %v, want capped at %v", got, ipPolicy.maxDelay)
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	authModel "tic-tac-toe/internal/domain/model/auth"
+	"tic-tac-toe/internal/utils"
+	dto "tic-tac-toe/internal/web/dto"
+
+	"github.com/google/uuid"
+)
+
+func (a *authServices) LoginTwoFactor(ctx context.Context, challengeToken, code string, client authModel.Client) (res dto.JwtResponse, err error) {
+	claims, err := a.jwt.ValidateChallengeToken(challengeToken)
+	if err != nil {
+		return res, ErrTokenInvalid
+	}
+	user, err := a.user.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return res, ErrUserNotFound
+	}
+
+	// неверные коды считаются неудачными входами, как неверный пароль
+	keys := throttleKeys(user.Login, client)
+	if err := a.checkThrottle(ctx, keys); err != nil {
+		return res, err
+	}
+	if err := a.verifySecondFactor(ctx, user.UUID, code); err != nil {
+		if errors.Is(err, ErrInvalidCode) {
+			a.recordFailure(ctx, keys)
+		}
+		return res, err
+	}
+	a.resetFailures(ctx, user.Login)
+
+	// challenge токен одноразовый: повтор с другим кодом не даст второй сессии
+	used, err := a.twoFactor.UseChallenge(ctx, user.UUID, claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return res, err
+	}
+	if !used {
+		return res, ErrTokenInvalid
+	}
+
+	// бан мог быть выдан между вводом пароля и кода
+	if err := a.checkBan(ctx, user.UUID); err != nil {
+		return res, err
+	}
+	return a.issueTokens(ctx, user, client)
+}
+
+func (a *authServices) SetupTwoFactor(ctx context.Context, userID uuid.UUID) (secret, uri string, err error) {
+	user, err := a.user.GetByID(ctx, userID)
+	if err != nil {
+		return "", "", ErrUserNotFound
+	}
+	secret, err = newTOTPSecret()
+	if err != nil {
+		return "", "", err
+	}
+	saved, err := a.twoFactor.SaveSecret(ctx, userID, secret)
+	if err != nil {
+		return "", "", err
+	}
+	if !saved {
+		return "", "", ErrTwoFactorEnabled
+	}
+	return secret, totpURI(secret, user.Login), nil
+}
+
+func (a *authServices) EnableTwoFactor(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	twoFactor, err := a.twoFactor.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, ErrTwoFactorNotSetUp
+	}
+	if twoFactor.Enabled() {
+		return nil, ErrTwoFactorEnabled
+	}
+	step, ok := verifyTOTP(twoFactor.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidCode
+	}
+
+	codes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, c := range codes {
+		hashes[i] = utils.HashToken(normalizeRecoveryCode(c))
+	}
+	if err := a.twoFactor.Enable(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+	log.Printf("2FA включена: user_id=%s", userID)
+	return codes, nil
+}
+
+func (a *authServices) DisableTwoFactor(ctx context.Context, userID uuid.UUID, code string) error {
+	if err := a.verifySecondFactor(ctx, userID, code); err != nil {
+		return err
+	}
+	if err := a.twoFactor.Disable(ctx, userID); err != nil {
+		return err
+	}
+	log.Printf("2FA отключена: user_id=%s", userID)
+	return nil
+}
+
+// verifySecondFactor принимает код TOTP (каждый не больше одного раза)
+// или неиспользованный код восстановления
+func (a *authServices) verifySecondFactor(ctx context.Context, userID uuid.UUID, code string) error {
+	twoFactor, err := a.twoFactor.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil || !twoFactor.Enabled() {
+		return ErrTwoFactorNotSetUp
+	}
+
+	if step, ok := verifyTOTP(twoFactor.Secret, code, time.Now()); ok {
+		used, err := a.twoFactor.UseStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidCode
+		}
+		return nil
+	}
+
+	used, err := a.twoFactor.UseRecoveryCode(ctx, userID, utils.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidCode
+	}
+	log.Printf("Использован код восстановления 2FA: user_id=%s", userID)
+	return nil
+}
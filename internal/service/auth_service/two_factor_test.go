@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	authModel "tic-tac-toe/internal/domain/model/auth"
+	moderationModel "tic-tac-toe/internal/domain/model/moderation"
+	jwtService "tic-tac-toe/internal/service/jwt_service"
+	moderationService "tic-tac-toe/internal/service/moderation_service"
+	userService "tic-tac-toe/internal/service/user_service"
+	"tic-tac-toe/internal/storage/memory"
+	dto "tic-tac-toe/internal/web/dto"
+
+	"github.com/google/uuid"
+)
+
+// секрет из тестовых векторов RFC 6238 (SHA-1): "12345678901234567890"
+var rfcSecret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	// RFC 6238, приложение B: последние шесть цифр восьмизначных кодов
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := totpCode(rfcSecret, totpStep(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("totpCode(%d): %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("totpCode(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+	// секрет из приложения принимается и в нижнем регистре
+	if got, _ := totpCode(strings.ToLower(rfcSecret), 1); got != "287082" {
+		t.Errorf("lower-case secret: code = %s, want 287082", got)
+	}
+}
+
+func TestVerifyTOTPWindow(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := totpStep(now)
+
+	for delta := int64(-2); delta <= 2; delta++ {
+		code, err := totpCode(rfcSecret, current+delta)
+		if err != nil {
+			t.Fatalf("totpCode: %v", err)
+		}
+		step, ok := verifyTOTP(rfcSecret, code, now)
+		if want := delta >= -totpSkew && delta <= totpSkew; ok != want {
+			t.Errorf("step %+d: accepted = %v, want %v", delta, ok, want)
+			continue
+		}
+		if ok && step != current+delta {
+			t.Errorf("step %+d: matched step %d, want %d", delta, step, current+delta)
+		}
+	}
+	for _, code := range []string{"", "05047", "0504711", "xh6c2-pzvxc"} {
+		if _, ok := verifyTOTP(rfcSecret, code, now); ok {
+			t.Errorf("code %q accepted", code)
+		}
+	}
+}
+
+// noBan - ни у кого нет действующего бана
+type noBan struct {
+	moderationService.ModerationService
+}
+
+func (noBan) ActiveBan(ctx context.Context, userID uuid.UUID) (*moderationModel.Sanction, error) {
+	return nil, nil
+}
+
+type twoFactorFixture struct {
+	auth      *authServices
+	twoFactor authModel.TwoFactorRepository
+	userID    uuid.UUID
+	login     string
+	recovery  []string
+}
+
+// newTwoFactorFixture регистрирует пользователя и включает ему 2FA
+func newTwoFactorFixture(t *testing.T) twoFactorFixture {
+	t.Helper()
+	ctx := context.Background()
+	store := memory.NewStore()
+	tx := memory.NewTxManager(store)
+	keys, err := jwtService.LoadKeySet("", nil, "", []byte("secret"))
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+	users := userService.NewUserServices(memory.NewUserRepository(store), tx)
+	f := twoFactorFixture{twoFactor: memory.NewTwoFactorRepository(store), login: "two_factor"}
+	f.auth = &authServices{
+		user:       users,
+		jwt:        jwtService.NewJwtProvider(keys),
+		tokens:     memory.NewTokenRepository(store),
+		attempts:   memory.NewLoginAttemptRepository(store),
+		twoFactor:  f.twoFactor,
+		moderation: noBan{},
+		tx:         tx,
+	}
+
+	user, err := users.Register(ctx, dto.SignUpRequest{Login: f.login, Password: "secret-pw"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	f.userID = user.UUID
+	secret, _, err := f.auth.SetupTwoFactor(ctx, f.userID)
+	if err != nil {
+		t.Fatalf("SetupTwoFactor: %v", err)
+	}
+	code, _ := totpCode(secret, totpStep(time.Now()))
+	if f.recovery, err = f.auth.EnableTwoFactor(ctx, f.userID, code); err != nil {
+		t.Fatalf("EnableTwoFactor: %v", err)
+	}
+	if len(f.recovery) != recoveryCodeCount {
+		t.Fatalf("got %d recovery codes, want %d", len(f.recovery), recoveryCodeCount)
+	}
+	return f
+}
+
+// code возвращает код шага last+delta, где last - последний принятый шаг
+func (f twoFactorFixture) code(t *testing.T, delta int64) string {
+	t.Helper()
+	tf, err := f.twoFactor.Get(context.Background(), f.userID)
+	if err != nil || tf == nil {
+		t.Fatalf("Get 2FA: %v, %v", tf, err)
+	}
+	code, err := totpCode(tf.Secret, tf.LastUsedStep+delta)
+	if err != nil {
+		t.Fatalf("totpCode: %v", err)
+	}
+	return code
+}
+
+func (f twoFactorFixture) challenge(t *testing.T) string {
+	t.Helper()
+	res, err := f.auth.Login(context.Background(), dto.JwtRequest{Login: f.login, Password: "secret-pw"}, authModel.Client{})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if !res.TwoFactorRequired || res.ChallengeToken == "" {
+		t.Fatalf("Login = %+v, want a challenge token", res)
+	}
+	return res.ChallengeToken
+}
+
+func TestTOTPStepUsedOnce(t *testing.T) {
+	ctx := context.Background()
+	f := newTwoFactorFixture(t)
+
+	// код, которым 2FA включали, уже погашен
+	if err := f.auth.verifySecondFactor(ctx, f.userID, f.code(t, 0)); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("enabling code: err = %v, want ErrInvalidCode", err)
+	}
+	next := f.code(t, 1)
+	if err := f.auth.verifySecondFactor(ctx, f.userID, next); err != nil {
+		t.Fatalf("next step: %v", err)
+	}
+	if err := f.auth.verifySecondFactor(ctx, f.userID, next); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("same step again: err = %v, want ErrInvalidCode", err)
+	}
+}
+
+func TestRecoveryCodeUsedOnce(t *testing.T) {
+	ctx := context.Background()
+	f := newTwoFactorFixture(t)
+
+	// код принимается в любом регистре и с пробелами вокруг
+	if err := f.auth.verifySecondFactor(ctx, f.userID, " "+strings.ToUpper(f.recovery[0])+" "); err != nil {
+		t.Fatalf("recovery code: %v", err)
+	}
+	if err := f.auth.verifySecondFactor(ctx, f.userID, f.recovery[0]); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("recovery code again: err = %v, want ErrInvalidCode", err)
+	}
+	if err := f.auth.verifySecondFactor(ctx, f.userID, f.recovery[1]); err != nil {
+		t.Errorf("another recovery code: %v", err)
+	}
+}
+
+func TestDisableTwoFactorRequiresCode(t *testing.T) {
+	ctx := context.Background()
+	f := newTwoFactorFixture(t)
+
+	if err := f.auth.DisableTwoFactor(ctx, f.userID, "wrong-code"); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("wrong code: err = %v, want ErrInvalidCode", err)
+	}
+	if tf, _ := f.twoFactor.Get(ctx, f.userID); tf == nil || !tf.Enabled() {
+		t.Fatal("2FA disabled with a wrong code")
+	}
+
+	if err := f.auth.DisableTwoFactor(ctx, f.userID, f.code(t, 1)); err != nil {
+		t.Fatalf("valid code: %v", err)
+	}
+	if tf, _ := f.twoFactor.Get(ctx, f.userID); tf != nil {
+		t.Errorf("2FA = %+v after disable, want none", tf)
+	}
+}
+
+func TestLoginTwoFactorChallengeUsedOnce(t *testing.T) {
+	ctx := context.Background()
+	f := newTwoFactorFixture(t)
+	challenge := f.challenge(t)
+
+	// неверный код не гасит challenge токен
+	if _, err := f.auth.LoginTwoFactor(ctx, challenge, "wrong-code", authModel.Client{}); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("wrong code: err = %v, want ErrInvalidCode", err)
+	}
+	tokens, err := f.auth.LoginTwoFactor(ctx, challenge, f.code(t, 1), authModel.Client{})
+	if err != nil {
+		t.Fatalf("LoginTwoFactor: %v", err)
+	}
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Errorf("tokens = %+v, want a pair", tokens)
+	}
+
+	// тот же токен с другим верным кодом отклоняется
+	if _, err := f.auth.LoginTwoFactor(ctx, challenge, f.recovery[0], authModel.Client{}); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("reused challenge: err = %v, want ErrTokenInvalid", err)
+	}
+	// новый вход выдаёт новый challenge токен
+	if _, err := f.auth.LoginTwoFactor(ctx, f.challenge(t), f.recovery[1], authModel.Client{}); err != nil {
+		t.Errorf("new challenge: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/game"
+)
+
+type subscriber struct {
+	name    string
+	handler Handler
+	types   []model.EventType
+}
+
+func (s subscriber) wants(event model.Event) bool {
+	return len(s.types) == 0 || slices.Contains(s.types, event.Type)
+}
+
+type eventBus struct {
+	repo        model.OutboxRepository
+	subscribers []subscriber
+	wake        chan struct{}
+}
+
+func NewEventBus(repo model.OutboxRepository) EventBus {
+	return &eventBus{
+		repo: repo,
+		wake: make(chan struct{}, 1),
+	}
+}
+
+func (b *eventBus) Subscribe(name string, handler Handler, types ...model.EventType) {
+	b.subscribers = append(b.subscribers, subscriber{name: name, handler: handler, types: types})
+}
+
+func (b *eventBus) Wake() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (b *eventBus) Wakeups() <-chan struct{} {
+	return b.wake
+}
+
+func (b *eventBus) Dispatch(ctx context.Context) (int, error) {
+	records, err := b.repo.Claim(ctx, time.Now(), DispatchLease, DispatchBatch)
+	if err != nil {
+		return 0, err
+	}
+	// ошибка сохранения результата одного события не останавливает пачку:
+	// это событие вернётся после истечения аренды
+	var failed []error
+	for _, record := range records {
+		// при остановке оставшиеся события не трогаем, чтобы не тратить их попытки
+		if ctx.Err() != nil {
+			break
+		}
+		if err := b.deliver(ctx, record); err != nil {
+			failed = append(failed, fmt.Errorf("событие %d: %w", record.Event.ID, err))
+		}
+	}
+	return len(records), errors.Join(failed...)
+}
+
+// deliver передаёт событие подписчикам, которые его ещё не обработали.
+// Если кто-то из них вернул ошибку, событие откладывается, а успешные отмечаются в done;
+// после MaxAttempts неудачных попыток событие переводится в dead
+func (b *eventBus) deliver(ctx context.Context, record model.OutboxRecord) error {
+	event := record.Event
+	var failed []error
+	for _, sub := range b.subscribers {
+		if !sub.wants(event) || slices.Contains(record.Done, sub.name) {
+			continue
+		}
+		if err := b.call(ctx, sub, event); err != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", sub.name, err))
+			continue
+		}
+		record.Done = append(record.Done, sub.name)
+	}
+	// результат сохраняется и при остановке: иначе обработанное придётся повторить
+	ctx = context.WithoutCancel(ctx)
+	if len(failed) == 0 {
+		return b.repo.Complete(ctx, event.ID, time.Now())
+	}
+
+	record.Attempts++
+	err := errors.Join(failed...)
+	if record.Attempts >= MaxAttempts {
+		log.Printf("Событие %d (%s, game_id=%s) не обработано за %d попыток и отложено в dead: %v",
+			event.ID, event.Type, event.GameID, record.Attempts, err)
+		return b.repo.DeadLetter(ctx, record, err.Error())
+	}
+	log.Printf("Ошибка обработки события %d (%s, game_id=%s), попытка %d: %v",
+		event.ID, event.Type, event.GameID, record.Attempts, err)
+	return b.repo.Retry(ctx, record, err.Error(), time.Now().Add(retryDelay(record.Attempts)))
+}
+
+// call вызывает обработчик; паника обработчика считается ошибкой и не останавливает диспетчер
+func (b *eventBus) call(ctx context.Context, sub subscriber, event model.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return sub.handler(ctx, event)
+}
+
+func (b *eventBus) Cleanup(ctx context.Context) (int64, error) {
+	return b.repo.DeleteProcessed(ctx, time.Now().Add(-ProcessedRetention))
+}
+
+// retryDelay - задержка после attempt неудачных попыток
+func retryDelay(attempt int) time.Duration {
+	delay := RetryBaseDelay
+	for i := 1; i < attempt && delay < RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, RetryMaxDelay)
+}
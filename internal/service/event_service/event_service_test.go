@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/game"
+	"tic-tac-toe/internal/storage/memory"
+
+	"github.com/google/uuid"
+)
+
+// brokenComplete не может отметить обработанным первое событие и запоминает его
+type brokenComplete struct {
+	model.OutboxRepository
+	failed int64
+}
+
+func (r *brokenComplete) Complete(ctx context.Context, id int64, now time.Time) error {
+	if r.failed == 0 {
+		r.failed = id
+		return errors.New("db is down")
+	}
+	return r.OutboxRepository.Complete(ctx, id, now)
+}
+
+// newOutbox сохраняет игру с n событиями и возвращает outbox хранилища
+func newOutbox(t *testing.T, n int) model.OutboxRepository {
+	t.Helper()
+	store := memory.NewStore()
+	game := model.Game{
+		UUID:    uuid.New(),
+		Field:   &model.GameField{Field: [][]int{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}}},
+		Status:  model.Playing,
+		PlayerX: uuid.New(),
+	}
+	events := make([]model.Event, n)
+	for i := range events {
+		events[i] = model.MoveMade(game, game.PlayerX)
+	}
+	if err := memory.NewGameRepository(store).SaveGame(context.Background(), game, events...); err != nil {
+		t.Fatalf("SaveGame: %v", err)
+	}
+	return memory.NewOutboxRepository(store)
+}
+
+// pending - события, которые выдаст следующий Claim после истечения аренды
+func pending(t *testing.T, repo model.OutboxRepository) []model.OutboxRecord {
+	t.Helper()
+	records, err := repo.Claim(context.Background(), time.Now().Add(DispatchLease+RetryMaxDelay), time.Second, DispatchBatch)
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	return records
+}
+
+func TestDispatchContinuesAfterFailure(t *testing.T) {
+	repo := &brokenComplete{OutboxRepository: newOutbox(t, 3)}
+	bus := NewEventBus(repo)
+	var handled []int64
+	bus.Subscribe("test", func(ctx context.Context, event model.Event) error {
+		handled = append(handled, event.ID)
+		return nil
+	})
+
+	n, err := bus.Dispatch(context.Background())
+	if n != 3 || err == nil {
+		t.Fatalf("Dispatch = %d, %v; want 3 and the error of the first event", n, err)
+	}
+	if len(handled) != 3 {
+		t.Errorf("handled %v, want all 3 events", handled)
+	}
+	// отмечены обработанными все, кроме сломанного
+	if left := pending(t, repo); len(left) != 1 || left[0].Event.ID != repo.failed {
+		t.Errorf("pending = %+v, want only event %d", left, repo.failed)
+	}
+}
+
+// laterOutbox сдвигает время выдачи событий вперёд: повторы наступают без ожидания задержки
+type laterOutbox struct {
+	model.OutboxRepository
+	skew time.Duration
+}
+
+func (r *laterOutbox) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.OutboxRecord, error) {
+	return r.OutboxRepository.Claim(ctx, now.Add(r.skew), lease, limit)
+}
+
+func TestDispatchDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	repo := &laterOutbox{OutboxRepository: newOutbox(t, 1)}
+	bus := NewEventBus(repo)
+	calls := 0
+	bus.Subscribe("broken", func(ctx context.Context, event model.Event) error {
+		calls++
+		return errors.New("handler is broken")
+	})
+
+	for range MaxAttempts + 2 {
+		if _, err := bus.Dispatch(ctx); err != nil {
+			t.Fatalf("Dispatch: %v", err)
+		}
+		repo.skew += RetryMaxDelay
+	}
+	if calls != MaxAttempts {
+		t.Errorf("handler called %d times, want %d", calls, MaxAttempts)
+	}
+	if left := pending(t, repo); len(left) != 0 {
+		t.Errorf("dead event still pending: %+v", left)
+	}
+	// dead-события не считаются обработанными и не удаляются
+	if n, err := repo.DeleteProcessed(ctx, time.Now().Add(24*time.Hour)); err != nil || n != 0 {
+		t.Errorf("DeleteProcessed = %d, %v; want the dead event kept", n, err)
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/game"
+)
+
+const (
+	// DispatchBatch - сколько событий outbox забирается за один проход
+	DispatchBatch = 100
+	// пока событие обрабатывается, оно не выдаётся другим инстансам
+	DispatchLease = time.Minute
+	// повтор после ошибки подписчика: RetryBaseDelay, дальше удваивается до RetryMaxDelay
+	RetryBaseDelay = time.Second
+	RetryMaxDelay  = 5 * time.Minute
+	// MaxAttempts - после стольких неудачных попыток событие переводится в dead
+	// и больше не повторяется (около часа с учётом задержек)
+	MaxAttempts = 20
+	// ProcessedRetention - сколько хранятся обработанные события
+	ProcessedRetention = 7 * 24 * time.Hour
+)
+
+// Handler обрабатывает событие. Доставка "хотя бы один раз": после ошибки или
+// сбоя инстанса событие придёт повторно, поэтому обработчик должен быть идемпотентным
+type Handler func(ctx context.Context, event model.Event) error
+
+// EventBus доставляет события игр из outbox подписчикам внутри приложения
+type EventBus interface {
+	// Subscribe подписывает обработчик name на события types (без types - на все).
+	// name сохраняется в outbox как отметка об обработке, поэтому его нельзя менять.
+	// Подписка выполняется при сборке приложения, до запуска диспетчера
+	Subscribe(name string, handler Handler, types ...model.EventType)
+	// Wake сообщает диспетчеру этого инстанса, что в outbox записаны новые события
+	Wake()
+	// Wakeups - сигналы Wake для цикла диспетчера
+	Wakeups() <-chan struct{}
+	// Dispatch обрабатывает очередную пачку событий и возвращает их число; ошибка
+	// одного события не прерывает пачку и возвращается вместе с остальными
+	Dispatch(ctx context.Context) (int, error)
+	// Cleanup удаляет события, обработанные дольше ProcessedRetention назад
+	Cleanup(ctx context.Context) (int64, error)
+}
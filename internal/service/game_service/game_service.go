@@ -2,8 +2,12 @@ package service
 
 import (
 	"context"
+	"log"
 	"slices"
 	model "tic-tac-toe/internal/domain/model/game"
+	eventService "tic-tac-toe/internal/service/event_service"
+	seasonService "tic-tac-toe/internal/service/season_service"
+	userService "tic-tac-toe/internal/service/user_service"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,54 +24,76 @@ const (
 )
 
 type gameService struct {
-	repo model.GameRepository
+	repo    model.GameRepository
+	seasons seasonService.SeasonService
+	friends userService.FriendService
+	events  eventService.EventBus
 }
 
-func NewGameService(repo model.GameRepository) GameServices {
+func NewGameService(repo model.GameRepository, seasons seasonService.SeasonService, friends userService.FriendService,
+	events eventService.EventBus) GameServices {
 	return &gameService{
-		repo: repo,
+		repo:    repo,
+		seasons: seasons,
+		friends: friends,
+		events:  events,
 	}
 }
 
 // создание новой игры
 func (service *gameService) CreateNewGame(ctx context.Context, playerX uuid.UUID, withBot bool) (model.Game, error) {
-	newUUID := uuid.New()
-
-	newField := model.GameField{
-		Field: [][]int{
-			{Empty, Empty, Empty},
-			{Empty, Empty, Empty},
-			{Empty, Empty, Empty},
-		},
-	}
-	var status model.GameStatus
-	var playerO *uuid.UUID
+	status := model.Waiting
 	if withBot {
-		playerO = nil
 		status = model.Playing
-	} else {
-		playerO = nil
-		status = model.Waiting
 	}
+	game := newGame(playerX, status)
 
-	newGame := model.Game{
-		UUID:        newUUID,
-		Field:       &newField,
+	return game, service.save(ctx, game, model.GameCreated(game, &playerX))
+}
+
+// newGame - игра с пустым полем, X ходит первым
+func newGame(playerX uuid.UUID, status model.GameStatus) model.Game {
+	return model.Game{
+		UUID: uuid.New(),
+		Field: &model.GameField{
+			Field: [][]int{
+				{Empty, Empty, Empty},
+				{Empty, Empty, Empty},
+				{Empty, Empty, Empty},
+			},
+		},
 		Status:      status,
 		PlayerX:     playerX,
-		PlayerO:     playerO,
 		CurrentTurn: playerX,
 		Symbols: map[uuid.UUID]model.Char{
 			playerX: model.CharX,
 		},
 		DateCreate: time.Now(),
 	}
-
-	return newGame, service.repo.SaveGame(ctx, newGame)
 }
 
-func (service *gameService) GetAvailableGames(ctx context.Context) ([]model.Game, error) {
-	return service.repo.GetAvailableGames(ctx)
+func (service *gameService) GetAvailableGames(ctx context.Context, userID uuid.UUID, friendsOnly bool) ([]model.Game, error) {
+	games, err := service.repo.GetAvailableGames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := service.friends.BlockedIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var friends []uuid.UUID
+	if friendsOnly {
+		if friends, err = service.friends.FriendIDs(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return slices.DeleteFunc(games, func(g model.Game) bool {
+		if slices.Contains(blocked, g.PlayerX) {
+			return true
+		}
+		return friendsOnly && !slices.Contains(friends, g.PlayerX)
+	}), nil
 }
 
 func (service *gameService) GetComplitedGames(ctx context.Context, userID uuid.UUID) ([]model.Game, error) {
@@ -88,21 +114,111 @@ func (service *gameService) JoinGame(ctx context.Context, gameID, playerO uuid.U
 	if gameCurrent.PlayerX == playerO {
 		return model.Game{}, ErrCannotJoinOwn
 	}
+	blocked, err := service.friends.IsBlockedBetween(ctx, gameCurrent.PlayerX, playerO)
+	if err != nil {
+		return model.Game{}, err
+	}
+	if blocked {
+		return model.Game{}, ErrPlayerBlocked
+	}
 	//обновляем игру
 	gameCurrent.Symbols[playerO] = model.CharO
 	gameCurrent.Status = model.Playing
 	gameCurrent.PlayerO = &playerO
 	gameCurrent.CurrentTurn = gameCurrent.PlayerX
 
-	return gameCurrent, service.repo.SaveGame(ctx, gameCurrent)
+	return gameCurrent, service.save(ctx, gameCurrent, model.PlayerJoined(gameCurrent, playerO))
+}
+
+func (service *gameService) CreatePairedGame(ctx context.Context, playerX, playerO uuid.UUID) (model.Game, error) {
+	if playerX == playerO {
+		return model.Game{}, ErrSamePlayer
+	}
+	game := newGame(playerX, model.Playing)
+	game.Symbols[playerO] = model.CharO
+	game.PlayerO = &playerO
+
+	return game, service.save(ctx, game, model.GameCreated(game, nil))
 }
 
 func (service *gameService) GetCurrentGame(ctx context.Context, gameID uuid.UUID) (model.Game, error) {
 	return service.repo.GetCurrentGame(ctx, gameID)
 }
 
+func (service *gameService) DeleteGame(ctx context.Context, gameID uuid.UUID) error {
+	deleted, err := service.repo.DeleteGame(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrGameNotFound
+	}
+	log.Printf("Игра удалена: game_id=%s", gameID)
+	return nil
+}
+
+func (service *gameService) CancelWaitingGames(ctx context.Context, playerID uuid.UUID) (int64, error) {
+	return service.repo.CancelWaitingGames(ctx, playerID)
+}
+
+// таблица лидеров текущего сезона
 func (service *gameService) GetLeaderBoard(ctx context.Context, count int) ([]model.UserLeaders, error) {
-	return service.repo.GetLeaderBoard(ctx, count)
+	season, err := service.seasons.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return service.repo.GetLeaderBoard(ctx, season.UUID, count)
+}
+
+// GetHeadToHead считает статистику личных встреч playerID против opponentID
+// lastCount - сколько последних игр вернуть в ответе (0 - ни одной)
+func (service *gameService) GetHeadToHead(ctx context.Context, playerID, opponentID uuid.UUID, lastCount int) (model.HeadToHead, error) {
+	if playerID == opponentID {
+		return model.HeadToHead{}, ErrSamePlayer
+	}
+	games, err := service.repo.GetGamesBetween(ctx, playerID, opponentID)
+	if err != nil {
+		return model.HeadToHead{}, err
+	}
+
+	result := model.HeadToHead{
+		PlayerID:   playerID,
+		OpponentID: opponentID,
+		Total:      len(games),
+	}
+	// игры отсортированы от новых к старым
+	streakOpen := true
+	for i, game := range games {
+		winner := game.Winner()
+		switch {
+		case winner == nil:
+			result.Draws++
+		case *winner == playerID:
+			result.PlayerWins++
+		default:
+			result.OpponentWins++
+		}
+
+		if !streakOpen {
+			continue
+		}
+		if i == 0 {
+			if winner == nil {
+				streakOpen = false
+				continue
+			}
+			result.Streak = model.Streak{Winner: winner, Count: 1}
+			continue
+		}
+		if winner != nil && *winner == *result.Streak.Winner {
+			result.Streak.Count++
+		} else {
+			streakOpen = false
+		}
+	}
+
+	result.LastGames = games[:min(max(lastCount, 0), len(games))]
+	return result, nil
 }
 
 // MakeMove обрабатывает ход игрока
@@ -137,7 +253,7 @@ func (service *gameService) MakeMove(ctx context.Context, gameID, playerID uuid.
 	// Проверяем окончание игры после хода игрока
 	if status := service.CheckEndGame(gameCurrent); status != model.Playing {
 		gameCurrent.Status = status
-		return gameCurrent, service.repo.SaveGame(ctx, gameCurrent)
+		return gameCurrent, service.finishGame(ctx, gameCurrent, playerID)
 	}
 
 	///===== Игра с ботом ======
@@ -153,12 +269,12 @@ func (service *gameService) MakeMove(ctx context.Context, gameID, playerID uuid.
 		// Проверяем окончание игры после хода бота
 		if status := service.CheckEndGame(gameCurrent); status != model.Playing {
 			gameCurrent.Status = status
-			return gameCurrent, service.repo.SaveGame(ctx, gameCurrent)
+			return gameCurrent, service.finishGame(ctx, gameCurrent, playerID)
 		}
 
 		// Возвращаем ход игроку X
 		gameCurrent.CurrentTurn = gameCurrent.PlayerX
-		return gameCurrent, service.repo.SaveGame(ctx, gameCurrent)
+		return gameCurrent, service.save(ctx, gameCurrent, model.MoveMade(gameCurrent, playerID))
 	}
 	// Игра между двумя игроками - меняем текущего игрока
 	if gameCurrent.CurrentTurn == gameCurrent.PlayerX {
@@ -167,7 +283,27 @@ func (service *gameService) MakeMove(ctx context.Context, gameID, playerID uuid.
 		gameCurrent.CurrentTurn = gameCurrent.PlayerX
 	}
 
-	return gameCurrent, service.repo.SaveGame(ctx, gameCurrent)
+	return gameCurrent, service.save(ctx, gameCurrent, model.MoveMade(gameCurrent, playerID))
+}
+
+// сохраняет завершённую игру в текущем сезоне; достижения, турниры, уведомления
+// и вебхуки обрабатывают событие GameFinished
+func (service *gameService) finishGame(ctx context.Context, game model.Game, mover uuid.UUID) error {
+	season, err := service.seasons.Current(ctx)
+	if err != nil {
+		return err
+	}
+	game.SeasonID = &season.UUID
+	return service.save(ctx, game, model.MoveMade(game, mover), model.GameFinished(game, mover))
+}
+
+// save сохраняет игру вместе с событиями и будит диспетчер событий
+func (service *gameService) save(ctx context.Context, game model.Game, events ...model.Event) error {
+	if err := service.repo.SaveGame(ctx, game, events...); err != nil {
+		return err
+	}
+	service.events.Wake()
+	return nil
 }
 
 // получение следующего хода
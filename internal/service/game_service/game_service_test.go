@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	model "tic-tac-toe/internal/domain/model/game"
+	userModel "tic-tac-toe/internal/domain/model/user"
+	eventService "tic-tac-toe/internal/service/event_service"
+	userService "tic-tac-toe/internal/service/user_service"
+	"tic-tac-toe/internal/storage/contract"
+	"tic-tac-toe/internal/storage/memory"
+	"tic-tac-toe/internal/storage/postgres"
+
+	"github.com/google/uuid"
+)
+
+// quietBus - шина без подписчиков: ходы только будят диспетчер
+type quietBus struct {
+	eventService.EventBus
+}
+
+func (quietBus) Wake() {}
+
+// barrierRepository отдаёт игру только после того, как её прочитали все
+// участники гонки: все ходы строятся поверх одной версии
+type barrierRepository struct {
+	model.GameRepository
+	read *sync.WaitGroup
+}
+
+func (r barrierRepository) GetCurrentGame(ctx context.Context, id uuid.UUID) (model.Game, error) {
+	game, err := r.GameRepository.GetCurrentGame(ctx, id)
+	r.read.Done()
+	r.read.Wait()
+	return game, err
+}
+
+func TestMakeMoveConcurrent(t *testing.T) {
+	backends := map[string]func(t *testing.T) (model.GameRepository, userModel.UserRepository){
+		"Memory": func(t *testing.T) (model.GameRepository, userModel.UserRepository) {
+			store := memory.NewStore()
+			return memory.NewGameRepository(store), memory.NewUserRepository(store)
+		},
+		"Postgres": func(t *testing.T) (model.GameRepository, userModel.UserRepository) {
+			pool := contract.PostgresPool(t)
+			return postgres.NewGameRepository(pool), postgres.NewUserRepository(pool)
+		},
+	}
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			testMakeMoveConcurrent(t, backend)
+		})
+	}
+}
+
+func testMakeMoveConcurrent(t *testing.T, backend func(t *testing.T) (model.GameRepository, userModel.UserRepository)) {
+	ctx := context.Background()
+	games, users := backend(t)
+
+	// игроки нужны Postgres: игры ссылаются на пользователей
+	var players [2]uuid.UUID
+	for i := range players {
+		players[i] = uuid.New()
+		user := userModel.User{UUID: players[i], Login: "move_" + players[i].String()[:8], Password: "hash"}
+		if err := users.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+	}
+	x, o := players[0], players[1]
+	game := newGame(x, model.Playing)
+	game.PlayerO = &o
+	game.Symbols[o] = model.CharO
+	if err := games.SaveGame(ctx, game); err != nil {
+		t.Fatalf("SaveGame: %v", err)
+	}
+
+	// X одновременно ходит в разные клетки: каждый ход корректен сам по себе
+	const movers = 9
+	var read sync.WaitGroup
+	read.Add(movers)
+	service := NewGameService(barrierRepository{GameRepository: games, read: &read}, nil, nil, quietBus{})
+
+	errs := make(chan error, movers)
+	var wg sync.WaitGroup
+	for i := range movers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			field := &model.GameField{Field: [][]int{{Empty, Empty, Empty}, {Empty, Empty, Empty}, {Empty, Empty, Empty}}}
+			field.Field[i/3][i%3] = X
+			_, err := service.MakeMove(ctx, game.UUID, x, field)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	moved := 0
+	for err := range errs {
+		var conflict *model.ConflictError
+		switch {
+		case err == nil:
+			moved++
+		case errors.As(err, &conflict):
+		default:
+			t.Errorf("MakeMove: err = %v, want nil or *ConflictError", err)
+		}
+	}
+	if moved != 1 {
+		t.Fatalf("%d of %d concurrent moves succeeded, want exactly 1", moved, movers)
+	}
+
+	got, err := games.GetCurrentGame(ctx, game.UUID)
+	if err != nil {
+		t.Fatalf("GetCurrentGame: %v", err)
+	}
+	marks := 0
+	for _, line := range got.Field.Field {
+		for _, cell := range line {
+			if cell != Empty {
+				marks++
+			}
+		}
+	}
+	if marks != 1 || got.CurrentTurn != o || got.Version != 2 {
+		t.Errorf("game after race: %d marks, turn %s, version %d; want 1 mark, O to move, version 2", marks, got.CurrentTurn, got.Version)
+	}
+}
+
+func TestHeadToHeadLastGames(t *testing.T) {
+	ctx := context.Background()
+	games := memory.NewGameRepository(memory.NewStore())
+	x, o := uuid.New(), uuid.New()
+	for range 3 {
+		game := newGame(x, model.WonX)
+		game.PlayerO = &o
+		if err := games.SaveGame(ctx, game); err != nil {
+			t.Fatalf("SaveGame: %v", err)
+		}
+	}
+	service := NewGameService(games, nil, nil, quietBus{})
+
+	tests := []struct {
+		lastCount int
+		want      int
+	}{
+		{0, 0},
+		{-1, 0},
+		{2, 2},
+		{3, 3},
+		{10, 3},
+	}
+	for _, tt := range tests {
+		h, err := service.GetHeadToHead(ctx, x, o, tt.lastCount)
+		if err != nil {
+			t.Fatalf("GetHeadToHead(%d): %v", tt.lastCount, err)
+		}
+		// счётчики не зависят от числа игр в ответе
+		if len(h.LastGames) != tt.want || h.Total != 3 || h.PlayerWins != 3 {
+			t.Errorf("GetHeadToHead(%d): %d last games, total %d, wins %d; want %d of 3 wins",
+				tt.lastCount, len(h.LastGames), h.Total, h.PlayerWins, tt.want)
+		}
+	}
+}
+
+// newLobbyFixture - игровой сервис с друзьями в памяти и n пользователей
+func newLobbyFixture(t *testing.T, n int) (GameServices, userModel.FriendRepository, []uuid.UUID) {
+	t.Helper()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		ids[i] = uuid.New()
+		if err := users.CreateUser(context.Background(), userModel.User{UUID: ids[i], Login: "lobby_" + ids[i].String()[:8], Password: "hash"}); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+	}
+	friends := memory.NewFriendRepository(store)
+	service := NewGameService(memory.NewGameRepository(store), nil, userService.NewFriendService(friends, users, nil), quietBus{})
+	return service, friends, ids
+}
+
+func TestJoinGameBlocked(t *testing.T) {
+	ctx := context.Background()
+	service, friends, ids := newLobbyFixture(t, 3)
+	owner, blocker, blocked := ids[0], ids[1], ids[2]
+
+	// блокировка действует независимо от того, кто её поставил
+	if _, err := friends.Block(ctx, blocker, owner); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	if _, err := friends.Block(ctx, owner, blocked); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	for _, playerO := range []uuid.UUID{blocker, blocked} {
+		game, err := service.CreateNewGame(ctx, owner, false)
+		if err != nil {
+			t.Fatalf("CreateNewGame: %v", err)
+		}
+		if _, err := service.JoinGame(ctx, game.UUID, playerO); !errors.Is(err, ErrPlayerBlocked) {
+			t.Errorf("join by %s: err = %v, want ErrPlayerBlocked", playerO, err)
+		}
+		if got, _ := service.GetCurrentGame(ctx, game.UUID); got.Status != model.Waiting || got.PlayerO != nil {
+			t.Errorf("game after refused join: status %v, player O %v; want waiting without O", got.Status, got.PlayerO)
+		}
+	}
+
+	if _, err := friends.Unblock(ctx, owner, blocked); err != nil {
+		t.Fatalf("Unblock: %v", err)
+	}
+	game, _ := service.CreateNewGame(ctx, owner, false)
+	if _, err := service.JoinGame(ctx, game.UUID, blocked); err != nil {
+		t.Errorf("join after unblock: %v", err)
+	}
+}
+
+func TestAvailableGamesFriendsOnly(t *testing.T) {
+	ctx := context.Background()
+	service, friends, ids := newLobbyFixture(t, 4)
+	viewer, friend, stranger, blocked := ids[0], ids[1], ids[2], ids[3]
+
+	friends.CreateRequest(ctx, viewer, friend)
+	if accepted, err := friends.Accept(ctx, viewer, friend); err != nil || !accepted {
+		t.Fatalf("Accept = %v, %v", accepted, err)
+	}
+	if _, err := friends.Block(ctx, blocked, viewer); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	owners := map[uuid.UUID]string{}
+	for name, id := range map[string]uuid.UUID{"friend": friend, "stranger": stranger, "blocked": blocked, "viewer": viewer} {
+		game, err := service.CreateNewGame(ctx, id, false)
+		if err != nil {
+			t.Fatalf("CreateNewGame: %v", err)
+		}
+		owners[game.PlayerX] = name
+	}
+
+	tests := []struct {
+		friendsOnly bool
+		want        []string
+	}{
+		{false, []string{"friend", "stranger", "viewer"}},
+		{true, []string{"friend"}},
+	}
+	for _, tt := range tests {
+		games, err := service.GetAvailableGames(ctx, viewer, tt.friendsOnly)
+		if err != nil {
+			t.Fatalf("GetAvailableGames(%v): %v", tt.friendsOnly, err)
+		}
+		var got []string
+		for _, g := range games {
+			got = append(got, owners[g.PlayerX])
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("GetAvailableGames(friendsOnly=%v) owners = %v, want %v", tt.friendsOnly, got, tt.want)
+		}
+	}
+}
@@ -16,6 +16,8 @@ var (
 	ErrGameNotWaiting = errors.New("game is not waiting")
 	ErrGameFull       = errors.New("game is already full")
 	ErrCannotJoinOwn  = errors.New("cannot join your own game")
+	ErrSamePlayer     = errors.New("players must be different")
+	ErrPlayerBlocked  = errors.New("cannot join this game")
 )
 
 type GameServices interface {
@@ -24,11 +26,22 @@ type GameServices interface {
 	ValidationField(myField, botField model.Game) error
 
 	CreateNewGame(ctx context.Context, playerX uuid.UUID, withBot bool) (model.Game, error)
-	GetAvailableGames(ctx context.Context) ([]model.Game, error)
+	// GetAvailableGames - лобби для userID: без игр тех, с кем есть блокировка;
+	// friendsOnly - только игры друзей
+	GetAvailableGames(ctx context.Context, userID uuid.UUID, friendsOnly bool) ([]model.Game, error)
 	GetComplitedGames(ctx context.Context, userID uuid.UUID) ([]model.Game, error)
+	// JoinGame присоединяет игрока к открытой игре; запрещено при блокировке между игроками
 	JoinGame(ctx context.Context, gameID, playerO uuid.UUID) (model.Game, error)
+	// CreatePairedGame создаёт игру двух заранее назначенных игроков (турнир);
+	// блокировки не проверяются - пары составляются автоматически
+	CreatePairedGame(ctx context.Context, playerX, playerO uuid.UUID) (model.Game, error)
 	MakeMove(ctx context.Context, gameID, player uuid.UUID, newField *model.GameField) (model.Game, error)
 	GetCurrentGame(ctx context.Context, gameID uuid.UUID) (model.Game, error)
+	// DeleteGame удаляет игру (администрирование)
+	DeleteGame(ctx context.Context, gameID uuid.UUID) error
+	// CancelWaitingGames отменяет открытые игры игрока (бан)
+	CancelWaitingGames(ctx context.Context, playerID uuid.UUID) (int64, error)
 
 	GetLeaderBoard(ctx context.Context, count int) ([]model.UserLeaders, error)
+	GetHeadToHead(ctx context.Context, playerID, opponentID uuid.UUID, lastCount int) (model.HeadToHead, error)
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"slices"
 	model "tic-tac-toe/internal/domain/model/user"
 
 	"time"
@@ -16,27 +17,35 @@ const (
 	Issuer           = "tic-tac-toe"      // кто выдал токен
 	TokenTypeAccess  = "access"
 	TokenTypeRefresh = "refresh"
+	// промежуточный токен входа с 2FA: только для обмена на пару токенов
+	TokenTypeChallenge = "2fa_challenge"
+	ChallengeTokenTTL  = 5 * time.Minute
 )
 
 type CustomClaims struct {
 	UserID uuid.UUID `json:"user_id"`
+	// роли пользователя; выдаются только в access-токене
+	Roles []string `json:"roles,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type jwtProvider struct {
-	secretKey []byte
+	keys *KeySet
 }
 
-func NewJwtProvider(secretKey []byte) JwtProvider {
+func NewJwtProvider(keys *KeySet) JwtProvider {
 	return &jwtProvider{
-		secretKey: secretKey,
+		keys: keys,
 	}
 }
-func (p *jwtProvider) generateToken(userID uuid.UUID, tokenType string, ttl time.Duration) (string, error) {
+func (p *jwtProvider) generateToken(userID uuid.UUID, roles []string, tokenType string, ttl time.Duration) (string, error) {
 	now := time.Now()
 	claims := CustomClaims{
 		UserID: userID,
+		Roles:  roles,
 		RegisteredClaims: jwt.RegisteredClaims{
+			// уникальный jti: токены, выданные в одну секунду, не совпадают
+			ID:        uuid.NewString(),
 			Issuer:    Issuer,
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
@@ -44,21 +53,23 @@ func (p *jwtProvider) generateToken(userID uuid.UUID, tokenType string, ttl time
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(p.secretKey)
+	return p.keys.sign(claims)
 }
 func (p *jwtProvider) GenerateAccessToken(user model.User) (string, error) {
-	return p.generateToken(user.UUID, TokenTypeAccess, AccessTokenTTL)
+	return p.generateToken(user.UUID, user.RoleNames(), TokenTypeAccess, AccessTokenTTL)
 }
 
 func (p *jwtProvider) GenerateRefreshToken(user model.User) (string, error) {
-	return p.generateToken(user.UUID, TokenTypeRefresh, RefreshTokenTTL)
+	return p.generateToken(user.UUID, nil, TokenTypeRefresh, RefreshTokenTTL)
+}
+
+func (p *jwtProvider) GenerateChallengeToken(user model.User) (string, error) {
+	return p.generateToken(user.UUID, nil, TokenTypeChallenge, ChallengeTokenTTL)
 }
 
 func (p *jwtProvider) parseToken(tokenStr string) (*CustomClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenStr, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return p.secretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenStr, &CustomClaims{}, p.keys.verificationKey,
+		jwt.WithValidMethods(p.keys.validMethods()))
 	if err != nil {
 		return nil, errors.New("invalid refresh token")
 	}
@@ -92,6 +103,17 @@ func (p *jwtProvider) ValidateRefreshToken(tokenStr string) (*CustomClaims, erro
 	return claims, nil
 }
 
+func (p *jwtProvider) ValidateChallengeToken(tokenStr string) (*CustomClaims, error) {
+	claims, err := p.parseToken(tokenStr)
+	if err != nil {
+		return nil, errors.New("invalid challenge token")
+	}
+	if claims.Subject != TokenTypeChallenge {
+		return nil, errors.New("token is not a challenge token")
+	}
+	return claims, nil
+}
+
 func (p *jwtProvider) GetUUIDFromToken(token string) (uuid.UUID, error) {
 	claims, err := p.parseToken(token)
 	if err != nil {
@@ -100,3 +122,12 @@ func (p *jwtProvider) GetUUIDFromToken(token string) (uuid.UUID, error) {
 
 	return claims.UserID, nil
 }
+
+// HasRole проверяет роль в клеймах токена
+func (c *CustomClaims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+func (p *jwtProvider) PublicKeys() []Key {
+	return p.keys.PublicKeys()
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ключ подписи/проверки токенов; Private == nil - ключ только для проверки
+// (например, старый ключ, который ещё нужен до истечения выданных им токенов)
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// набор ключей: один подписывает, все остальные принимаются при проверке по kid
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	// симметричный ключ HS256, если асимметричные ключи не настроены
+	secret []byte
+}
+
+// LoadKeySet загружает PEM-ключи из каталога dir (*.pem) и списка files.
+// kid ключа - имя файла без расширения. Приватные ключи (PKCS#8, PKCS#1)
+// используются для подписи и проверки, публичные (PKIX) - только для проверки.
+// Подписывает ключ signingKID, по умолчанию - последний по имени приватный ключ.
+// Если ключей нет, используется HS256 с secret.
+func LoadKeySet(dir string, files []string, signingKID string, secret []byte) (*KeySet, error) {
+	paths := slices.Clone(files)
+	if dir != "" {
+		matches, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения каталога ключей: %w", err)
+		}
+		paths = append(paths, matches...)
+	}
+
+	set := &KeySet{keys: map[string]*Key{}, secret: secret}
+	for _, path := range paths {
+		key, err := loadKey(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := set.keys[key.ID]; exists {
+			return nil, fmt.Errorf("ключ с kid %q загружен дважды", key.ID)
+		}
+		set.keys[key.ID] = key
+	}
+
+	if len(set.keys) == 0 {
+		if len(secret) == 0 {
+			return nil, errors.New("не настроены ключи подписи JWT")
+		}
+		return set, nil
+	}
+
+	if signingKID == "" {
+		for _, kid := range slices.Sorted(maps.Keys(set.keys)) {
+			if set.keys[kid].Private != nil {
+				signingKID = kid
+			}
+		}
+	}
+	signing, ok := set.keys[signingKID]
+	if !ok || signing.Private == nil {
+		return nil, fmt.Errorf("нет приватного ключа для подписи (kid %q)", signingKID)
+	}
+	set.signing = signing
+	return set, nil
+}
+
+// PublicKeys возвращает все ключи проверки для публикации в JWKS
+func (s *KeySet) PublicKeys() []Key {
+	result := make([]Key, 0, len(s.keys))
+	for _, kid := range slices.Sorted(maps.Keys(s.keys)) {
+		key := s.keys[kid]
+		result = append(result, Key{ID: key.ID, Method: key.Method, Public: key.Public})
+	}
+	return result
+}
+
+func (s *KeySet) sign(claims jwt.Claims) (string, error) {
+	if s.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	}
+	token := jwt.NewWithClaims(s.signing.Method, claims)
+	token.Header["kid"] = s.signing.ID
+	return token.SignedString(s.signing.Private)
+}
+
+// verificationKey выбирает ключ по kid и проверяет, что алгоритм токена
+// совпадает с алгоритмом ключа (защита от подмены alg)
+func (s *KeySet) verificationKey(token *jwt.Token) (interface{}, error) {
+	if s.signing == nil {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return s.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+	}
+	return key.Public, nil
+}
+
+// допустимые алгоритмы для парсера
+func (s *KeySet) validMethods() []string {
+	if s.signing == nil {
+		return []string{jwt.SigningMethodHS256.Alg()}
+	}
+	var methods []string
+	for _, key := range s.keys {
+		if !slices.Contains(methods, key.Method.Alg()) {
+			methods = append(methods, key.Method.Alg())
+		}
+	}
+	return methods
+}
+
+func loadKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения ключа %s: %w", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("файл %s не содержит PEM", path)
+	}
+	kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("неподдерживаемый тип PEM %q в %s", block.Type, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора ключа %s: %w", path, err)
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, Private: k, Public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, Public: k}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, Private: k, Public: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, Public: k}, nil
+	default:
+		return nil, fmt.Errorf("ключ %s: поддерживаются только RSA и Ed25519", path)
+	}
+}
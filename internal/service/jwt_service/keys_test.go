@@ -0,0 +1,288 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/user"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var testUser = model.User{UUID: uuid.MustParse("00000000-0000-0000-0000-000000000001"), Login: "alice"}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	return key
+}
+
+func newEd25519Key(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey: %v", err)
+	}
+	return key
+}
+
+// writePrivate сохраняет приватный ключ в PKCS#8 PEM и возвращает путь
+func writePrivate(t *testing.T, dir, kid string, key crypto.Signer) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+	return writePEM(t, dir, kid, "PRIVATE KEY", der)
+}
+
+// writePublic сохраняет публичный ключ в PKIX PEM и возвращает путь
+func writePublic(t *testing.T, dir, kid string, key crypto.PublicKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+	return writePEM(t, dir, kid, "PUBLIC KEY", der)
+}
+
+func writePEM(t *testing.T, dir, kid, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, kid+".pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func loadProvider(t *testing.T, dir string, files []string, signingKID string) JwtProvider {
+	t.Helper()
+	keys, err := LoadKeySet(dir, files, signingKID, nil)
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+	return NewJwtProvider(keys)
+}
+
+// kidOf возвращает kid из заголовка токена без проверки подписи
+func kidOf(t *testing.T, token string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &CustomClaims{})
+	if err != nil {
+		t.Fatalf("ParseUnverified: %v", err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+// signWith подписывает access-токен произвольным ключом и заголовком kid
+func signWith(t *testing.T, method jwt.SigningMethod, kid string, key interface{}) string {
+	t.Helper()
+	now := time.Now()
+	token := jwt.NewWithClaims(method, CustomClaims{
+		UserID: testUser.UUID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer,
+			Subject:   TokenTypeAccess,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func TestLoadKeySetFromDir(t *testing.T) {
+	dir := t.TempDir()
+	writePrivate(t, dir, "2026-01", newRSAKey(t))
+	writePrivate(t, dir, "2026-02", newEd25519Key(t))
+	writePublic(t, dir, "2026-03", newRSAKey(t).Public())
+	// не *.pem - не загружается
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a key"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	p := loadProvider(t, dir, nil, "")
+	keys := p.PublicKeys()
+	if len(keys) != 3 || keys[0].ID != "2026-01" || keys[1].ID != "2026-02" || keys[2].ID != "2026-03" {
+		t.Fatalf("PublicKeys = %+v, want 2026-01, 2026-02, 2026-03", keys)
+	}
+	for _, key := range keys {
+		if key.Private != nil {
+			t.Errorf("PublicKeys exposes the private key of %s", key.ID)
+		}
+	}
+
+	// по умолчанию подписывает последний по имени приватный ключ, публичный пропускается
+	token, err := p.GenerateAccessToken(testUser)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	if kid := kidOf(t, token); kid != "2026-02" {
+		t.Errorf("signing kid = %q, want 2026-02", kid)
+	}
+	claims, err := p.ValidateAccessToken(token)
+	if err != nil || claims.UserID != testUser.UUID {
+		t.Errorf("ValidateAccessToken = %+v, %v", claims, err)
+	}
+}
+
+func TestLoadKeySetFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	rsaPKCS1 := newRSAKey(t)
+	files := []string{
+		writePEM(t, dir, "legacy", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaPKCS1)),
+		writePrivate(t, dir, "current", newEd25519Key(t)),
+	}
+
+	p := loadProvider(t, "", files, "legacy")
+	token, err := p.GenerateAccessToken(testUser)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	if kid := kidOf(t, token); kid != "legacy" {
+		t.Errorf("signing kid = %q, want legacy", kid)
+	}
+	if _, err := p.ValidateAccessToken(token); err != nil {
+		t.Errorf("ValidateAccessToken: %v", err)
+	}
+}
+
+func TestLoadKeySetErrors(t *testing.T) {
+	dir := t.TempDir()
+	signer := writePrivate(t, dir, "signer", newRSAKey(t))
+	verifier := writePublic(t, dir, "verifier", newRSAKey(t).Public())
+	other := t.TempDir()
+	duplicate := writePrivate(t, other, "signer", newRSAKey(t))
+	garbage := filepath.Join(other, "garbage.pem")
+	if err := os.WriteFile(garbage, []byte("not a key"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		dir        string
+		files      []string
+		signingKID string
+		secret     []byte
+	}{
+		{"duplicate kid", dir, []string{duplicate}, "", nil},
+		{"signing kid without private key", "", []string{signer, verifier}, "verifier", nil},
+		{"unknown signing kid", "", []string{signer}, "missing", nil},
+		{"only public keys", "", []string{verifier}, "", nil},
+		{"not a PEM file", "", []string{garbage}, "", nil},
+		{"no keys and no secret", "", nil, "", nil},
+	}
+	for _, tt := range tests {
+		if _, err := LoadKeySet(tt.dir, tt.files, tt.signingKID, tt.secret); err == nil {
+			t.Errorf("%s: LoadKeySet: want error", tt.name)
+		}
+	}
+}
+
+func TestHS256Fallback(t *testing.T) {
+	keys, err := LoadKeySet("", nil, "", []byte("secret"))
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+	p := NewJwtProvider(keys)
+	if len(p.PublicKeys()) != 0 {
+		t.Errorf("PublicKeys = %+v, want none for HS256", p.PublicKeys())
+	}
+	token, err := p.GenerateAccessToken(testUser)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	if _, err := p.ValidateAccessToken(token); err != nil {
+		t.Errorf("ValidateAccessToken: %v", err)
+	}
+	if _, err := p.ValidateAccessToken(signWith(t, jwt.SigningMethodHS256, "", []byte("other"))); err == nil {
+		t.Error("token signed with another secret accepted")
+	}
+}
+
+func TestVerifyRejectsForeignTokens(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey := newRSAKey(t)
+	writePrivate(t, dir, "rsa", rsaKey)
+	// второй алгоритм в наборе: проверка alg по kid, а не только по списку алгоритмов
+	writePrivate(t, dir, "ed", newEd25519Key(t))
+	publicPath := writePublic(t, t.TempDir(), "rsa-public", rsaKey.Public())
+	p := loadProvider(t, dir, nil, "rsa")
+	publicPEM, err := os.ReadFile(publicPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(rsaKey.Public())
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"unknown kid", signWith(t, jwt.SigningMethodRS256, "missing", rsaKey)},
+		{"no kid", signWith(t, jwt.SigningMethodRS256, "", rsaKey)},
+		{"another key under a known kid", signWith(t, jwt.SigningMethodRS256, "rsa", newRSAKey(t))},
+		// подмена alg: HMAC с публичным ключом в качестве секрета
+		{"HS256 with the public key PEM", signWith(t, jwt.SigningMethodHS256, "rsa", publicPEM)},
+		{"HS256 with the public key DER", signWith(t, jwt.SigningMethodHS256, "rsa", publicDER)},
+		{"EdDSA under an RSA kid", signWith(t, jwt.SigningMethodEdDSA, "rsa", newEd25519Key(t))},
+		{"alg none", signWith(t, jwt.SigningMethodNone, "rsa", jwt.UnsafeAllowNoneSignatureType)},
+	}
+	for _, tt := range tests {
+		if _, err := p.ValidateAccessToken(tt.token); err == nil {
+			t.Errorf("%s: token accepted", tt.name)
+		}
+	}
+	// тот же токен, подписанный правильно, принимается
+	if _, err := p.ValidateAccessToken(signWith(t, jwt.SigningMethodRS256, "rsa", rsaKey)); err != nil {
+		t.Errorf("valid token rejected: %v", err)
+	}
+}
+
+func TestVerifyWithNonSigningKey(t *testing.T) {
+	dir := t.TempDir()
+	writePrivate(t, dir, "old", newEd25519Key(t))
+	writePrivate(t, dir, "new", newRSAKey(t))
+	external := newEd25519Key(t)
+	writePublic(t, dir, "external", external.Public())
+
+	// токен выдан до смены ключа подписи
+	before := loadProvider(t, dir, nil, "old")
+	token, err := before.GenerateAccessToken(testUser)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	after := loadProvider(t, dir, nil, "new")
+	if _, err := after.ValidateAccessToken(token); err != nil {
+		t.Errorf("token of the previous signing key rejected: %v", err)
+	}
+	// ключ только для проверки
+	if _, err := after.ValidateAccessToken(signWith(t, jwt.SigningMethodEdDSA, "external", external)); err != nil {
+		t.Errorf("token of a public-only key rejected: %v", err)
+	}
+	if fresh, _ := after.GenerateAccessToken(testUser); kidOf(t, fresh) != "new" {
+		t.Errorf("signing kid = %q, want new", kidOf(t, fresh))
+	}
+}
@@ -9,4 +9,8 @@ type JwtProvider interface {
 	GenerateRefreshToken(user model.User) (string, error)
 	ValidateAccessToken(tokenStr string) (*CustomClaims, error)
 	ValidateRefreshToken(tokenStr string) (*CustomClaims, error)
+	GenerateChallengeToken(user model.User) (string, error)
+	ValidateChallengeToken(tokenStr string) (*CustomClaims, error)
+	// ключи проверки для JWKS; пусто, если используется HS256
+	PublicKeys() []Key
 }
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BanCacheTTL - сколько живёт результат проверки бана; бан, выданный на другом
+// инстансе, начинает действовать на этом не позже чем через BanCacheTTL
+const BanCacheTTL = 10 * time.Second
+
+// при таком размере кэша из него удаляются устаревшие записи
+const banCachePruneSize = 10_000
+
+type banCacheEntry struct {
+	banned bool
+	// до какого момента запись верна
+	validUntil time.Time
+}
+
+// banCache - кэш проверок бана, чтобы не ходить в БД на каждый запрос
+type banCache struct {
+	mu      sync.Mutex
+	entries map[uuid.UUID]banCacheEntry
+}
+
+func newBanCache() *banCache {
+	return &banCache{
+		entries: make(map[uuid.UUID]banCacheEntry),
+	}
+}
+
+func (c *banCache) get(userID uuid.UUID, now time.Time) (banned, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[userID]
+	if !ok || !now.Before(entry.validUntil) {
+		return false, false
+	}
+	return entry.banned, true
+}
+
+// set запоминает результат; истекающий бан хранится не дольше своего срока
+func (c *banCache) set(userID uuid.UUID, banned bool, expiresAt *time.Time, now time.Time) {
+	validUntil := now.Add(BanCacheTTL)
+	if banned && expiresAt != nil && expiresAt.Before(validUntil) {
+		validUntil = *expiresAt
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= banCachePruneSize {
+		for id, entry := range c.entries {
+			if !now.Before(entry.validUntil) {
+				delete(c.entries, id)
+			}
+		}
+	}
+	c.entries[userID] = banCacheEntry{banned: banned, validUntil: validUntil}
+}
+
+func (c *banCache) forget(userID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, userID)
+}
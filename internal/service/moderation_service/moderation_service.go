@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	authModel "tic-tac-toe/internal/domain/model/auth"
+	model "tic-tac-toe/internal/domain/model/moderation"
+	gameService "tic-tac-toe/internal/service/game_service"
+	userService "tic-tac-toe/internal/service/user_service"
+
+	"github.com/google/uuid"
+)
+
+type moderationService struct {
+	repo   model.ModerationRepository
+	users  userService.UserService
+	tokens authModel.TokenRepository
+	games  gameService.GameServices
+	bans   *banCache
+}
+
+func NewModerationService(repo model.ModerationRepository, users userService.UserService,
+	tokens authModel.TokenRepository, games gameService.GameServices) ModerationService {
+	return &moderationService{
+		repo:   repo,
+		users:  users,
+		tokens: tokens,
+		games:  games,
+		bans:   newBanCache(),
+	}
+}
+
+func (s *moderationService) Ban(ctx context.Context, actor, userID uuid.UUID, reason string, expiresAt *time.Time) (model.Sanction, error) {
+	sanction, err := s.apply(ctx, actor, userID, model.SanctionBan, model.ActionBan, reason, expiresAt)
+	if err != nil {
+		return model.Sanction{}, err
+	}
+	s.bans.set(userID, true, expiresAt, time.Now())
+
+	// бан уже действует через проверку в MiddlewareAuth, поэтому ошибки
+	// побочных действий только логируем
+	if err := s.tokens.DeleteAllByUser(ctx, userID); err != nil {
+		log.Printf("Ошибка отзыва сессий забаненного user_id=%s: %v", userID, err)
+	}
+	cancelled, err := s.games.CancelWaitingGames(ctx, userID)
+	if err != nil {
+		log.Printf("Ошибка отмены игр забаненного user_id=%s: %v", userID, err)
+	}
+	log.Printf("Пользователь %s забанен администратором %s, отменено игр: %d", userID, actor, cancelled)
+	return sanction, nil
+}
+
+func (s *moderationService) Unban(ctx context.Context, actor, userID uuid.UUID, reason string) error {
+	if err := s.lift(ctx, actor, userID, model.SanctionBan, model.ActionUnban, reason); err != nil {
+		return err
+	}
+	s.bans.forget(userID)
+	log.Printf("Пользователь %s разбанен администратором %s", userID, actor)
+	return nil
+}
+
+func (s *moderationService) Mute(ctx context.Context, actor, userID uuid.UUID, reason string, expiresAt *time.Time) (model.Sanction, error) {
+	sanction, err := s.apply(ctx, actor, userID, model.SanctionMute, model.ActionMute, reason, expiresAt)
+	if err != nil {
+		return model.Sanction{}, err
+	}
+	log.Printf("Пользователь %s лишён права писать администратором %s", userID, actor)
+	return sanction, nil
+}
+
+func (s *moderationService) Unmute(ctx context.Context, actor, userID uuid.UUID, reason string) error {
+	if err := s.lift(ctx, actor, userID, model.SanctionMute, model.ActionUnmute, reason); err != nil {
+		return err
+	}
+	log.Printf("С пользователя %s снят запрет писать администратором %s", userID, actor)
+	return nil
+}
+
+func (s *moderationService) IsBanned(ctx context.Context, userID uuid.UUID) (bool, error) {
+	now := time.Now()
+	if banned, ok := s.bans.get(userID, now); ok {
+		return banned, nil
+	}
+	ban, err := s.repo.Active(ctx, userID, model.SanctionBan, now)
+	if err != nil {
+		return false, err
+	}
+	if ban == nil {
+		s.bans.set(userID, false, nil, now)
+		return false, nil
+	}
+	s.bans.set(userID, true, ban.ExpiresAt, now)
+	return true, nil
+}
+
+func (s *moderationService) ActiveBan(ctx context.Context, userID uuid.UUID) (*model.Sanction, error) {
+	return s.repo.Active(ctx, userID, model.SanctionBan, time.Now())
+}
+
+func (s *moderationService) IsMuted(ctx context.Context, userID uuid.UUID) (bool, error) {
+	mute, err := s.repo.Active(ctx, userID, model.SanctionMute, time.Now())
+	if err != nil {
+		return false, err
+	}
+	return mute != nil, nil
+}
+
+func (s *moderationService) Log(ctx context.Context, target *uuid.UUID, limit int) ([]model.LogEntry, error) {
+	return s.repo.GetLog(ctx, target, limit)
+}
+
+func (s *moderationService) apply(ctx context.Context, actor, userID uuid.UUID, kind model.SanctionKind,
+	action model.Action, reason string, expiresAt *time.Time) (model.Sanction, error) {
+	reason, err := s.validate(ctx, actor, userID, reason)
+	if err != nil {
+		return model.Sanction{}, err
+	}
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return model.Sanction{}, ErrExpiryInPast
+	}
+
+	sanction := model.Sanction{
+		UserID:    userID,
+		Kind:      kind,
+		Reason:    reason,
+		CreatedBy: actor,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repo.Apply(ctx, sanction, newLogEntry(actor, userID, action, reason, expiresAt, now)); err != nil {
+		return model.Sanction{}, err
+	}
+	return sanction, nil
+}
+
+func (s *moderationService) lift(ctx context.Context, actor, userID uuid.UUID, kind model.SanctionKind,
+	action model.Action, reason string) error {
+	reason, err := s.validate(ctx, actor, userID, reason)
+	if err != nil {
+		return err
+	}
+	lifted, err := s.repo.Lift(ctx, userID, kind, newLogEntry(actor, userID, action, reason, nil, time.Now()))
+	if err != nil {
+		return err
+	}
+	if !lifted {
+		return ErrNotSanctioned
+	}
+	return nil
+}
+
+// validate проверяет причину и цель; возвращает причину без лишних пробелов
+func (s *moderationService) validate(ctx context.Context, actor, userID uuid.UUID, reason string) (string, error) {
+	if actor == userID {
+		return "", ErrCannotModerateSelf
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", ErrReasonRequired
+	}
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		return "", userService.ErrUserNotFound
+	}
+	return reason, nil
+}
+
+func newLogEntry(actor, target uuid.UUID, action model.Action, reason string, expiresAt *time.Time, now time.Time) model.LogEntry {
+	return model.LogEntry{
+		UUID:      uuid.New(),
+		ActorID:   &actor,
+		TargetID:  target,
+		Action:    action,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}
+}
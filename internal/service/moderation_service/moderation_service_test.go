@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	authModel "tic-tac-toe/internal/domain/model/auth"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/moderation"
+	userModel "tic-tac-toe/internal/domain/model/user"
+	gameService "tic-tac-toe/internal/service/game_service"
+	userService "tic-tac-toe/internal/service/user_service"
+	"tic-tac-toe/internal/storage/memory"
+	dto "tic-tac-toe/internal/storage/postgres/dto"
+
+	"github.com/google/uuid"
+)
+
+type fixture struct {
+	service ModerationService
+	users   userModel.UserRepository
+	tokens  authModel.TokenRepository
+	games   gameModel.GameRepository
+	admin   uuid.UUID
+	target  uuid.UUID
+}
+
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+	ctx := context.Background()
+	store := memory.NewStore()
+	f := fixture{
+		users:  memory.NewUserRepository(store),
+		tokens: memory.NewTokenRepository(store),
+		games:  memory.NewGameRepository(store),
+		admin:  uuid.New(),
+		target: uuid.New(),
+	}
+	for _, id := range []uuid.UUID{f.admin, f.target} {
+		if err := f.users.CreateUser(ctx, userModel.User{UUID: id, Login: "mod_" + id.String()[:8], Password: "hash"}); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+	}
+	f.service = NewModerationService(memory.NewModerationRepository(store),
+		userService.NewUserServices(f.users, memory.NewTxManager(store)), f.tokens,
+		gameService.NewGameService(f.games, nil, nil, nil))
+	return f
+}
+
+// actions - действия журнала по пользователю, от старых к новым
+func (f fixture) actions(t *testing.T) []model.Action {
+	t.Helper()
+	entries, err := f.service.Log(context.Background(), &f.target, 100)
+	if err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	var actions []model.Action
+	for _, e := range slices.Backward(entries) {
+		if *e.ActorID != f.admin || e.Reason == "" {
+			t.Errorf("log entry %+v, want actor %s with a reason", e, f.admin)
+		}
+		actions = append(actions, e.Action)
+	}
+	return actions
+}
+
+func (f fixture) game(t *testing.T, playerX uuid.UUID, status gameModel.GameStatus) gameModel.Game {
+	t.Helper()
+	game := gameModel.Game{
+		UUID:        uuid.New(),
+		Field:       &gameModel.GameField{Field: [][]int{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}}},
+		Status:      status,
+		PlayerX:     playerX,
+		CurrentTurn: playerX,
+		Symbols:     map[uuid.UUID]gameModel.Char{playerX: gameModel.CharX},
+	}
+	if err := f.games.SaveGame(context.Background(), game); err != nil {
+		t.Fatalf("SaveGame: %v", err)
+	}
+	return game
+}
+
+func (f fixture) status(t *testing.T, id uuid.UUID) gameModel.GameStatus {
+	t.Helper()
+	game, err := f.games.GetCurrentGame(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetCurrentGame: %v", err)
+	}
+	return game.Status
+}
+
+func TestBan(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	waiting := f.game(t, f.target, gameModel.Waiting)
+	playing := f.game(t, f.target, gameModel.Playing)
+	foreign := f.game(t, f.admin, gameModel.Waiting)
+	session := dto.RefreshToken{TokenHash: "hash-" + uuid.NewString(), UserID: f.target, FamilyID: uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour)}
+	if err := f.tokens.Save(ctx, session); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	expires := time.Now().Add(time.Hour)
+	sanction, err := f.service.Ban(ctx, f.admin, f.target, "  spam  ", &expires)
+	if err != nil {
+		t.Fatalf("Ban: %v", err)
+	}
+	if sanction.Kind != model.SanctionBan || sanction.Reason != "spam" || sanction.CreatedBy != f.admin || !sanction.ExpiresAt.Equal(expires) {
+		t.Errorf("sanction = %+v, want ban by %s with trimmed reason", sanction, f.admin)
+	}
+	if banned, err := f.service.IsBanned(ctx, f.target); err != nil || !banned {
+		t.Errorf("IsBanned = %v, %v, want true", banned, err)
+	}
+	if muted, _ := f.service.IsMuted(ctx, f.target); muted {
+		t.Error("ban mutes the user")
+	}
+	// сессии отозваны, открытая игра отменена, остальные игры не тронуты
+	if tokens, _ := f.tokens.ListByUser(ctx, f.target); len(tokens) != 0 {
+		t.Errorf("%d sessions left after ban", len(tokens))
+	}
+	if got := f.status(t, waiting.UUID); got != gameModel.Cancelled {
+		t.Errorf("waiting game status = %v, want cancelled", got)
+	}
+	if got := f.status(t, playing.UUID); got != gameModel.Playing {
+		t.Errorf("playing game status = %v, want playing", got)
+	}
+	if got := f.status(t, foreign.UUID); got != gameModel.Waiting {
+		t.Errorf("other user's game status = %v, want waiting", got)
+	}
+
+	// разбан действует сразу, без ожидания кэша
+	if err := f.service.Unban(ctx, f.admin, f.target, "appeal"); err != nil {
+		t.Fatalf("Unban: %v", err)
+	}
+	if banned, err := f.service.IsBanned(ctx, f.target); err != nil || banned {
+		t.Errorf("IsBanned after unban = %v, %v, want false", banned, err)
+	}
+	if err := f.service.Unban(ctx, f.admin, f.target, "again"); !errors.Is(err, ErrNotSanctioned) {
+		t.Errorf("second Unban: err = %v, want ErrNotSanctioned", err)
+	}
+	if got, want := f.actions(t), []model.Action{model.ActionBan, model.ActionUnban}; !slices.Equal(got, want) {
+		t.Errorf("log actions = %v, want %v", got, want)
+	}
+}
+
+func TestBanValidation(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name    string
+		target  uuid.UUID
+		reason  string
+		expires *time.Time
+		want    error
+	}{
+		{"self", f.admin, "spam", nil, ErrCannotModerateSelf},
+		{"no reason", f.target, "   ", nil, ErrReasonRequired},
+		{"expiry in the past", f.target, "spam", &past, ErrExpiryInPast},
+		{"unknown user", uuid.New(), "spam", nil, userService.ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		if _, err := f.service.Ban(ctx, f.admin, tt.target, tt.reason, tt.expires); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Ban err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	if entries, _ := f.service.Log(ctx, nil, 100); len(entries) != 0 {
+		t.Errorf("rejected bans logged: %+v", entries)
+	}
+}
+
+func TestMute(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	if _, err := f.service.Mute(ctx, f.admin, f.target, "flood", nil); err != nil {
+		t.Fatalf("Mute: %v", err)
+	}
+	if muted, err := f.service.IsMuted(ctx, f.target); err != nil || !muted {
+		t.Errorf("IsMuted = %v, %v, want true", muted, err)
+	}
+	// мут не блокирует вход
+	if banned, _ := f.service.IsBanned(ctx, f.target); banned {
+		t.Error("mute bans the user")
+	}
+	if muted, _ := f.service.IsMuted(ctx, f.admin); muted {
+		t.Error("another user is muted")
+	}
+
+	if err := f.service.Unmute(ctx, f.admin, f.target, "calmed down"); err != nil {
+		t.Fatalf("Unmute: %v", err)
+	}
+	if muted, _ := f.service.IsMuted(ctx, f.target); muted {
+		t.Error("IsMuted after unmute = true")
+	}
+	if err := f.service.Unmute(ctx, f.admin, f.target, "again"); !errors.Is(err, ErrNotSanctioned) {
+		t.Errorf("second Unmute: err = %v, want ErrNotSanctioned", err)
+	}
+	if got, want := f.actions(t), []model.Action{model.ActionMute, model.ActionUnmute}; !slices.Equal(got, want) {
+		t.Errorf("log actions = %v, want %v", got, want)
+	}
+}
+
+func TestBanCacheTTL(t *testing.T) {
+	now := time.Now()
+	user := uuid.New()
+	c := newBanCache()
+
+	if _, ok := c.get(user, now); ok {
+		t.Fatal("empty cache hit")
+	}
+	c.set(user, false, nil, now)
+	if banned, ok := c.get(user, now.Add(BanCacheTTL-time.Millisecond)); !ok || banned {
+		t.Errorf("get before TTL = %v, %v, want cached false", banned, ok)
+	}
+	if _, ok := c.get(user, now.Add(BanCacheTTL)); ok {
+		t.Error("entry served after BanCacheTTL")
+	}
+
+	// бан, истекающий раньше TTL, не держится в кэше дольше своего срока
+	expires := now.Add(BanCacheTTL / 2)
+	c.set(user, true, &expires, now)
+	if banned, ok := c.get(user, expires.Add(-time.Millisecond)); !ok || !banned {
+		t.Errorf("get before ban expiry = %v, %v, want cached true", banned, ok)
+	}
+	if _, ok := c.get(user, expires); ok {
+		t.Error("expired ban served from cache")
+	}
+
+	c.set(user, true, nil, now)
+	c.forget(user)
+	if _, ok := c.get(user, now); ok {
+		t.Error("forgotten entry served")
+	}
+}
+
+func TestIsBannedCachesResult(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	// второй инстанс со своим кэшем поверх того же хранилища
+	other := f.service.(*moderationService)
+	replica := &moderationService{repo: other.repo, users: other.users, tokens: other.tokens, games: other.games, bans: newBanCache()}
+	if banned, _ := replica.IsBanned(ctx, f.target); banned {
+		t.Fatal("replica IsBanned before ban = true")
+	}
+	if _, err := f.service.Ban(ctx, f.admin, f.target, "spam", nil); err != nil {
+		t.Fatalf("Ban: %v", err)
+	}
+	// бан с другого инстанса виден только после истечения кэша
+	if banned, _ := replica.IsBanned(ctx, f.target); banned {
+		t.Error("replica saw the ban before BanCacheTTL")
+	}
+	// запись, сделанная BanCacheTTL назад, уже не действует
+	replica.bans.set(f.target, false, nil, time.Now().Add(-BanCacheTTL))
+	if banned, _ := replica.IsBanned(ctx, f.target); !banned {
+		t.Error("replica did not see the ban after BanCacheTTL")
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/moderation"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCannotModerateSelf = errors.New("cannot moderate yourself")
+	ErrReasonRequired     = errors.New("reason is required")
+	ErrExpiryInPast       = errors.New("expiry must be in the future")
+	ErrNotSanctioned      = errors.New("user has no active sanction")
+)
+
+type ModerationService interface {
+	// Ban блокирует вход и все запросы пользователя до expiresAt (nil - бессрочно),
+	// отзывает его сессии и отменяет открытые игры
+	Ban(ctx context.Context, actor, userID uuid.UUID, reason string, expiresAt *time.Time) (model.Sanction, error)
+	Unban(ctx context.Context, actor, userID uuid.UUID, reason string) error
+	// Mute запрещает пользователю писать в чат до expiresAt (nil - бессрочно)
+	Mute(ctx context.Context, actor, userID uuid.UUID, reason string, expiresAt *time.Time) (model.Sanction, error)
+	Unmute(ctx context.Context, actor, userID uuid.UUID, reason string) error
+
+	// IsBanned - быстрая проверка для каждого запроса (результат кэшируется на BanCacheTTL)
+	IsBanned(ctx context.Context, userID uuid.UUID) (bool, error)
+	// ActiveBan возвращает действующий бан без кэша; nil - бана нет
+	ActiveBan(ctx context.Context, userID uuid.UUID) (*model.Sanction, error)
+	IsMuted(ctx context.Context, userID uuid.UUID) (bool, error)
+
+	// Log возвращает журнал модерации; target == nil - по всем пользователям
+	Log(ctx context.Context, target *uuid.UUID, limit int) ([]model.LogEntry, error)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"sync"
+
+	model "tic-tac-toe/internal/domain/model/notification"
+
+	"github.com/google/uuid"
+)
+
+// размер буфера подписки; медленный клиент пропускает лишнее и дочитывает через GET /notifications
+const subscriptionBuffer = 16
+
+// hub раздаёт подписчикам этого инстанса уведомления, полученные от брокера
+type hub struct {
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[chan model.Notification]struct{}
+}
+
+func newHub() *hub {
+	return &hub{
+		subscribers: make(map[uuid.UUID]map[chan model.Notification]struct{}),
+	}
+}
+
+func (h *hub) subscribe(userID uuid.UUID) (<-chan model.Notification, func()) {
+	ch := make(chan model.Notification, subscriptionBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan model.Notification]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.subscribers[userID], ch)
+			if len(h.subscribers[userID]) == 0 {
+				delete(h.subscribers, userID)
+			}
+			close(ch)
+		})
+	}
+}
+
+// publish не блокируется: при заполненном буфере уведомление подписчику не отправляется
+func (h *hub) publish(n model.Notification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers[n.UserID] {
+		select {
+		case ch <- n:
+		default:
+		}
+	}
+}
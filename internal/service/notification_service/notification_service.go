@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/notification"
+
+	"github.com/google/uuid"
+)
+
+type notificationService struct {
+	repo   model.NotificationRepository
+	broker model.Broker
+	hub    *hub
+}
+
+func NewNotificationService(repo model.NotificationRepository, broker model.Broker) NotificationService {
+	return &notificationService{
+		repo:   repo,
+		broker: broker,
+		hub:    newHub(),
+	}
+}
+
+func (s *notificationService) Notify(ctx context.Context, userID uuid.UUID, kind model.Kind, data map[string]string) error {
+	if data == nil {
+		data = map[string]string{}
+	}
+	notification := model.Notification{
+		UUID:      uuid.New(),
+		UserID:    userID,
+		Kind:      kind,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Save(ctx, notification); err != nil {
+		return err
+	}
+	// подписчики пользователя могут быть подключены к другому инстансу
+	return s.broker.Publish(ctx, notification)
+}
+
+func (s *notificationService) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]model.Notification, error) {
+	return s.repo.List(ctx, userID, unreadOnly, limit)
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	return s.repo.MarkRead(ctx, userID, ids, time.Now())
+}
+
+func (s *notificationService) Subscribe(userID uuid.UUID) (<-chan model.Notification, func()) {
+	return s.hub.subscribe(userID)
+}
+
+func (s *notificationService) Listen(ctx context.Context) error {
+	return s.broker.Listen(ctx, s.hub.publish)
+}
+
+func (s *notificationService) HandleGameEvent(ctx context.Context, event gameModel.Event) error {
+	// в игре с ботом уведомлять некого
+	if event.PlayerO == nil || event.PlayerID == nil {
+		return nil
+	}
+	gameID := event.GameID.String()
+	switch event.Type {
+	case gameModel.EventPlayerJoined:
+		return s.Notify(ctx, event.PlayerX, model.KindOpponentJoined, map[string]string{
+			"game_id":   gameID,
+			"player_id": event.PlayerID.String(),
+		})
+	case gameModel.EventMoveMade:
+		// о последнем ходе соперник узнает из game_finished
+		if event.Status != gameModel.Playing {
+			return nil
+		}
+		for _, player := range opponents(event) {
+			if err := s.Notify(ctx, player, model.KindOpponentMoved, map[string]string{"game_id": gameID}); err != nil {
+				return err
+			}
+		}
+	case gameModel.EventGameFinished:
+		for _, player := range opponents(event) {
+			err := s.Notify(ctx, player, model.KindGameFinished, map[string]string{
+				"game_id": gameID,
+				"result":  gameResult(event, player),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// opponents - участники игры, кроме совершившего действие
+func opponents(event gameModel.Event) []uuid.UUID {
+	var players []uuid.UUID
+	for _, player := range event.Players() {
+		if player != *event.PlayerID {
+			players = append(players, player)
+		}
+	}
+	return players
+}
+
+// результат игры для игрока: won, lost или draw
+func gameResult(event gameModel.Event, player uuid.UUID) string {
+	switch {
+	case event.Winner == nil:
+		return "draw"
+	case *event.Winner == player:
+		return "won"
+	default:
+		return "lost"
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/notification"
+	userModel "tic-tac-toe/internal/domain/model/user"
+	"tic-tac-toe/internal/storage/contract"
+	"tic-tac-toe/internal/storage/memory"
+	"tic-tac-toe/internal/storage/postgres"
+
+	"github.com/google/uuid"
+)
+
+// instances - два экземпляра приложения: у каждого свой сервис и свои подписчики SSE
+type instances struct {
+	users userModel.UserRepository
+	a, b  NotificationService
+}
+
+func TestNotifyReachesOtherInstance(t *testing.T) {
+	backends := map[string]func(t *testing.T) instances{
+		// в памяти инстанс один, брокер общий
+		"Memory": func(t *testing.T) instances {
+			store := memory.NewStore()
+			repo := memory.NewNotificationRepository(store)
+			broker := memory.NewNotificationBroker()
+			return instances{
+				users: memory.NewUserRepository(store),
+				a:     NewNotificationService(repo, broker),
+				b:     NewNotificationService(repo, broker),
+			}
+		},
+		"Postgres": func(t *testing.T) instances {
+			pool := contract.PostgresPool(t)
+			repo := postgres.NewNotificationRepository(pool)
+			return instances{
+				users: postgres.NewUserRepository(pool),
+				a:     NewNotificationService(repo, postgres.NewNotificationBroker(pool)),
+				b:     NewNotificationService(repo, postgres.NewNotificationBroker(pool)),
+			}
+		},
+	}
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			testNotifyReachesOtherInstance(t, backend(t))
+		})
+	}
+}
+
+func testNotifyReachesOtherInstance(t *testing.T, in instances) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	userID := uuid.New()
+	if err := in.users.CreateUser(ctx, userModel.User{UUID: userID, Login: "ntf_" + userID.String()[:8], Password: "hash"}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	listening, stop := context.WithCancel(ctx)
+	for _, s := range []NotificationService{in.a, in.b} {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			s.Listen(listening)
+		}()
+		t.Cleanup(func() { <-done })
+	}
+	t.Cleanup(stop)
+
+	// клиент подключён к инстансу b, уведомление создаёт инстанс a
+	events, unsubscribe := in.b.Subscribe(userID)
+	defer unsubscribe()
+
+	// подписка на брокер устанавливается асинхронно: уведомления повторяются, пока одно не дойдёт
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if err := in.a.Notify(ctx, userID, model.KindFriendRequest, map[string]string{"from": "alice"}); err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+		select {
+		case n := <-events:
+			if n.UserID != userID || n.Kind != model.KindFriendRequest || n.Data["from"] != "alice" {
+				t.Errorf("notification = %+v", n)
+			}
+			return
+		case <-ticker.C:
+		case <-ctx.Done():
+			t.Fatal("notification from another instance not received")
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/notification"
+
+	"github.com/google/uuid"
+)
+
+// NotificationService - входящие уведомления пользователя внутри приложения.
+// Сервисы вызывают Notify; доставка наружу (сброс пароля и т.п.) - notifier_service
+type NotificationService interface {
+	// Notify сохраняет уведомление и сразу отправляет его подключённым клиентам пользователя
+	Notify(ctx context.Context, userID uuid.UUID, kind model.Kind, data map[string]string) error
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]model.Notification, error)
+	// MarkRead отмечает прочитанными уведомления ids (пустой ids - все)
+	MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int64, error)
+	// Subscribe - поток новых уведомлений пользователя, созданных любым инстансом;
+	// cancel закрывает поток
+	Subscribe(userID uuid.UUID) (events <-chan model.Notification, cancel func())
+	// Listen получает новые уведомления всех инстансов и раздаёт их подписчикам
+	// этого инстанса, пока не отменён ctx или не оборвалось соединение
+	Listen(ctx context.Context) error
+	// HandleGameEvent - подписчик шины событий: уведомляет соперника о присоединении,
+	// ходе и завершении PvP-игры
+	HandleGameEvent(ctx context.Context, event gameModel.Event) error
+}
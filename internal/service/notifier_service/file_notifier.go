@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileNotifier дописывает сообщения в файл построчно в JSON (локальная разработка, тесты)
+type fileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileNotifier(path string) Notifier {
+	return &fileNotifier{
+		path: path,
+	}
+}
+
+func (n *fileNotifier) Notify(ctx context.Context, msg Message) error {
+	line, err := json.Marshal(struct {
+		Message
+		SentAt time.Time
+	}{Message: msg, SentAt: time.Now()})
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации сообщения: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла сообщений: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("ошибка записи сообщения: %w", err)
+	}
+	return nil
+}
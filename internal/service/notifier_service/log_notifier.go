@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"log"
+)
+
+// logNotifier пишет сообщения в лог сервера (локальная разработка)
+type logNotifier struct{}
+
+func NewLogNotifier() Notifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) Notify(ctx context.Context, msg Message) error {
+	log.Printf("Сообщение для user_id=%s (login=%s): %s\n%s", msg.UserID, msg.Login, msg.Subject, msg.Body)
+	return nil
+}
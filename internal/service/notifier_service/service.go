@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// сообщение пользователю (сброс пароля и т.п.)
+type Message struct {
+	UserID  uuid.UUID
+	Login   string
+	Subject string
+	Body    string
+}
+
+// Notifier доставляет сообщения пользователям; реализация выбирается в конфигурации
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/identity"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const httpTimeout = 10 * time.Second
+
+// поля документа /.well-known/openid-configuration, которые нужны клиенту
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type idTokenClaims struct {
+	Nonce             string `json:"nonce"`
+	PreferredUsername string `json:"preferred_username"`
+	Email             string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+type oidcService struct {
+	config Config
+	states model.LoginStateRepository
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      map[string]jwk
+}
+
+func NewOIDCService(config Config, states model.LoginStateRepository) OIDCService {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "profile", "email"}
+	}
+	return &oidcService{
+		config: config,
+		states: states,
+		client: &http.Client{Timeout: httpTimeout},
+	}
+}
+
+func (s *oidcService) Enabled() bool {
+	return s.config.Issuer != ""
+}
+
+func (s *oidcService) AuthorizationURL(ctx context.Context) (string, string, error) {
+	if !s.Enabled() {
+		return "", "", ErrNotConfigured
+	}
+	provider, err := s.provider(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	state := model.LoginState{
+		State:        randomString(32),
+		CodeVerifier: randomString(48),
+		Nonce:        randomString(32),
+		ExpiresAt:    time.Now().Add(LoginStateTTL),
+	}
+	if err := s.states.Save(ctx, state); err != nil {
+		return "", "", err
+	}
+
+	challenge := sha256.Sum256([]byte(state.CodeVerifier))
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", s.config.ClientID)
+	params.Set("redirect_uri", s.config.RedirectURL)
+	params.Set("scope", strings.Join(s.config.Scopes, " "))
+	params.Set("state", state.State)
+	params.Set("nonce", state.Nonce)
+	params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(provider.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return provider.AuthorizationEndpoint + separator + params.Encode(), state.State, nil
+}
+
+func (s *oidcService) Exchange(ctx context.Context, code, state string) (Claims, error) {
+	if !s.Enabled() {
+		return Claims{}, ErrNotConfigured
+	}
+	loginState, err := s.states.Consume(ctx, state)
+	if err != nil {
+		return Claims{}, err
+	}
+	if loginState == nil {
+		return Claims{}, ErrInvalidState
+	}
+	provider, err := s.provider(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", s.config.RedirectURL)
+	form.Set("client_id", s.config.ClientID)
+	form.Set("code_verifier", loginState.CodeVerifier)
+	if s.config.ClientSecret != "" {
+		form.Set("client_secret", s.config.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := s.doJSON(req, &tokens); err != nil {
+		return Claims{}, err
+	}
+	if tokens.IDToken == "" {
+		return Claims{}, fmt.Errorf("%w: ответ без id_token", ErrProvider)
+	}
+
+	claims, err := s.verifyIDToken(ctx, provider, tokens.IDToken)
+	if err != nil {
+		return Claims{}, err
+	}
+	if claims.Nonce != loginState.Nonce {
+		return Claims{}, fmt.Errorf("%w: nonce не совпадает", ErrInvalidToken)
+	}
+	return Claims{
+		Issuer:            claims.Issuer,
+		Subject:           claims.Subject,
+		PreferredUsername: claims.PreferredUsername,
+		Email:             claims.Email,
+	}, nil
+}
+
+func (s *oidcService) verifyIDToken(ctx context.Context, provider *discovery, raw string) (*idTokenClaims, error) {
+	keyfunc := func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := s.key(ctx, provider, kid)
+		if err != nil {
+			return nil, err
+		}
+		if key.Alg != "" && key.Alg != token.Method.Alg() {
+			return nil, fmt.Errorf("алгоритм токена %s не совпадает с ключом %s", token.Method.Alg(), key.Alg)
+		}
+		return key.publicKey()
+	}
+
+	token, err := jwt.ParseWithClaims(raw, &idTokenClaims{}, keyfunc,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(provider.Issuer),
+		jwt.WithAudience(s.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	claims, ok := token.Claims.(*idTokenClaims)
+	if !ok || !token.Valid || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// provider загружает документ discovery один раз
+func (s *oidcService) provider(ctx context.Context) (*discovery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.discovery != nil {
+		return s.discovery, nil
+	}
+	endpoint := strings.TrimSuffix(s.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+	var d discovery
+	if err := s.doJSON(req, &d); err != nil {
+		return nil, err
+	}
+	if d.Issuer != s.config.Issuer {
+		return nil, fmt.Errorf("%w: issuer %q не совпадает с настроенным %q", ErrProvider, d.Issuer, s.config.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: неполный документ discovery", ErrProvider)
+	}
+	s.discovery = &d
+	return s.discovery, nil
+}
+
+// key ищет ключ по kid; неизвестный kid означает ротацию ключей у провайдера,
+// поэтому JWKS перечитывается
+func (s *oidcService) key(ctx context.Context, provider *discovery, kid string) (jwk, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.findKey(kid); ok {
+		return key, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.JWKSURI, nil)
+	if err != nil {
+		return jwk{}, fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+	var set jwks
+	if err := s.doJSON(req, &set); err != nil {
+		return jwk{}, err
+	}
+	s.keys = map[string]jwk{}
+	for _, key := range set.Keys {
+		if key.Use == "" || key.Use == "sig" {
+			s.keys[key.Kid] = key
+		}
+	}
+	if key, ok := s.findKey(kid); ok {
+		return key, nil
+	}
+	return jwk{}, fmt.Errorf("ключ %q не найден в JWKS провайдера", kid)
+}
+
+func (s *oidcService) findKey(kid string) (jwk, bool) {
+	if key, ok := s.keys[kid]; ok {
+		return key, true
+	}
+	// токен без kid допустим, если у провайдера единственный ключ
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	return jwk{}, false
+}
+
+func (s *oidcService) doJSON(req *http.Request, target interface{}) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("OIDC провайдер ответил %d на %s: %s", resp.StatusCode, req.URL, body)
+		return fmt.Errorf("%w: статус %d", ErrProvider, resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+	return nil
+}
+
+func randomString(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand не возвращает ошибок на поддерживаемых платформах
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)[:n]
+}
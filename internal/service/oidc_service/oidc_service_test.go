@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"tic-tac-toe/internal/storage/memory"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID = "tic-tac-toe"
+	testKid      = "key-1"
+)
+
+// выданный провайдером code: для чего он и с каким PKCE challenge
+type grant struct {
+	challenge string
+	nonce     string
+}
+
+// mockProvider - OIDC провайдер на httptest: discovery, JWKS и token endpoint
+type mockProvider struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]grant
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	p := &mockProvider{key: key, grants: map[string]grant{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(discovery{
+			Issuer:                p.URL,
+			AuthorizationEndpoint: p.URL + "/authorize",
+			TokenEndpoint:         p.URL + "/token",
+			JWKSURI:               p.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jwks{Keys: []jwk{{
+			Kty: "RSA",
+			Kid: testKid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", p.token)
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+// authorize имитирует страницу входа: выдаёт code под challenge из адреса
+func (p *mockProvider) authorize(t *testing.T, authURL, nonce string) string {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse authorization url: %v", err)
+	}
+	query := u.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		t.Fatalf("authorization url without PKCE: %s", authURL)
+	}
+	if nonce == "" {
+		nonce = query.Get("nonce")
+	}
+	code := randomString(16)
+	p.mu.Lock()
+	p.grants[code] = grant{challenge: query.Get("code_challenge"), nonce: nonce}
+	p.mu.Unlock()
+	return code
+}
+
+func (p *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+		return
+	}
+	p.mu.Lock()
+	g, ok := p.grants[r.PostForm.Get("code")]
+	delete(p.grants, r.PostForm.Get("code"))
+	p.mu.Unlock()
+
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(verifier[:]) != g.challenge {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, idTokenClaims{
+		Nonce:             g.nonce,
+		PreferredUsername: "alice",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    p.URL,
+			Subject:   "subject-1",
+			Audience:  jwt.ClaimStrings{testClientID},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	token.Header["kid"] = testKid
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+}
+
+func newTestService(p *mockProvider) OIDCService {
+	return NewOIDCService(Config{
+		Issuer:      p.URL,
+		ClientID:    testClientID,
+		RedirectURL: "http://localhost/auth/oidc/callback",
+	}, memory.NewLoginStateRepository(memory.NewStore()))
+}
+
+func TestExchangePKCE(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockProvider(t)
+	s := newTestService(provider)
+
+	authURL, state, err := s.AuthorizationURL(ctx)
+	if err != nil {
+		t.Fatalf("AuthorizationURL: %v", err)
+	}
+	code := provider.authorize(t, authURL, "")
+
+	// провайдер выдаёт токены, только если verifier соответствует challenge
+	claims, err := s.Exchange(ctx, code, state)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if claims.Issuer != provider.URL || claims.Subject != "subject-1" || claims.PreferredUsername != "alice" {
+		t.Errorf("claims = %+v", claims)
+	}
+}
+
+func TestExchangeStateReplay(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockProvider(t)
+	s := newTestService(provider)
+
+	authURL, state, err := s.AuthorizationURL(ctx)
+	if err != nil {
+		t.Fatalf("AuthorizationURL: %v", err)
+	}
+	if _, err := s.Exchange(ctx, provider.authorize(t, authURL, ""), state); err != nil {
+		t.Fatalf("first Exchange: %v", err)
+	}
+
+	// state одноразовый, даже с новым code
+	if _, err := s.Exchange(ctx, provider.authorize(t, authURL, ""), state); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("replayed state: err = %v, want ErrInvalidState", err)
+	}
+	if _, err := s.Exchange(ctx, provider.authorize(t, authURL, ""), "unknown"); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("unknown state: err = %v, want ErrInvalidState", err)
+	}
+}
+
+func TestExchangeNonceMismatch(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockProvider(t)
+	s := newTestService(provider)
+
+	authURL, state, err := s.AuthorizationURL(ctx)
+	if err != nil {
+		t.Fatalf("AuthorizationURL: %v", err)
+	}
+	// id_token выпущен для другого входа
+	code := provider.authorize(t, authURL, "other-nonce")
+
+	if _, err := s.Exchange(ctx, code, state); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Exchange: err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestExchangeWrongVerifier(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockProvider(t)
+	s := newTestService(provider)
+
+	// code выдан под challenge другого входа: verifier этого входа не подходит
+	otherURL, _, err := s.AuthorizationURL(ctx)
+	if err != nil {
+		t.Fatalf("AuthorizationURL: %v", err)
+	}
+	code := provider.authorize(t, otherURL, "")
+	_, state, err := s.AuthorizationURL(ctx)
+	if err != nil {
+		t.Fatalf("AuthorizationURL: %v", err)
+	}
+
+	if _, err := s.Exchange(ctx, code, state); !errors.Is(err, ErrProvider) {
+		t.Errorf("Exchange: err = %v, want ErrProvider", err)
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// сколько ждать возврата пользователя от провайдера
+const LoginStateTTL = 10 * time.Minute
+
+var (
+	ErrNotConfigured = errors.New("oidc login is not configured")
+	ErrInvalidState  = errors.New("oidc state is invalid or expired")
+	ErrProvider      = errors.New("oidc provider error")
+	ErrInvalidToken  = errors.New("oidc id token is invalid")
+)
+
+// настройки клиента OIDC; пустой Issuer - вход через OIDC отключён
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// проверенные данные пользователя из id_token
+type Claims struct {
+	Issuer            string
+	Subject           string
+	PreferredUsername string
+	Email             string
+}
+
+type OIDCService interface {
+	Enabled() bool
+	// AuthorizationURL начинает вход: сохраняет state, nonce и PKCE verifier
+	// и возвращает адрес страницы входа провайдера и state для привязки к браузеру
+	AuthorizationURL(ctx context.Context) (authURL, state string, err error)
+	// Exchange завершает вход: проверяет state, обменивает code на токены
+	// и проверяет подпись и поля id_token
+	Exchange(ctx context.Context, code, state string) (Claims, error)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/presence"
+
+	"github.com/google/uuid"
+)
+
+// при таком числе записей из кэша удаляются устаревшие
+const touchCachePruneSize = 10_000
+
+type presenceService struct {
+	repo model.PresenceRepository
+
+	mu sync.Mutex
+	// когда активность пользователя последний раз записана этим инстансом
+	touched map[uuid.UUID]time.Time
+}
+
+func NewPresenceService(repo model.PresenceRepository) PresenceService {
+	return &presenceService{
+		repo:    repo,
+		touched: make(map[uuid.UUID]time.Time),
+	}
+}
+
+func (s *presenceService) Touch(ctx context.Context, userID uuid.UUID) error {
+	now := time.Now()
+	if !s.shouldWrite(userID, now) {
+		return nil
+	}
+	if err := s.repo.Touch(ctx, userID, now); err != nil {
+		s.forget(userID)
+		return err
+	}
+	return nil
+}
+
+func (s *presenceService) Get(ctx context.Context, userID uuid.UUID) (model.Presence, error) {
+	return s.repo.Get(ctx, userID, time.Now().Add(-OnlineTTL))
+}
+
+func (s *presenceService) ListOnline(ctx context.Context, limit int) ([]model.Presence, error) {
+	return s.repo.ListOnline(ctx, time.Now().Add(-OnlineTTL), limit)
+}
+
+// shouldWrite пропускает запись, если она была меньше HeartbeatInterval назад
+func (s *presenceService) shouldWrite(userID uuid.UUID, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if last, ok := s.touched[userID]; ok && now.Sub(last) < HeartbeatInterval {
+		return false
+	}
+	if len(s.touched) >= touchCachePruneSize {
+		for id, last := range s.touched {
+			if now.Sub(last) >= HeartbeatInterval {
+				delete(s.touched, id)
+			}
+		}
+	}
+	s.touched[userID] = now
+	return true
+}
+
+func (s *presenceService) forget(userID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.touched, userID)
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/presence"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// OnlineTTL - пользователь в сети, если был активен не раньше OnlineTTL назад
+	OnlineTTL = time.Minute
+	// HeartbeatInterval - как часто клиенту без других запросов слать heartbeat;
+	// чаще этого активность одного пользователя в БД не записывается
+	HeartbeatInterval = 20 * time.Second
+)
+
+type PresenceService interface {
+	// Touch отмечает активность пользователя (запрос или heartbeat)
+	Touch(ctx context.Context, userID uuid.UUID) error
+	Get(ctx context.Context, userID uuid.UUID) (model.Presence, error)
+	ListOnline(ctx context.Context, limit int) ([]model.Presence, error)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"log"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/season"
+	txModel "tic-tac-toe/internal/domain/model/transaction"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// в архив попадает вся таблица сезона
+const archiveLimit = 1_000_000
+
+type seasonService struct {
+	repo  model.SeasonRepository
+	games gameModel.GameRepository
+	tx    txModel.Manager
+}
+
+func NewSeasonService(repo model.SeasonRepository, games gameModel.GameRepository, tx txModel.Manager) SeasonService {
+	return &seasonService{
+		repo:  repo,
+		games: games,
+		tx:    tx,
+	}
+}
+
+func (s *seasonService) Current(ctx context.Context) (model.Season, error) {
+	active, err := s.repo.GetActive(ctx)
+	if err != nil {
+		return model.Season{}, err
+	}
+	if active != nil {
+		return *active, nil
+	}
+
+	// другой экземпляр мог создать сезон одновременно - перечитываем
+	if err := s.repo.CreateActive(ctx, monthlySeason(time.Now())); err != nil {
+		return model.Season{}, err
+	}
+	active, err = s.repo.GetActive(ctx)
+	if err != nil {
+		return model.Season{}, err
+	}
+	if active == nil {
+		return model.Season{}, ErrSeasonNotFound
+	}
+	return *active, nil
+}
+
+func (s *seasonService) List(ctx context.Context) ([]model.Season, error) {
+	return s.repo.ListSeasons(ctx)
+}
+
+func (s *seasonService) GetLeaderBoard(ctx context.Context, seasonID uuid.UUID, count int) ([]gameModel.UserLeaders, error) {
+	season, err := s.repo.GetSeason(ctx, seasonID)
+	if err != nil {
+		return nil, ErrSeasonNotFound
+	}
+	if season.Status == model.Archived {
+		return s.repo.GetArchivedLeaderBoard(ctx, seasonID, count)
+	}
+	return s.games.GetLeaderBoard(ctx, seasonID, count)
+}
+
+func (s *seasonService) Rollover(ctx context.Context, now time.Time) error {
+	season, err := s.Current(ctx)
+	if err != nil {
+		return err
+	}
+	if now.Before(season.EndsAt) {
+		return nil
+	}
+
+	next := monthlySeason(season.EndsAt)
+	if now.After(next.EndsAt) {
+		// сервер был выключен дольше месяца - начинаем с текущего
+		next = monthlySeason(now)
+	}
+
+	// таблица считается после закрытия сезона в той же транзакции: игры, записанные
+	// до снимка, в него попадают, а другой инстанс не архивирует сезон повторно
+	var archived bool
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		archived, err = s.repo.Archive(ctx, season.UUID, next)
+		if err != nil || !archived {
+			return err
+		}
+		standings, err := s.games.GetLeaderBoard(ctx, season.UUID, archiveLimit)
+		if err != nil {
+			return err
+		}
+		return s.repo.SaveStandings(ctx, season.UUID, standings)
+	})
+	if err != nil {
+		return err
+	}
+	if archived {
+		log.Printf("Сезон %s завершён, начат сезон %s", season.Name, next.Name)
+	}
+	return nil
+}
+
+// сезон - календарный месяц (UTC), в который попадает t
+func monthlySeason(t time.Time) model.Season {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return model.Season{
+		UUID:     uuid.New(),
+		Name:     start.Format("2006-01"),
+		StartsAt: start,
+		EndsAt:   start.AddDate(0, 1, 0),
+		Status:   model.Active,
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/season"
+	txModel "tic-tac-toe/internal/domain/model/transaction"
+	userModel "tic-tac-toe/internal/domain/model/user"
+	"tic-tac-toe/internal/storage/contract"
+	"tic-tac-toe/internal/storage/memory"
+	"tic-tac-toe/internal/storage/postgres"
+
+	"github.com/google/uuid"
+)
+
+type repositories struct {
+	seasons model.SeasonRepository
+	games   gameModel.GameRepository
+	users   userModel.UserRepository
+	tx      txModel.Manager
+}
+
+// brokenStandings не может сохранить итоговую таблицу
+type brokenStandings struct {
+	model.SeasonRepository
+}
+
+func (brokenStandings) SaveStandings(ctx context.Context, seasonID uuid.UUID, standings []gameModel.UserLeaders) error {
+	return errors.New("db is down")
+}
+
+func TestRollover(t *testing.T) {
+	backends := map[string]func(t *testing.T) repositories{
+		"Memory": func(t *testing.T) repositories {
+			store := memory.NewStore()
+			return repositories{
+				seasons: memory.NewSeasonRepository(store),
+				games:   memory.NewGameRepository(store),
+				users:   memory.NewUserRepository(store),
+				tx:      memory.NewTxManager(store),
+			}
+		},
+		"Postgres": func(t *testing.T) repositories {
+			pool := contract.PostgresPool(t)
+			return repositories{
+				seasons: postgres.NewSeasonRepository(pool),
+				games:   postgres.NewGameRepository(pool),
+				users:   postgres.NewUserRepository(pool),
+				tx:      postgres.NewTxManager(pool),
+			}
+		},
+	}
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			testRollover(t, backend(t))
+		})
+	}
+}
+
+func testRollover(t *testing.T, repos repositories) {
+	ctx := context.Background()
+	s := NewSeasonService(repos.seasons, repos.games, repos.tx)
+	season, err := s.Current(ctx)
+	if err != nil {
+		t.Fatalf("Current: %v", err)
+	}
+
+	userID := uuid.New()
+	if err := repos.users.CreateUser(ctx, userModel.User{UUID: userID, Login: "season_" + userID.String()[:8], Password: "hash"}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	game := gameModel.Game{
+		UUID:        uuid.New(),
+		Field:       &gameModel.GameField{Field: [][]int{{1, 1, 1}, {2, 2, 0}, {0, 0, 0}}},
+		Status:      gameModel.WonX,
+		PlayerX:     userID,
+		CurrentTurn: userID,
+		Symbols:     map[uuid.UUID]gameModel.Char{userID: gameModel.CharX},
+		SeasonID:    &season.UUID,
+	}
+	if err := repos.games.SaveGame(ctx, game); err != nil {
+		t.Fatalf("SaveGame: %v", err)
+	}
+
+	// таблица не сохранилась - сезон не закрыт
+	broken := NewSeasonService(brokenStandings{repos.seasons}, repos.games, repos.tx)
+	if err := broken.Rollover(ctx, season.EndsAt); err == nil {
+		t.Fatal("Rollover: want error when standings are not saved")
+	}
+	if active, err := repos.seasons.GetActive(ctx); err != nil || active == nil || active.UUID != season.UUID {
+		t.Fatalf("GetActive = %+v, %v; want season %s still active", active, err, season.UUID)
+	}
+
+	if err := s.Rollover(ctx, season.EndsAt); err != nil {
+		t.Fatalf("Rollover: %v", err)
+	}
+	archived, err := repos.seasons.GetSeason(ctx, season.UUID)
+	if err != nil || archived.Status != model.Archived {
+		t.Fatalf("GetSeason = %+v, %v; want archived", archived, err)
+	}
+	standings, err := s.GetLeaderBoard(ctx, season.UUID, archiveLimit)
+	if err != nil {
+		t.Fatalf("GetLeaderBoard: %v", err)
+	}
+	found := false
+	for _, leader := range standings {
+		found = found || leader.UserId == userID
+	}
+	if !found {
+		t.Errorf("standings %+v miss the player of the season game", standings)
+	}
+
+	// повторная смена сезона (другой инстанс) ничего не меняет
+	if err := s.Rollover(ctx, season.EndsAt); err != nil {
+		t.Fatalf("second Rollover: %v", err)
+	}
+}
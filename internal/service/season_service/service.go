@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"errors"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/season"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrSeasonNotFound = errors.New("season not found")
+
+type SeasonService interface {
+	// Current возвращает активный сезон, создавая его при отсутствии
+	Current(ctx context.Context) (model.Season, error)
+	List(ctx context.Context) ([]model.Season, error)
+	// GetLeaderBoard - живая таблица для активного сезона и архивная для завершённых
+	GetLeaderBoard(ctx context.Context, seasonID uuid.UUID, count int) ([]gameModel.UserLeaders, error)
+	// Rollover закрывает активный сезон, если он закончился к моменту now
+	Rollover(ctx context.Context, now time.Time) error
+}
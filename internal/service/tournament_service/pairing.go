@@ -0,0 +1,112 @@
+package service
+
+import (
+	model "tic-tac-toe/internal/domain/model/tournament"
+
+	"github.com/google/uuid"
+)
+
+// пара без привязки к туру и игре; o == nil - пропуск тура
+type pair struct {
+	x uuid.UUID
+	o *uuid.UUID
+}
+
+// roundRobinSchedule строит полное расписание круговой системы методом вращения.
+// players упорядочены по посеву; при нечётном числе участников каждый тур
+// один игрок пропускает
+func roundRobinSchedule(players []uuid.UUID) [][]pair {
+	slots := make([]*uuid.UUID, 0, len(players)+1)
+	for i := range players {
+		slots = append(slots, &players[i])
+	}
+	if len(slots)%2 == 1 {
+		slots = append(slots, nil)
+	}
+
+	n := len(slots)
+	rounds := make([][]pair, 0, n-1)
+	for round := 0; round < n-1; round++ {
+		var pairs []pair
+		for board := 0; board < n/2; board++ {
+			a, b := slots[board], slots[n-1-board]
+			// первый стол меняет цвета каждый тур, чтобы зафиксированный игрок
+			// не играл всегда крестиками
+			if board == 0 && round%2 == 1 {
+				a, b = b, a
+			}
+			pairs = append(pairs, makePair(a, b))
+		}
+		rounds = append(rounds, pairs)
+
+		// вращаем всех, кроме первого
+		last := slots[n-1]
+		copy(slots[2:], slots[1:n-1])
+		slots[1] = last
+	}
+	return rounds
+}
+
+// eliminationFirstRound расставляет игроков по стандартной сетке на выбывание
+// (1-й посев против последнего и т.д.); недостающие места - пропуски тура
+func eliminationFirstRound(players []uuid.UUID) []pair {
+	size := 1
+	for size < len(players) {
+		size *= 2
+	}
+
+	order := bracketOrder(size)
+	pairs := make([]pair, 0, size/2)
+	for i := 0; i < size; i += 2 {
+		pairs = append(pairs, makePair(seedAt(players, order[i]), seedAt(players, order[i+1])))
+	}
+	return pairs
+}
+
+// eliminationNextRound составляет следующий тур из победителей соседних пар
+func eliminationNextRound(previous []model.Pairing) []pair {
+	pairs := make([]pair, 0, len(previous)/2)
+	for i := 0; i+1 < len(previous); i += 2 {
+		pairs = append(pairs, makePair(previous[i].Winner(), previous[i+1].Winner()))
+	}
+	return pairs
+}
+
+// порядок посевов в сетке размера size: для 8 - 1,8,4,5,2,7,3,6
+func bracketOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		n := len(order) * 2
+		next := make([]int, 0, n)
+		for _, seed := range order {
+			next = append(next, seed, n+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+func seedAt(players []uuid.UUID, seed int) *uuid.UUID {
+	if seed > len(players) {
+		return nil
+	}
+	return &players[seed-1]
+}
+
+// если один из игроков отсутствует, второй получает пропуск тура
+func makePair(a, b *uuid.UUID) pair {
+	if a == nil {
+		a, b = b, a
+	}
+	return pair{x: *a, o: b}
+}
+
+// ceilLog2 - число туров олимпийской системы и рекомендуемое число туров
+// швейцарской системы для n игроков
+func ceilLog2(n int) int {
+	rounds := 0
+	for size := 1; size < n; size *= 2 {
+		rounds++
+	}
+	return rounds
+}
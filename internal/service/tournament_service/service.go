@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/tournament"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownFormat      = errors.New("unknown tournament format")
+	ErrInvalidName        = errors.New("invalid tournament name")
+	ErrInvalidRounds      = errors.New("invalid number of rounds")
+	ErrNotRegistration    = errors.New("tournament registration is closed")
+	ErrAlreadyJoined      = errors.New("already joined the tournament")
+	ErrNotOwner           = errors.New("only the owner can manage the tournament")
+	ErrNotEnoughPlayers   = errors.New("not enough players to start")
+	ErrTournamentNotFound = errors.New("tournament not found")
+)
+
+type TournamentService interface {
+	// rounds задаёт число туров швейцарской системы, 0 - по числу участников
+	Create(ctx context.Context, ownerID uuid.UUID, name string, format model.Format, rounds int) (model.Tournament, error)
+	List(ctx context.Context) ([]model.Tournament, error)
+	Get(ctx context.Context, id uuid.UUID) (model.Tournament, []model.Participant, error)
+	Join(ctx context.Context, id, userID uuid.UUID) error
+	Start(ctx context.Context, id, userID uuid.UUID) (model.Tournament, error)
+
+	GetStandings(ctx context.Context, id uuid.UUID) ([]model.Standing, error)
+	// GetBracket возвращает пары, сгруппированные по турам
+	GetBracket(ctx context.Context, id uuid.UUID) ([][]model.Pairing, error)
+
+	// HandleGameFinished засчитывает результат турнирной игры
+	// (подписчик шины событий; повторный вызов для той же игры ничего не меняет)
+	HandleGameFinished(ctx context.Context, game gameModel.Game) error
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"slices"
+	model "tic-tac-toe/internal/domain/model/tournament"
+
+	"github.com/google/uuid"
+)
+
+// предел шагов перебора пар без повторных встреч: дальше повторы разрешаются
+const maxPairingSteps = 10000
+
+// состояние участника швейцарской системы перед очередным туром
+type swissPlayer struct {
+	id        uuid.UUID
+	seed      int
+	score     float64
+	opponents map[uuid.UUID]bool
+	// разница сыгранных партий крестиками и ноликами
+	colorBalance int
+	lastColor    model.Result // WonX - играл X, WonO - играл O, Pending - ещё не играл
+	hadBye       bool
+}
+
+// swissStandings восстанавливает состояние игроков по сыгранным парам
+func swissStandings(participants []model.Participant, pairings []model.Pairing) []*swissPlayer {
+	players := make([]*swissPlayer, 0, len(participants))
+	byID := make(map[uuid.UUID]*swissPlayer, len(participants))
+	for _, p := range participants {
+		player := &swissPlayer{id: p.UserID, seed: p.Seed, opponents: map[uuid.UUID]bool{}}
+		players = append(players, player)
+		byID[p.UserID] = player
+	}
+
+	for _, p := range pairings {
+		x := byID[p.PlayerX]
+		if p.Result == model.Bye {
+			x.hadBye = true
+			x.score++
+			continue
+		}
+		o := byID[*p.PlayerO]
+		// цвета и соперники учитываются и для ещё не сыгранных пар
+		x.opponents[o.id] = true
+		o.opponents[x.id] = true
+		x.colorBalance++
+		o.colorBalance--
+		x.lastColor, o.lastColor = model.WonX, model.WonO
+
+		switch p.Result {
+		case model.WonX:
+			x.score++
+		case model.WonO:
+			o.score++
+		case model.Draw:
+			x.score += 0.5
+			o.score += 0.5
+		}
+	}
+	return players
+}
+
+// swissPairing составляет пары очередного тура:
+//   - игроки сортируются по очкам, затем по посеву и разбиваются на группы по очкам;
+//   - при нечётном числе пропуск тура получает самый низкий в таблице игрок без пропуска;
+//   - в группе верхняя половина играет с нижней, повторные встречи исключаются
+//     перебором с возвратом, при необходимости игрок опускается в следующую группу;
+//     если перебор превысил maxPairingSteps, повторные встречи разрешаются;
+//   - цвета назначаются так, чтобы выровнять баланс X/O.
+//
+// Результат детерминирован для одинаковых входных данных.
+func swissPairing(players []*swissPlayer) []pair {
+	ranked := slices.Clone(players)
+	slices.SortStableFunc(ranked, func(a, b *swissPlayer) int {
+		if a.score != b.score {
+			if a.score > b.score {
+				return -1
+			}
+			return 1
+		}
+		return a.seed - b.seed
+	})
+
+	var bye *swissPlayer
+	if len(ranked)%2 == 1 {
+		byeIndex := len(ranked) - 1
+		for i := len(ranked) - 1; i >= 0; i-- {
+			if !ranked[i].hadBye {
+				byeIndex = i
+				break
+			}
+		}
+		bye = ranked[byeIndex]
+		ranked = slices.Delete(ranked, byeIndex, byeIndex+1)
+	}
+
+	steps := maxPairingSteps
+	matched, ok := pairRemaining(ranked, false, &steps)
+	if !ok {
+		// все возможные соперники уже встречались или перебор слишком долгий -
+		// разрешаем повторы, тогда подходит первый же кандидат
+		matched, _ = pairRemaining(ranked, true, &steps)
+	}
+
+	pairs := make([]pair, 0, len(matched)/2+1)
+	for i := 0; i+1 < len(matched); i += 2 {
+		pairs = append(pairs, colorPair(matched[i], matched[i+1]))
+	}
+	if bye != nil {
+		pairs = append(pairs, pair{x: bye.id})
+	}
+	return pairs
+}
+
+// pairRemaining рекурсивно подбирает соперника первому игроку списка.
+// Возвращает игроков попарно: [a1, b1, a2, b2, ...]. Перебор без повторов
+// ограничен steps шагами
+func pairRemaining(ranked []*swissPlayer, allowRematch bool, steps *int) ([]*swissPlayer, bool) {
+	if len(ranked) == 0 {
+		return nil, true
+	}
+	if !allowRematch {
+		if *steps <= 0 {
+			return nil, false
+		}
+		*steps--
+	}
+
+	top := ranked[0]
+	rest := ranked[1:]
+	for _, i := range candidateOrder(ranked) {
+		opponent := rest[i]
+		if !allowRematch && top.opponents[opponent.id] {
+			continue
+		}
+		remaining := make([]*swissPlayer, 0, len(rest)-1)
+		remaining = append(remaining, rest[:i]...)
+		remaining = append(remaining, rest[i+1:]...)
+
+		if tail, ok := pairRemaining(remaining, allowRematch, steps); ok {
+			return append([]*swissPlayer{top, opponent}, tail...), true
+		}
+	}
+	return nil, false
+}
+
+// порядок перебора соперников для ranked[0] (индексы в ranked[1:]):
+// сначала игрок из нижней половины своей группы по очкам, затем остальные
+// игроки группы, затем нижние группы по порядку
+func candidateOrder(ranked []*swissPlayer) []int {
+	groupSize := 1
+	for groupSize < len(ranked) && ranked[groupSize].score == ranked[0].score {
+		groupSize++
+	}
+
+	// в группе из groupSize игроков первому достаётся соперник с номером groupSize/2
+	ideal := groupSize / 2
+	if ideal == 0 {
+		ideal = 1
+	}
+
+	order := make([]int, 0, len(ranked)-1)
+	for i := ideal; i < groupSize; i++ {
+		order = append(order, i-1)
+	}
+	for i := ideal - 1; i >= 1; i-- {
+		order = append(order, i-1)
+	}
+	for i := groupSize; i < len(ranked); i++ {
+		order = append(order, i-1)
+	}
+	return order
+}
+
+// крестики получает игрок, который чаще играл ноликами;
+// при равенстве - тот, кто в прошлом туре играл ноликами, затем старший в таблице
+func colorPair(a, b *swissPlayer) pair {
+	switch {
+	case a.colorBalance < b.colorBalance:
+		return pair{x: a.id, o: &b.id}
+	case b.colorBalance < a.colorBalance:
+		return pair{x: b.id, o: &a.id}
+	case b.lastColor == model.WonO && a.lastColor != model.WonO:
+		return pair{x: b.id, o: &a.id}
+	default:
+		return pair{x: a.id, o: &b.id}
+	}
+}
+
+// коэффициент Бухгольца - сумма очков всех сыгранных соперников
+func buchholz(players []*swissPlayer) map[uuid.UUID]float64 {
+	scores := make(map[uuid.UUID]float64, len(players))
+	for _, p := range players {
+		scores[p.id] = p.score
+	}
+	result := make(map[uuid.UUID]float64, len(players))
+	for _, p := range players {
+		for opponent := range p.opponents {
+			result[p.id] += scores[opponent]
+		}
+	}
+	return result
+}
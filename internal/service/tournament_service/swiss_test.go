@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/tournament"
+
+	"github.com/google/uuid"
+)
+
+// newSwissPlayers создаёт n игроков с посевом 1..n и заданными очками
+func newSwissPlayers(scores ...float64) []*swissPlayer {
+	players := make([]*swissPlayer, len(scores))
+	for i, score := range scores {
+		players[i] = &swissPlayer{id: uuid.New(), seed: i + 1, score: score, opponents: map[uuid.UUID]bool{}}
+	}
+	return players
+}
+
+func met(a, b *swissPlayer) {
+	a.opponents[b.id] = true
+	b.opponents[a.id] = true
+}
+
+// checkPairs проверяет, что каждый игрок попал ровно в одну пару
+func checkPairs(t *testing.T, players []*swissPlayer, pairs []pair) {
+	t.Helper()
+	seen := map[uuid.UUID]int{}
+	for _, p := range pairs {
+		seen[p.x]++
+		if p.o != nil {
+			seen[*p.o]++
+		}
+	}
+	for _, p := range players {
+		if seen[p.id] != 1 {
+			t.Errorf("player %d appears in %d pairs, want 1", p.seed, seen[p.id])
+		}
+	}
+}
+
+func TestSwissPairingBye(t *testing.T) {
+	tests := []struct {
+		name    string
+		scores  []float64
+		hadBye  []int // посевы игроков, уже получивших пропуск
+		wantBye int   // посев игрока с пропуском, 0 - без пропуска
+	}{
+		{"even", []float64{0, 0, 0, 0}, nil, 0},
+		{"odd first round", []float64{0, 0, 0, 0, 0}, nil, 5},
+		{"lowest already had bye", []float64{0, 0, 0, 0, 0}, []int{5}, 4},
+		{"lowest by score", []float64{0, 2, 1}, nil, 1},
+		{"everyone had bye", []float64{0, 0, 0}, []int{1, 2, 3}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			players := newSwissPlayers(tt.scores...)
+			for _, seed := range tt.hadBye {
+				players[seed-1].hadBye = true
+			}
+
+			pairs := swissPairing(players)
+			checkPairs(t, players, pairs)
+
+			gotBye := 0
+			for _, p := range pairs {
+				if p.o == nil {
+					gotBye = players[slices.IndexFunc(players, func(s *swissPlayer) bool { return s.id == p.x })].seed
+				}
+			}
+			if gotBye != tt.wantBye {
+				t.Errorf("bye to seed %d, want %d", gotBye, tt.wantBye)
+			}
+		})
+	}
+}
+
+func TestSwissPairingRematch(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores []float64
+		met    [][2]int // пары посевов, уже встречавшихся
+		want   [][2]int // ожидаемые пары посевов без учёта цвета
+	}{
+		{"top half against bottom half", []float64{0, 0, 0, 0}, nil, [][2]int{{1, 3}, {2, 4}}},
+		{"score groups", []float64{1, 1, 0, 0}, nil, [][2]int{{1, 2}, {3, 4}}},
+		{"rematch avoided", []float64{1, 1, 0, 0}, [][2]int{{1, 2}}, [][2]int{{1, 3}, {2, 4}}},
+		{"backtrack", []float64{0, 0, 0, 0}, [][2]int{{1, 3}, {2, 4}}, [][2]int{{1, 4}, {2, 3}}},
+		{"rematch unavoidable", []float64{1, 0}, [][2]int{{1, 2}}, [][2]int{{1, 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			players := newSwissPlayers(tt.scores...)
+			for _, m := range tt.met {
+				met(players[m[0]-1], players[m[1]-1])
+			}
+			seedOf := map[uuid.UUID]int{}
+			for _, p := range players {
+				seedOf[p.id] = p.seed
+			}
+
+			var got [][2]int
+			for _, p := range swissPairing(players) {
+				a, b := seedOf[p.x], seedOf[*p.o]
+				got = append(got, [2]int{min(a, b), max(a, b)})
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("pairs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSwissPairingSearchBounded(t *testing.T) {
+	// несыгранные пары образуют две клики нечётного размера: пар без повторов
+	// не существует, а полный перебор экспоненциален
+	const n = 40
+	players := newSwissPlayers(make([]float64, n)...)
+	group := func(i int) bool { return i%2 == 0 || i == 1 }
+	for i := range players {
+		for j := i + 1; j < n; j++ {
+			if group(i) != group(j) {
+				met(players[i], players[j])
+			}
+		}
+	}
+
+	done := make(chan []pair)
+	go func() { done <- swissPairing(players) }()
+	select {
+	case pairs := <-done:
+		checkPairs(t, players, pairs)
+	case <-time.After(5 * time.Second):
+		t.Fatal("swissPairing did not finish in 5s")
+	}
+}
+
+func TestCandidateOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores []float64
+		want   []int
+	}{
+		{"one group of four", []float64{0, 0, 0, 0}, []int{1, 2, 0}},
+		{"one group of six", []float64{0, 0, 0, 0, 0, 0}, []int{2, 3, 4, 1, 0}},
+		{"group of two", []float64{1, 1, 0, 0}, []int{0, 1, 2}},
+		{"alone in group", []float64{2, 1, 1, 0}, []int{0, 1, 2}},
+		{"odd group", []float64{1, 1, 1, 0, 0}, []int{0, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := candidateOrder(newSwissPlayers(tt.scores...)); !slices.Equal(got, tt.want) {
+				t.Errorf("candidateOrder = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorPair(t *testing.T) {
+	tests := []struct {
+		name         string
+		balanceA     int
+		balanceB     int
+		lastA, lastB model.Result
+		wantAPlaysX  bool
+	}{
+		{"first round", 0, 0, model.Pending, model.Pending, true},
+		{"a played more as X", 1, -1, model.WonX, model.WonO, false},
+		{"b played more as X", -1, 1, model.WonO, model.WonX, true},
+		{"equal balance, b was O", 0, 0, model.WonX, model.WonO, false},
+		{"equal balance, both were O", 0, 0, model.WonO, model.WonO, true},
+		{"balance wins over last color", -2, 0, model.WonX, model.WonO, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			players := newSwissPlayers(0, 0)
+			a, b := players[0], players[1]
+			a.colorBalance, b.colorBalance = tt.balanceA, tt.balanceB
+			a.lastColor, b.lastColor = tt.lastA, tt.lastB
+
+			p := colorPair(a, b)
+			if gotAPlaysX := p.x == a.id; gotAPlaysX != tt.wantAPlaysX {
+				t.Errorf("a plays X = %v, want %v", gotAPlaysX, tt.wantAPlaysX)
+			}
+			if p.o == nil || (*p.o != a.id && *p.o != b.id) || *p.o == p.x {
+				t.Errorf("pair = %+v, want a and b", p)
+			}
+		})
+	}
+}
+
+func TestSwissStandingsAndBuchholz(t *testing.T) {
+	participants := make([]model.Participant, 5)
+	for i := range participants {
+		participants[i] = model.Participant{UserID: uuid.New(), Seed: i + 1}
+	}
+	a, b, c, d, e := participants[0].UserID, participants[1].UserID, participants[2].UserID, participants[3].UserID, participants[4].UserID
+	pairings := []model.Pairing{
+		{Round: 1, PlayerX: a, PlayerO: &b, Result: model.WonX},
+		{Round: 1, PlayerX: c, PlayerO: &d, Result: model.Draw},
+		{Round: 1, PlayerX: e, Result: model.Bye},
+		{Round: 2, PlayerX: c, PlayerO: &a, Result: model.WonO},
+		{Round: 2, PlayerX: b, PlayerO: &e, Result: model.WonX},
+		{Round: 2, PlayerX: d, Result: model.Bye},
+	}
+
+	players := swissStandings(participants, pairings)
+	tests := []struct {
+		id       uuid.UUID
+		score    float64
+		balance  int
+		hadBye   bool
+		buchholz float64
+	}{
+		{a, 2, 0, false, 1 + 0.5},   // соперники b и c
+		{b, 1, 0, false, 2 + 1},     // a и e
+		{c, 0.5, 2, false, 1.5 + 2}, // d и a
+		{d, 1.5, -1, true, 0.5},     // c, пропуск не учитывается
+		{e, 1, -1, true, 1},         // b
+	}
+	scores := buchholz(players)
+	for i, tt := range tests {
+		p := players[i]
+		if p.id != tt.id {
+			t.Fatalf("player %d: id order changed", i+1)
+		}
+		if p.score != tt.score || p.colorBalance != tt.balance || p.hadBye != tt.hadBye {
+			t.Errorf("player %d: score %v, balance %d, bye %v; want %v, %d, %v",
+				i+1, p.score, p.colorBalance, p.hadBye, tt.score, tt.balance, tt.hadBye)
+		}
+		if scores[p.id] != tt.buchholz {
+			t.Errorf("player %d: buchholz = %v, want %v", i+1, scores[p.id], tt.buchholz)
+		}
+	}
+}
+
+func TestCreateSwissRounds(t *testing.T) {
+	tests := []struct {
+		format model.Format
+		rounds int
+		valid  bool
+	}{
+		{model.Swiss, 0, true},
+		{model.Swiss, maxSwissRounds, true},
+		{model.Swiss, maxSwissRounds + 1, false},
+		{model.Swiss, -1, false},
+		{model.RoundRobin, 3, false},
+	}
+	for _, tt := range tests {
+		s := &tournamentService{repo: nopTournaments{}}
+		_, err := s.Create(context.Background(), uuid.New(), "cup", tt.format, tt.rounds)
+		if tt.valid != (err == nil) || (err != nil && !errors.Is(err, ErrInvalidRounds)) {
+			t.Errorf("Create(%s, rounds=%d): err = %v, want valid=%v", tt.format, tt.rounds, err, tt.valid)
+		}
+	}
+}
+
+// nopTournaments принимает создание турнира и ничего не хранит
+type nopTournaments struct {
+	model.TournamentRepository
+}
+
+func (nopTournaments) CreateTournament(ctx context.Context, tournament model.Tournament) error {
+	return nil
+}
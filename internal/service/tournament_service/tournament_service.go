@@ -0,0 +1,469 @@
+package service
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strconv"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	notificationModel "tic-tac-toe/internal/domain/model/notification"
+	model "tic-tac-toe/internal/domain/model/tournament"
+	txModel "tic-tac-toe/internal/domain/model/transaction"
+	gameService "tic-tac-toe/internal/service/game_service"
+	notificationService "tic-tac-toe/internal/service/notification_service"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	minPlayers    = 2
+	maxNameLength = 64
+	// больше туров швейцарской системы не нужно даже для крупных турниров
+	maxSwissRounds = 20
+)
+
+type tournamentService struct {
+	repo          model.TournamentRepository
+	games         gameService.GameServices
+	notifications notificationService.NotificationService
+	tx            txModel.Manager
+}
+
+func NewTournamentService(repo model.TournamentRepository, games gameService.GameServices,
+	notifications notificationService.NotificationService, tx txModel.Manager) TournamentService {
+	return &tournamentService{
+		repo:          repo,
+		games:         games,
+		notifications: notifications,
+		tx:            tx,
+	}
+}
+
+func (s *tournamentService) Create(ctx context.Context, ownerID uuid.UUID, name string, format model.Format, rounds int) (model.Tournament, error) {
+	if name == "" || len(name) > maxNameLength {
+		return model.Tournament{}, ErrInvalidName
+	}
+	if format != model.RoundRobin && format != model.SingleElimination && format != model.Swiss {
+		return model.Tournament{}, ErrUnknownFormat
+	}
+	if rounds < 0 || rounds > maxSwissRounds || (rounds > 0 && format != model.Swiss) {
+		return model.Tournament{}, ErrInvalidRounds
+	}
+
+	tournament := model.Tournament{
+		UUID:      uuid.New(),
+		Name:      name,
+		Format:    format,
+		Status:    model.Registration,
+		OwnerID:   ownerID,
+		Rounds:    rounds,
+		CreatedAt: time.Now(),
+	}
+	return tournament, s.repo.CreateTournament(ctx, tournament)
+}
+
+func (s *tournamentService) List(ctx context.Context) ([]model.Tournament, error) {
+	return s.repo.ListTournaments(ctx)
+}
+
+func (s *tournamentService) Get(ctx context.Context, id uuid.UUID) (model.Tournament, []model.Participant, error) {
+	tournament, err := s.repo.GetTournament(ctx, id)
+	if err != nil {
+		return model.Tournament{}, nil, ErrTournamentNotFound
+	}
+	participants, err := s.repo.GetParticipants(ctx, id)
+	if err != nil {
+		return model.Tournament{}, nil, err
+	}
+	return tournament, participants, nil
+}
+
+func (s *tournamentService) Join(ctx context.Context, id, userID uuid.UUID) error {
+	tournament, participants, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if tournament.Status != model.Registration {
+		return ErrNotRegistration
+	}
+	for _, p := range participants {
+		if p.UserID == userID {
+			return ErrAlreadyJoined
+		}
+	}
+
+	return s.repo.AddParticipant(ctx, model.Participant{
+		TournamentID: id,
+		UserID:       userID,
+		Seed:         len(participants) + 1,
+	})
+}
+
+// Start закрывает регистрацию, строит первый тур и создаёт его игры
+func (s *tournamentService) Start(ctx context.Context, id, userID uuid.UUID) (model.Tournament, error) {
+	tournament, participants, err := s.Get(ctx, id)
+	if err != nil {
+		return model.Tournament{}, err
+	}
+	if tournament.OwnerID != userID {
+		return model.Tournament{}, ErrNotOwner
+	}
+	if tournament.Status != model.Registration {
+		return model.Tournament{}, ErrNotRegistration
+	}
+	if len(participants) < minPlayers {
+		return model.Tournament{}, ErrNotEnoughPlayers
+	}
+	// каждому игроку должно хватить новых соперников на все туры
+	if tournament.Format == model.Swiss && tournament.Rounds > len(participants)-1 {
+		return model.Tournament{}, ErrNotEnoughPlayers
+	}
+
+	players := make([]uuid.UUID, 0, len(participants))
+	for _, p := range participants {
+		players = append(players, p.UserID)
+	}
+
+	// старт целиком в одной транзакции: при ошибке турнир остаётся в регистрации
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// переключение тура 0 -> 1 защищает от повторного старта
+		ok, err := s.repo.AdvanceRound(ctx, id, 0, 1)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNotRegistration
+		}
+		if err := s.repo.UpdateStatus(ctx, id, model.Running); err != nil {
+			return err
+		}
+		tournament.Status = model.Running
+		tournament.CurrentRound = 1
+
+		var pairings []model.Pairing
+		switch tournament.Format {
+		case model.RoundRobin:
+			// расписание круговой системы известно заранее, игры создаются по турам
+			schedule := roundRobinSchedule(players)
+			for round, pairs := range schedule {
+				pairings = append(pairings, newPairings(id, round+1, pairs)...)
+			}
+			tournament.Rounds = len(schedule)
+		case model.SingleElimination:
+			pairings = newPairings(id, 1, eliminationFirstRound(players))
+			tournament.Rounds = ceilLog2(len(players))
+		case model.Swiss:
+			pairings = newPairings(id, 1, swissPairing(swissStandings(participants, nil)))
+			if tournament.Rounds == 0 {
+				tournament.Rounds = ceilLog2(len(players))
+			}
+		}
+
+		if err := s.repo.UpdateRounds(ctx, id, tournament.Rounds); err != nil {
+			return err
+		}
+		if err := s.repo.SavePairings(ctx, pairings); err != nil {
+			return err
+		}
+		return s.startRound(ctx, pairings, 1)
+	})
+	if err != nil {
+		return model.Tournament{}, err
+	}
+	return tournament, nil
+}
+
+func (s *tournamentService) GetStandings(ctx context.Context, id uuid.UUID) ([]model.Standing, error) {
+	tournament, participants, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	pairings, err := s.repo.GetPairings(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return computeStandings(tournament.Format, participants, pairings), nil
+}
+
+func (s *tournamentService) GetBracket(ctx context.Context, id uuid.UUID) ([][]model.Pairing, error) {
+	if _, err := s.repo.GetTournament(ctx, id); err != nil {
+		return nil, ErrTournamentNotFound
+	}
+	pairings, err := s.repo.GetPairings(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var rounds [][]model.Pairing
+	for _, p := range pairings {
+		for len(rounds) < p.Round {
+			rounds = append(rounds, nil)
+		}
+		rounds[p.Round-1] = append(rounds[p.Round-1], p)
+	}
+	return rounds, nil
+}
+
+// результат пары и переход к следующему туру сохраняются вместе: после ошибки
+// пара остаётся Pending и повтор события засчитает её заново
+func (s *tournamentService) HandleGameFinished(ctx context.Context, game gameModel.Game) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return s.recordResult(ctx, game)
+	})
+}
+
+func (s *tournamentService) recordResult(ctx context.Context, game gameModel.Game) error {
+	pairing, err := s.repo.FindPairingByGame(ctx, game.UUID)
+	if err != nil || pairing == nil {
+		return err
+	}
+	if pairing.Result != model.Pending {
+		return nil
+	}
+	tournament, err := s.repo.GetTournament(ctx, pairing.TournamentID)
+	if err != nil {
+		return err
+	}
+
+	switch game.Status {
+	case gameModel.WonX:
+		pairing.Result = resultFor(*pairing, game.PlayerX)
+	case gameModel.WonO:
+		pairing.Result = resultFor(*pairing, *game.PlayerO)
+	case gameModel.Draw:
+		if tournament.Format == model.SingleElimination {
+			// на выбывание нужен победитель: переигровка со сменой цветов
+			return s.replay(ctx, *pairing)
+		}
+		pairing.Result = model.Draw
+	}
+	if err := s.repo.UpdatePairing(ctx, *pairing); err != nil {
+		return err
+	}
+	return s.advance(ctx, tournament)
+}
+
+// advance переходит к следующему туру, когда сыграны все пары текущего
+func (s *tournamentService) advance(ctx context.Context, tournament model.Tournament) error {
+	pairings, err := s.repo.GetPairings(ctx, tournament.UUID)
+	if err != nil {
+		return err
+	}
+	current := roundOf(pairings, tournament.CurrentRound)
+	for _, p := range current {
+		if p.Result == model.Pending {
+			return nil
+		}
+	}
+
+	var next []model.Pairing
+	switch tournament.Format {
+	case model.RoundRobin:
+		next = roundOf(pairings, tournament.CurrentRound+1)
+	case model.SingleElimination:
+		if len(current) > 1 {
+			next = newPairings(tournament.UUID, tournament.CurrentRound+1, eliminationNextRound(current))
+		}
+	case model.Swiss:
+		if tournament.CurrentRound < tournament.Rounds {
+			participants, err := s.repo.GetParticipants(ctx, tournament.UUID)
+			if err != nil {
+				return err
+			}
+			pairs := swissPairing(swissStandings(participants, pairings))
+			next = newPairings(tournament.UUID, tournament.CurrentRound+1, pairs)
+		}
+	}
+
+	if len(next) == 0 {
+		log.Printf("Турнир %s завершён", tournament.UUID)
+		return s.repo.UpdateStatus(ctx, tournament.UUID, model.Finished)
+	}
+
+	// тур переключает только один из одновременно завершившихся запросов
+	ok, err := s.repo.AdvanceRound(ctx, tournament.UUID, tournament.CurrentRound, tournament.CurrentRound+1)
+	if err != nil || !ok {
+		return err
+	}
+	// пары круговой системы сохранены при старте, остальные строятся по итогам тура
+	if tournament.Format != model.RoundRobin {
+		if err := s.repo.SavePairings(ctx, next); err != nil {
+			return err
+		}
+	}
+	return s.startRound(ctx, next, tournament.CurrentRound+1)
+}
+
+// startRound создаёт игры для всех пар тура (пропуски тура игр не требуют)
+// и уведомляет участников о начале тура
+func (s *tournamentService) startRound(ctx context.Context, pairings []model.Pairing, round int) error {
+	for _, p := range pairings {
+		if p.Round != round {
+			continue
+		}
+		if p.PlayerO != nil {
+			var err error
+			if p, err = s.createGame(ctx, p); err != nil {
+				return err
+			}
+		}
+		s.notifyRoundStarted(ctx, p)
+	}
+	return nil
+}
+
+func (s *tournamentService) replay(ctx context.Context, p model.Pairing) error {
+	playerX := p.PlayerX
+	p.PlayerX = *p.PlayerO
+	p.PlayerO = &playerX
+	_, err := s.createGame(ctx, p)
+	return err
+}
+
+// createGame создаёт игру пары сразу с обоими игроками
+func (s *tournamentService) createGame(ctx context.Context, p model.Pairing) (model.Pairing, error) {
+	game, err := s.games.CreatePairedGame(ctx, p.PlayerX, *p.PlayerO)
+	if err != nil {
+		return p, err
+	}
+	p.GameID = &game.UUID
+	return p, s.repo.UpdatePairing(ctx, p)
+}
+
+// notifyRoundStarted уведомляет игроков пары; ошибка уведомления тур не останавливает
+func (s *tournamentService) notifyRoundStarted(ctx context.Context, p model.Pairing) {
+	data := map[string]string{
+		"tournament_id": p.TournamentID.String(),
+		"round":         strconv.Itoa(p.Round),
+	}
+	if p.GameID != nil {
+		data["game_id"] = p.GameID.String()
+	}
+	players := []uuid.UUID{p.PlayerX}
+	if p.PlayerO != nil {
+		players = append(players, *p.PlayerO)
+	}
+	for _, player := range players {
+		if err := s.notifications.Notify(ctx, player, notificationModel.KindRoundStarted, data); err != nil {
+			log.Printf("Ошибка уведомления о туре %d турнира %s для user_id=%s: %v", p.Round, p.TournamentID, player, err)
+		}
+	}
+}
+
+func newPairings(tournamentID uuid.UUID, round int, pairs []pair) []model.Pairing {
+	pairings := make([]model.Pairing, 0, len(pairs))
+	for board, pr := range pairs {
+		result := model.Pending
+		if pr.o == nil {
+			result = model.Bye
+		}
+		pairings = append(pairings, model.Pairing{
+			UUID:         uuid.New(),
+			TournamentID: tournamentID,
+			Round:        round,
+			Board:        board + 1,
+			PlayerX:      pr.x,
+			PlayerO:      pr.o,
+			Result:       result,
+		})
+	}
+	return pairings
+}
+
+func roundOf(pairings []model.Pairing, round int) []model.Pairing {
+	var result []model.Pairing
+	for _, p := range pairings {
+		if p.Round == round {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func resultFor(p model.Pairing, winner uuid.UUID) model.Result {
+	if winner == p.PlayerX {
+		return model.WonX
+	}
+	return model.WonO
+}
+
+// очки: победа - 1, ничья - 0.5; пропуск тура приносит очко, кроме круговой системы.
+// Порядок: очки, коэффициент Бухгольца (швейцарская система), победы, посев
+func computeStandings(format model.Format, participants []model.Participant, pairings []model.Pairing) []model.Standing {
+	index := make(map[uuid.UUID]int, len(participants))
+	standings := make([]model.Standing, 0, len(participants))
+	for i, p := range participants {
+		index[p.UserID] = i
+		standings = append(standings, model.Standing{UserID: p.UserID})
+	}
+
+	byePoints := 1.0
+	if format == model.RoundRobin {
+		byePoints = 0
+	}
+
+	for _, p := range pairings {
+		x := &standings[index[p.PlayerX]]
+		switch p.Result {
+		case model.Pending:
+			continue
+		case model.Bye:
+			x.Byes++
+			x.Points += byePoints
+			continue
+		}
+
+		o := &standings[index[*p.PlayerO]]
+		x.Played++
+		o.Played++
+		switch p.Result {
+		case model.WonX:
+			x.Wins++
+			x.Points++
+			o.Losses++
+		case model.WonO:
+			o.Wins++
+			o.Points++
+			x.Losses++
+		case model.Draw:
+			x.Draws++
+			o.Draws++
+			x.Points += 0.5
+			o.Points += 0.5
+		}
+	}
+
+	if format == model.Swiss {
+		completed := make([]model.Pairing, 0, len(pairings))
+		for _, p := range pairings {
+			if p.Result != model.Pending {
+				completed = append(completed, p)
+			}
+		}
+		tieBreaks := buchholz(swissStandings(participants, completed))
+		for i := range standings {
+			standings[i].Buchholz = tieBreaks[standings[i].UserID]
+		}
+	}
+
+	// стабильная сортировка сохраняет порядок посева при равенстве
+	slices.SortStableFunc(standings, func(a, b model.Standing) int {
+		switch {
+		case a.Points != b.Points:
+			return compareDesc(a.Points, b.Points)
+		case a.Buchholz != b.Buchholz:
+			return compareDesc(a.Buchholz, b.Buchholz)
+		default:
+			return b.Wins - a.Wins
+		}
+	})
+	return standings
+}
+
+func compareDesc(a, b float64) int {
+	if a > b {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	notificationModel "tic-tac-toe/internal/domain/model/notification"
+	model "tic-tac-toe/internal/domain/model/user"
+	notificationService "tic-tac-toe/internal/service/notification_service"
+
+	"github.com/google/uuid"
+)
+
+type friendService struct {
+	repo          model.FriendRepository
+	users         model.UserRepository
+	notifications notificationService.NotificationService
+}
+
+func NewFriendService(repo model.FriendRepository, users model.UserRepository,
+	notifications notificationService.NotificationService) FriendService {
+	return &friendService{
+		repo:          repo,
+		users:         users,
+		notifications: notifications,
+	}
+}
+
+func (s *friendService) SendRequest(ctx context.Context, userID, friendID uuid.UUID) (model.FriendshipStatus, error) {
+	if err := s.checkTarget(ctx, userID, friendID); err != nil {
+		return "", err
+	}
+	blocked, err := s.repo.IsBlockedBetween(ctx, userID, friendID)
+	if err != nil {
+		return "", err
+	}
+	if blocked {
+		return "", ErrUserBlocked
+	}
+
+	existing, err := s.repo.GetFriendship(ctx, userID, friendID)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil {
+		switch {
+		case existing.Status == model.FriendshipAccepted:
+			return "", ErrAlreadyFriends
+		case existing.RequesterID == friendID:
+			// встречная заявка - считаем согласием
+			if err := s.AcceptRequest(ctx, userID, friendID); err != nil {
+				return "", err
+			}
+			return model.FriendshipAccepted, nil
+		default:
+			return "", ErrFriendRequestExists
+		}
+	}
+
+	created, err := s.repo.CreateRequest(ctx, userID, friendID)
+	if err != nil {
+		return "", err
+	}
+	if !created {
+		// одновременная заявка от другой стороны
+		return "", ErrFriendRequestExists
+	}
+	log.Printf("Заявка в друзья: %s -> %s", userID, friendID)
+	err = s.notifications.Notify(ctx, friendID, notificationModel.KindFriendRequest, map[string]string{
+		"from": userID.String(),
+	})
+	if err != nil {
+		log.Printf("Ошибка уведомления о заявке в друзья для user_id=%s: %v", friendID, err)
+	}
+	return model.FriendshipPending, nil
+}
+
+func (s *friendService) AcceptRequest(ctx context.Context, userID, requesterID uuid.UUID) error {
+	accepted, err := s.repo.Accept(ctx, requesterID, userID)
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return ErrFriendRequestAbsent
+	}
+	log.Printf("Заявка в друзья принята: %s -> %s", requesterID, userID)
+	return nil
+}
+
+func (s *friendService) DeclineRequest(ctx context.Context, userID, requesterID uuid.UUID) error {
+	existing, err := s.repo.GetFriendship(ctx, userID, requesterID)
+	if err != nil {
+		return err
+	}
+	if existing == nil || existing.Status != model.FriendshipPending || existing.RequesterID != requesterID {
+		return ErrFriendRequestAbsent
+	}
+	if _, err := s.repo.DeleteFriendship(ctx, userID, requesterID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *friendService) RemoveFriend(ctx context.Context, userID, friendID uuid.UUID) error {
+	existing, err := s.repo.GetFriendship(ctx, userID, friendID)
+	if err != nil {
+		return err
+	}
+	// входящую заявку отклоняют через DeclineRequest
+	if existing == nil || (existing.Status == model.FriendshipPending && existing.RequesterID != userID) {
+		return ErrNotFriends
+	}
+	if _, err := s.repo.DeleteFriendship(ctx, userID, friendID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *friendService) ListFriends(ctx context.Context, userID uuid.UUID) ([]model.Friend, error) {
+	return s.repo.ListFriends(ctx, userID)
+}
+
+func (s *friendService) ListRequests(ctx context.Context, userID uuid.UUID) ([]model.FriendRequest, error) {
+	return s.repo.ListRequests(ctx, userID)
+}
+
+func (s *friendService) FriendIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	return s.repo.FriendIDs(ctx, userID)
+}
+
+func (s *friendService) Block(ctx context.Context, userID, blockedID uuid.UUID) error {
+	if err := s.checkTarget(ctx, userID, blockedID); err != nil {
+		return err
+	}
+	// повторная блокировка ничего не меняет
+	if _, err := s.repo.Block(ctx, userID, blockedID); err != nil {
+		return err
+	}
+	log.Printf("Пользователь %s заблокировал %s", userID, blockedID)
+	return nil
+}
+
+func (s *friendService) Unblock(ctx context.Context, userID, blockedID uuid.UUID) error {
+	unblocked, err := s.repo.Unblock(ctx, userID, blockedID)
+	if err != nil {
+		return err
+	}
+	if !unblocked {
+		return ErrNotBlocked
+	}
+	return nil
+}
+
+func (s *friendService) ListBlocked(ctx context.Context, userID uuid.UUID) ([]model.BlockedUser, error) {
+	return s.repo.ListBlocked(ctx, userID)
+}
+
+func (s *friendService) BlockedIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	return s.repo.BlockedIDs(ctx, userID)
+}
+
+func (s *friendService) IsBlockedBetween(ctx context.Context, a, b uuid.UUID) (bool, error) {
+	return s.repo.IsBlockedBetween(ctx, a, b)
+}
+
+// checkTarget проверяет, что цель - другой существующий пользователь
+func (s *friendService) checkTarget(ctx context.Context, userID, targetID uuid.UUID) error {
+	if userID == targetID {
+		return ErrCannotFriendSelf
+	}
+	if _, err := s.users.GetUserByID(ctx, targetID); err != nil {
+		return ErrUserNotFound
+	}
+	return nil
+}
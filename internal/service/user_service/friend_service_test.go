@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	notificationModel "tic-tac-toe/internal/domain/model/notification"
+	model "tic-tac-toe/internal/domain/model/user"
+	notificationService "tic-tac-toe/internal/service/notification_service"
+	"tic-tac-toe/internal/storage/memory"
+
+	"github.com/google/uuid"
+)
+
+// silentNotifications принимает уведомления и никуда их не отправляет
+type silentNotifications struct {
+	notificationService.NotificationService
+}
+
+func (silentNotifications) Notify(ctx context.Context, userID uuid.UUID, kind notificationModel.Kind, data map[string]string) error {
+	return nil
+}
+
+// newFriendFixture - сервис друзей в памяти и n зарегистрированных пользователей
+func newFriendFixture(t *testing.T, n int) (FriendService, []uuid.UUID) {
+	t.Helper()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		ids[i] = uuid.New()
+		if err := users.CreateUser(context.Background(), model.User{UUID: ids[i], Login: "friend_" + ids[i].String()[:8], Password: "hash"}); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+	}
+	return NewFriendService(memory.NewFriendRepository(store), users, silentNotifications{}), ids
+}
+
+func TestSendRequestCounterAccepts(t *testing.T) {
+	ctx := context.Background()
+	s, ids := newFriendFixture(t, 2)
+	alice, bob := ids[0], ids[1]
+
+	if status, err := s.SendRequest(ctx, alice, bob); err != nil || status != model.FriendshipPending {
+		t.Fatalf("SendRequest = %q, %v; want pending", status, err)
+	}
+	if _, err := s.SendRequest(ctx, alice, bob); !errors.Is(err, ErrFriendRequestExists) {
+		t.Errorf("repeated request: err = %v, want ErrFriendRequestExists", err)
+	}
+
+	// встречная заявка принимает первую
+	if status, err := s.SendRequest(ctx, bob, alice); err != nil || status != model.FriendshipAccepted {
+		t.Fatalf("counter request = %q, %v; want accepted", status, err)
+	}
+	for _, pair := range [][2]uuid.UUID{{alice, bob}, {bob, alice}} {
+		friends, err := s.FriendIDs(ctx, pair[0])
+		if err != nil {
+			t.Fatalf("FriendIDs: %v", err)
+		}
+		if len(friends) != 1 || friends[0] != pair[1] {
+			t.Errorf("friends of %s = %v, want [%s]", pair[0], friends, pair[1])
+		}
+	}
+	if _, err := s.SendRequest(ctx, bob, alice); !errors.Is(err, ErrAlreadyFriends) {
+		t.Errorf("request to a friend: err = %v, want ErrAlreadyFriends", err)
+	}
+}
+
+func TestBlockRemovesFriendship(t *testing.T) {
+	ctx := context.Background()
+	s, ids := newFriendFixture(t, 3)
+	alice, bob, carol := ids[0], ids[1], ids[2]
+
+	s.SendRequest(ctx, alice, bob)
+	if err := s.AcceptRequest(ctx, bob, alice); err != nil {
+		t.Fatalf("AcceptRequest: %v", err)
+	}
+	// заявка carol к alice тоже отменяется блокировкой
+	s.SendRequest(ctx, carol, alice)
+
+	if err := s.Block(ctx, bob, alice); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	if err := s.Block(ctx, alice, carol); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	if friends, _ := s.FriendIDs(ctx, alice); len(friends) != 0 {
+		t.Errorf("friends of alice = %v after block, want none", friends)
+	}
+	if requests, _ := s.ListRequests(ctx, alice); len(requests) != 0 {
+		t.Errorf("requests to alice = %v after block, want none", requests)
+	}
+	if err := s.Block(ctx, alice, alice); !errors.Is(err, ErrCannotFriendSelf) {
+		t.Errorf("block self: err = %v, want ErrCannotFriendSelf", err)
+	}
+}
+
+func TestSendRequestBlocked(t *testing.T) {
+	ctx := context.Background()
+	s, ids := newFriendFixture(t, 2)
+	alice, bob := ids[0], ids[1]
+
+	if err := s.Block(ctx, alice, bob); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	// блокировка действует в обе стороны
+	if _, err := s.SendRequest(ctx, alice, bob); !errors.Is(err, ErrUserBlocked) {
+		t.Errorf("request from blocker: err = %v, want ErrUserBlocked", err)
+	}
+	if _, err := s.SendRequest(ctx, bob, alice); !errors.Is(err, ErrUserBlocked) {
+		t.Errorf("request from blocked: err = %v, want ErrUserBlocked", err)
+	}
+
+	if err := s.Unblock(ctx, alice, bob); err != nil {
+		t.Fatalf("Unblock: %v", err)
+	}
+	if _, err := s.SendRequest(ctx, bob, alice); err != nil {
+		t.Errorf("request after unblock: %v", err)
+	}
+	if err := s.Unblock(ctx, alice, bob); !errors.Is(err, ErrNotBlocked) {
+		t.Errorf("second unblock: err = %v, want ErrNotBlocked", err)
+	}
+}
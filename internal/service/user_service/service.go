@@ -15,10 +15,51 @@ var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrPasswordHash       = errors.New("password hash failed")
+	ErrUnknownRole        = errors.New("unknown role")
+
+	ErrCannotFriendSelf    = errors.New("cannot befriend or block yourself")
+	ErrAlreadyFriends      = errors.New("already friends")
+	ErrFriendRequestExists = errors.New("friend request already sent")
+	ErrFriendRequestAbsent = errors.New("friend request not found")
+	ErrNotFriends          = errors.New("not friends")
+	ErrUserBlocked         = errors.New("user is blocked")
+	ErrNotBlocked          = errors.New("user is not blocked")
 )
 
 type UserService interface {
 	Register(ctx context.Context, account dto.SignUpRequest) (model.User, error)
 	Authenticate(ctx context.Context, login, password string) (model.User, error)
 	GetByID(ctx context.Context, id uuid.UUID) (model.User, error)
+	GetByLogin(ctx context.Context, login string) (model.User, error)
+	// ChangePassword меняет пароль после проверки текущего
+	ChangePassword(ctx context.Context, id uuid.UUID, oldPassword, newPassword string) error
+	// SetPassword устанавливает новый пароль без проверки текущего (сброс пароля)
+	SetPassword(ctx context.Context, id uuid.UUID, password string) error
+	// CreateExternal создаёт пользователя внешнего входа (OIDC) со случайным паролем;
+	// логин строится из preferredLogin и при занятости дополняется суффиксом
+	CreateExternal(ctx context.Context, preferredLogin string) (model.User, error)
+	// GrantRole и RevokeRole меняют роли; новые роли попадут в следующий access-токен
+	GrantRole(ctx context.Context, id uuid.UUID, role model.Role) error
+	RevokeRole(ctx context.Context, id uuid.UUID, role model.Role) error
+}
+
+// FriendService - друзья и чёрный список. Блокировка действует в обе стороны:
+// пользователи не видят открытые игры друг друга в лобби, не могут присоединиться
+// к ним и отправить друг другу заявку. Турнирные пары блокировку не проверяют
+type FriendService interface {
+	// SendRequest отправляет заявку; встречная заявка сразу принимается
+	SendRequest(ctx context.Context, userID, friendID uuid.UUID) (model.FriendshipStatus, error)
+	AcceptRequest(ctx context.Context, userID, requesterID uuid.UUID) error
+	DeclineRequest(ctx context.Context, userID, requesterID uuid.UUID) error
+	// RemoveFriend удаляет из друзей или отменяет свою заявку
+	RemoveFriend(ctx context.Context, userID, friendID uuid.UUID) error
+	ListFriends(ctx context.Context, userID uuid.UUID) ([]model.Friend, error)
+	ListRequests(ctx context.Context, userID uuid.UUID) ([]model.FriendRequest, error)
+	FriendIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+
+	Block(ctx context.Context, userID, blockedID uuid.UUID) error
+	Unblock(ctx context.Context, userID, blockedID uuid.UUID) error
+	ListBlocked(ctx context.Context, userID uuid.UUID) ([]model.BlockedUser, error)
+	BlockedIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	IsBlockedBetween(ctx context.Context, a, b uuid.UUID) (bool, error)
 }
@@ -2,8 +2,17 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"log"
+	mathrand "math/rand/v2"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
+	txModel "tic-tac-toe/internal/domain/model/transaction"
 	model "tic-tac-toe/internal/domain/model/user"
 	dto "tic-tac-toe/internal/web/dto"
 
@@ -17,12 +26,14 @@ import (
 type userServices struct {
 	userRepository model.UserRepository
 	validator      *validator.Validate
+	tx             txModel.Manager
 }
 
-func NewUserServices(repo model.UserRepository) UserService {
+func NewUserServices(repo model.UserRepository, tx txModel.Manager) UserService {
 	return &userServices{
 		userRepository: repo,
 		validator:      validator.New(),
+		tx:             tx,
 	}
 }
 
@@ -74,3 +85,140 @@ func (u *userServices) Authenticate(ctx context.Context, login, password string)
 func (u *userServices) GetByID(ctx context.Context, id uuid.UUID) (model.User, error) {
 	return u.userRepository.GetUserByID(ctx, id)
 }
+
+func (u *userServices) GetByLogin(ctx context.Context, login string) (model.User, error) {
+	user, err := u.userRepository.GetUserByLogin(ctx, login)
+	if err != nil {
+		return model.User{}, ErrUserNotFound
+	}
+	return *user, nil
+}
+
+func (u *userServices) ChangePassword(ctx context.Context, id uuid.UUID, oldPassword, newPassword string) error {
+	user, err := u.userRepository.GetUserByID(ctx, id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	// GetUserByID не возвращает хэш пароля
+	withPassword, err := u.userRepository.GetUserByLogin(ctx, user.Login)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(withPassword.Password), []byte(oldPassword)); err != nil {
+		log.Printf("Смена пароля отклонена: неверный текущий пароль для user_id=%s", id)
+		return ErrInvalidCredentials
+	}
+	return u.SetPassword(ctx, id, newPassword)
+}
+
+func (u *userServices) SetPassword(ctx context.Context, id uuid.UUID, password string) error {
+	// те же правила, что при регистрации
+	if err := u.validator.Var(password, "required,min=6"); err != nil {
+		return ErrValidationFailed
+	}
+	hashPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return ErrPasswordHash
+	}
+	if err := u.userRepository.UpdatePassword(ctx, id, string(hashPassword)); err != nil {
+		return err
+	}
+	log.Printf("Пароль изменён: user_id=%s", id)
+	return nil
+}
+
+func (u *userServices) GrantRole(ctx context.Context, id uuid.UUID, role model.Role) error {
+	if !role.Valid() {
+		return ErrUnknownRole
+	}
+	if err := u.userRepository.AddRole(ctx, id, role); err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	log.Printf("Роль %s выдана: user_id=%s", role, id)
+	return nil
+}
+
+func (u *userServices) RevokeRole(ctx context.Context, id uuid.UUID, role model.Role) error {
+	if !role.Valid() {
+		return ErrUnknownRole
+	}
+	if err := u.userRepository.RemoveRole(ctx, id, role); err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	log.Printf("Роль %s снята: user_id=%s", role, id)
+	return nil
+}
+
+// ограничения логина - как в dto.SignUpRequest
+const (
+	minLoginLength = 5
+	maxLoginLength = 32
+)
+
+func (u *userServices) CreateExternal(ctx context.Context, preferredLogin string) (model.User, error) {
+	// пароль неизвестен никому; при желании пользователь задаст свой через сброс пароля
+	password := make([]byte, 32)
+	if _, err := rand.Read(password); err != nil {
+		return model.User{}, ErrPasswordHash
+	}
+	hashPassword, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(password)), bcrypt.DefaultCost)
+	if err != nil {
+		return model.User{}, ErrPasswordHash
+	}
+
+	base := externalLogin(preferredLogin)
+	for attempt := 0; attempt < 10; attempt++ {
+		login := base
+		if attempt > 0 {
+			suffix := fmt.Sprintf("_%04d", mathrand.IntN(10000))
+			login = base[:min(len(base), maxLoginLength-len(suffix))] + suffix
+		}
+		if existing, _ := u.userRepository.GetUserByLogin(ctx, login); existing != nil {
+			continue
+		}
+		user := model.User{
+			UUID:     uuid.New(),
+			Login:    login,
+			Password: string(hashPassword),
+		}
+		// отдельная (вложенная) транзакция: ошибка вставки не прерывает внешнюю
+		err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+			return u.userRepository.CreateUser(ctx, user)
+		})
+		if err != nil {
+			// логин могли занять одновременно - пробуем следующий
+			log.Printf("Ошибка создания внешнего пользователя login=%s: %v", login, err)
+			continue
+		}
+		log.Printf("Создан пользователь внешнего входа: user_id=%s, login=%s", user.UUID, user.Login)
+		return user, nil
+	}
+	return model.User{}, ErrUserAlreadyExists
+}
+
+// externalLogin оставляет в имени от провайдера только допустимые символы
+func externalLogin(preferred string) string {
+	if at := strings.Index(preferred, "@"); at > 0 {
+		preferred = preferred[:at]
+	}
+	var b strings.Builder
+	for _, r := range preferred {
+		if r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.') {
+			b.WriteRune(r)
+		}
+	}
+	login := b.String()
+	if len(login) > maxLoginLength {
+		login = login[:maxLoginLength]
+	}
+	if len(login) < minLoginLength {
+		login = "player" + login
+	}
+	return login
+}
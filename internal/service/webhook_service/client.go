@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+var errForbiddenAddress = errors.New("webhook address is not allowed")
+
+// newHTTPClient - клиент для доставки вебхуков. Если allowPrivate выключен,
+// соединения с loopback, частными и link-local адресами запрещены: адрес
+// проверяется после разрешения имени, поэтому DNS не позволяет обойти запрет
+func newHTTPClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: DeliveryTimeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if !publicAddr(addr) {
+				return fmt.Errorf("%w: %s", errForbiddenAddress, addr)
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   DeliveryTimeout,
+		// перенаправления не выполняются: ответ 3xx считается неудачей
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() && !addr.IsUnspecified()
+}
+
+// retryDelay - задержка перед попыткой номер attempt+1 после attempt неудачных
+func retryDelay(attempt int) time.Duration {
+	delay := RetryBaseDelay
+	for i := 1; i < attempt && delay < RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, RetryMaxDelay)
+}
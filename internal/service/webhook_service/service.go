@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/webhook"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidURL        = errors.New("invalid webhook url")
+	ErrInvalidEvents     = errors.New("invalid webhook events")
+	ErrAllGamesForbidden = errors.New("all_games webhooks require admin role")
+	ErrTooManyWebhooks   = errors.New("too many webhooks")
+	ErrWebhookNotFound   = errors.New("webhook not found")
+)
+
+const (
+	// MaxWebhooksPerUser - сколько вебхуков может зарегистрировать один пользователь
+	MaxWebhooksPerUser = 10
+	// MaxAttempts - после стольких неудачных попыток доставка помечается failed
+	MaxAttempts = 8
+	// первая повторная попытка через RetryBaseDelay, дальше задержка удваивается до RetryMaxDelay
+	RetryBaseDelay = 10 * time.Second
+	RetryMaxDelay  = time.Hour
+	// DeliveryTimeout - сколько ждать ответа получателя
+	DeliveryTimeout = 10 * time.Second
+)
+
+type WebhookService interface {
+	// Create регистрирует вебхук; allGames доступен только администратору (isAdmin)
+	Create(ctx context.Context, ownerID uuid.UUID, isAdmin bool, url string, events []gameModel.EventType, allGames bool) (model.Webhook, error)
+	List(ctx context.Context, ownerID uuid.UUID) ([]model.Webhook, error)
+	Delete(ctx context.Context, id, ownerID uuid.UUID) error
+	Deliveries(ctx context.Context, id, ownerID uuid.UUID, limit int) ([]model.Delivery, error)
+
+	// HandleEvent создаёт доставки события игры (подписчик диспетчера событий)
+	HandleEvent(ctx context.Context, event gameModel.Event) error
+	// DeliverDue отправляет доставки, время которых пришло
+	DeliverDue(ctx context.Context) (int, error)
+}
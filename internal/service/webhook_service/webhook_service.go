@@ -0,0 +1,220 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/webhook"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// сколько доставок отправляется за один проход
+	deliveryBatch = 20
+	// пока идёт отправка, доставка не выдаётся другим инстансам
+	deliveryLease = time.Minute
+	// сколько байт ответа получателя сохраняется в журнале при ошибке
+	maxErrorBody = 256
+)
+
+// тело запроса вебхука; ID одинаков во всех попытках доставки
+type envelope struct {
+	ID        uuid.UUID       `json:"id"`
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+type webhookService struct {
+	repo   model.WebhookRepository
+	client *http.Client
+}
+
+// NewWebhookService; allowPrivate разрешает адреса в локальной сети (разработка, тесты)
+func NewWebhookService(repo model.WebhookRepository, allowPrivate bool) WebhookService {
+	return &webhookService{
+		repo:   repo,
+		client: newHTTPClient(allowPrivate),
+	}
+}
+
+func (s *webhookService) Create(ctx context.Context, ownerID uuid.UUID, isAdmin bool, rawURL string,
+	events []gameModel.EventType, allGames bool) (model.Webhook, error) {
+	if err := validateURL(rawURL); err != nil {
+		return model.Webhook{}, err
+	}
+	if len(events) == 0 {
+		return model.Webhook{}, ErrInvalidEvents
+	}
+	for _, e := range events {
+		if !slices.Contains(gameModel.EventTypes, e) {
+			return model.Webhook{}, ErrInvalidEvents
+		}
+	}
+	if allGames && !isAdmin {
+		return model.Webhook{}, ErrAllGamesForbidden
+	}
+	count, err := s.repo.CountByOwner(ctx, ownerID)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	if count >= MaxWebhooksPerUser {
+		return model.Webhook{}, ErrTooManyWebhooks
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	slices.Sort(events)
+	webhook := model.Webhook{
+		UUID:      uuid.New(),
+		OwnerID:   ownerID,
+		URL:       rawURL,
+		Secret:    secret,
+		Events:    slices.Compact(events),
+		AllGames:  allGames,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Create(ctx, webhook); err != nil {
+		return model.Webhook{}, err
+	}
+	log.Printf("Вебхук зарегистрирован: id=%s, owner=%s, all_games=%t", webhook.UUID, ownerID, allGames)
+	return webhook, nil
+}
+
+func (s *webhookService) List(ctx context.Context, ownerID uuid.UUID) ([]model.Webhook, error) {
+	return s.repo.ListByOwner(ctx, ownerID)
+}
+
+func (s *webhookService) Delete(ctx context.Context, id, ownerID uuid.UUID) error {
+	deleted, err := s.repo.Delete(ctx, id, ownerID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (s *webhookService) Deliveries(ctx context.Context, id, ownerID uuid.UUID, limit int) ([]model.Delivery, error) {
+	return s.repo.ListDeliveries(ctx, id, ownerID, limit)
+}
+
+func (s *webhookService) HandleEvent(ctx context.Context, event gameModel.Event) error {
+	return s.repo.Enqueue(ctx, event, time.Now())
+}
+
+func (s *webhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDue(ctx, time.Now(), deliveryLease, deliveryBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, d := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.deliver(ctx, d)
+		}()
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+// deliver выполняет одну попытку и сохраняет её результат
+func (s *webhookService) deliver(ctx context.Context, d model.Delivery) {
+	code, err := s.send(ctx, d)
+	now := time.Now()
+
+	d.Attempts++
+	d.ResponseCode = code
+	if err == nil {
+		d.Status = model.DeliveryDelivered
+		d.LastError = ""
+		d.DeliveredAt = &now
+	} else {
+		d.LastError = err.Error()
+		if d.Attempts >= MaxAttempts {
+			d.Status = model.DeliveryFailed
+			log.Printf("Доставка вебхука %s (delivery=%s) не удалась после %d попыток: %v", d.WebhookID, d.UUID, d.Attempts, err)
+		} else {
+			d.NextAttemptAt = now.Add(retryDelay(d.Attempts))
+		}
+	}
+	// контекст прохода мог закончиться при остановке, а результат попытки нужно сохранить
+	if err := s.repo.SaveAttempt(context.WithoutCancel(ctx), d); err != nil {
+		log.Printf("Ошибка сохранения попытки доставки %s: %v", d.UUID, err)
+	}
+}
+
+// send отправляет подписанный запрос; успех - любой ответ 2xx
+func (s *webhookService) send(ctx context.Context, d model.Delivery) (*int, error) {
+	body, err := json.Marshal(envelope{
+		ID:        d.UUID,
+		Event:     string(d.Event),
+		CreatedAt: d.CreatedAt,
+		Data:      d.Payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации тела: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tic-tac-toe-webhooks")
+	req.Header.Set(HeaderEvent, string(d.Event))
+	req.Header.Set(HeaderDelivery, d.UUID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(d.Secret, now, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	code := resp.StatusCode
+	if code < 200 || code > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return &code, fmt.Errorf("ответ %d: %s", code, bytes.TrimSpace(snippet))
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	return &code, nil
+}
+
+func validateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" || u.User != nil {
+		return ErrInvalidURL
+	}
+	return nil
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("ошибка генерации ключа вебхука: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,81 @@
+// Package contract - общий набор проверок хранилищ: репозиториев игр, пользователей,
+// refresh токенов, присутствия и бакетов лимитов. Один и тот же набор запускается против каждого хранилища,
+// чтобы реализация в памяти вела себя так же, как Postgres:
+//
+//	func TestMemory(t *testing.T) {
+//		contract.Run(t, func(t *testing.T) contract.Repositories {
+//			store := memory.NewStore()
+//			return contract.Repositories{
+//				Games:    memory.NewGameRepository(store),
+//				Users:    memory.NewUserRepository(store),
+//				Tokens:   memory.NewTokenRepository(store),
+//				Seasons:  memory.NewSeasonRepository(store),
+//				Presence: memory.NewPresenceRepository(store),
+//				Tx:       memory.NewTxManager(store),
+//			}
+//		})
+//	}
+//
+// Для Postgres фабрика возвращает репозитории поверх пула с применёнными
+// миграциями. Сценарии создают свои записи со случайными id и логинами
+// и не требуют пустой базы
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authModel "tic-tac-toe/internal/domain/model/auth"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	presenceModel "tic-tac-toe/internal/domain/model/presence"
+	seasonModel "tic-tac-toe/internal/domain/model/season"
+	txModel "tic-tac-toe/internal/domain/model/transaction"
+	userModel "tic-tac-toe/internal/domain/model/user"
+
+	"github.com/google/uuid"
+)
+
+// Repositories - проверяемые репозитории одного хранилища
+type Repositories struct {
+	Games  gameModel.GameRepository
+	Users  userModel.UserRepository
+	Tokens authModel.TokenRepository
+	// нужен для таблицы лидеров: игры ссылаются на сезон
+	Seasons  seasonModel.SeasonRepository
+	Presence presenceModel.PresenceRepository
+	Tx       txModel.Manager
+}
+
+// Backend создаёт репозитории для одного сценария
+type Backend func(t *testing.T) Repositories
+
+// Run запускает все сценарии против хранилища backend
+func Run(t *testing.T, backend Backend) {
+	t.Run("Users", func(t *testing.T) { RunUsers(t, backend) })
+	t.Run("Games", func(t *testing.T) { RunGames(t, backend) })
+	t.Run("Tokens", func(t *testing.T) { RunTokens(t, backend) })
+	t.Run("Transactions", func(t *testing.T) { RunTransactions(t, backend) })
+	t.Run("Presence", func(t *testing.T) { RunPresence(t, backend) })
+}
+
+// newUser создаёт пользователя с уникальным логином
+func newUser(t *testing.T, repos Repositories, prefix string) userModel.User {
+	t.Helper()
+	id := uuid.New()
+	user := userModel.User{
+		UUID:     id,
+		Login:    prefix + "_" + id.String()[:8],
+		Password: "hash-" + id.String(),
+	}
+	if err := repos.Users.CreateUser(context.Background(), user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	return user
+}
+
+// sameTime сравнивает время с точностью хранения Postgres (микросекунды)
+func sameTime(a, b time.Time) bool {
+	d := a.Sub(b)
+	return d < time.Millisecond && d > -time.Millisecond
+}
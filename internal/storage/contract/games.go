@@ -0,0 +1,393 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	seasonModel "tic-tac-toe/internal/domain/model/season"
+
+	"github.com/google/uuid"
+)
+
+// RunGames проверяет model.GameRepository
+func RunGames(t *testing.T, backend Backend) {
+	ctx := context.Background()
+
+	t.Run("SaveAndGet", func(t *testing.T) {
+		repos := backend(t)
+		x := newUser(t, repos, "save_x")
+		game := newGame(x.UUID, nil, gameModel.Waiting)
+
+		if err := repos.Games.SaveGame(ctx, game); err != nil {
+			t.Fatalf("SaveGame: %v", err)
+		}
+		got, err := repos.Games.GetCurrentGame(ctx, game.UUID)
+		if err != nil {
+			t.Fatalf("GetCurrentGame: %v", err)
+		}
+		if got.Version != 1 || got.PlayerX != x.UUID || got.Status != gameModel.Waiting || got.PlayerO != nil {
+			t.Errorf("GetCurrentGame = %+v, want waiting game of %s with version 1", got, x.UUID)
+		}
+		if got.Symbols[x.UUID] != gameModel.CharX || len(got.Field.Field) != 3 {
+			t.Errorf("Symbols = %v, Field = %v, want X for creator and 3x3 field", got.Symbols, got.Field)
+		}
+
+		// изменение полученной игры не меняет сохранённую
+		got.Field.Field[0][0] = 1
+		got.Symbols[uuid.New()] = gameModel.CharO
+		again, err := repos.Games.GetCurrentGame(ctx, game.UUID)
+		if err != nil {
+			t.Fatalf("GetCurrentGame: %v", err)
+		}
+		if again.Field.Field[0][0] != 0 || len(again.Symbols) != 1 {
+			t.Errorf("stored game changed through returned copy: %+v", again)
+		}
+
+		if _, err := repos.Games.GetCurrentGame(ctx, uuid.New()); err == nil {
+			t.Error("GetCurrentGame of missing game: want error")
+		}
+	})
+
+	t.Run("OptimisticLocking", func(t *testing.T) {
+		repos := backend(t)
+		x := newUser(t, repos, "lock_x")
+		o := newUser(t, repos, "lock_o")
+		game := newGame(x.UUID, nil, gameModel.Waiting)
+		if err := repos.Games.SaveGame(ctx, game); err != nil {
+			t.Fatalf("SaveGame: %v", err)
+		}
+
+		// повторная вставка той же игры
+		var conflict *gameModel.ConflictError
+		if err := repos.Games.SaveGame(ctx, game); !errors.As(err, &conflict) {
+			t.Errorf("SaveGame of existing game with version 0: err = %v, want *ConflictError", err)
+		}
+
+		stored, err := repos.Games.GetCurrentGame(ctx, game.UUID)
+		if err != nil {
+			t.Fatalf("GetCurrentGame: %v", err)
+		}
+		joined := stored
+		joined.PlayerO = &o.UUID
+		joined.Status = gameModel.Playing
+		if err := repos.Games.SaveGame(ctx, joined); err != nil {
+			t.Fatalf("SaveGame with current version: %v", err)
+		}
+
+		// stored устарела после сохранения joined
+		stored.Status = gameModel.Cancelled
+		if err := repos.Games.SaveGame(ctx, stored); !errors.As(err, &conflict) {
+			t.Errorf("SaveGame with stale version: err = %v, want *ConflictError", err)
+		}
+
+		got, err := repos.Games.GetCurrentGame(ctx, game.UUID)
+		if err != nil {
+			t.Fatalf("GetCurrentGame: %v", err)
+		}
+		if got.Version != 2 || got.Status != gameModel.Playing || got.PlayerO == nil || *got.PlayerO != o.UUID {
+			t.Errorf("GetCurrentGame = %+v, want playing game with O and version 2", got)
+		}
+	})
+
+	t.Run("ConcurrentSave", func(t *testing.T) {
+		repos := backend(t)
+		x := newUser(t, repos, "race_x")
+		o := newUser(t, repos, "race_o")
+		game := newGame(x.UUID, &o.UUID, gameModel.Playing)
+		saveGames(t, repos, game)
+		stored, err := repos.Games.GetCurrentGame(ctx, game.UUID)
+		if err != nil {
+			t.Fatalf("GetCurrentGame: %v", err)
+		}
+
+		// все сохраняют ход поверх одной и той же версии
+		const writers = 8
+		errs := make(chan error, writers)
+		var wg sync.WaitGroup
+		for i := range writers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				move := copyForMove(stored)
+				move.Field.Field[i/3][i%3] = 1
+				errs <- repos.Games.SaveGame(ctx, move)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		saved := 0
+		for err := range errs {
+			var conflict *gameModel.ConflictError
+			switch {
+			case err == nil:
+				saved++
+			case errors.As(err, &conflict):
+			default:
+				t.Errorf("SaveGame: err = %v, want nil or *ConflictError", err)
+			}
+		}
+		if saved != 1 {
+			t.Errorf("%d of %d concurrent saves succeeded, want exactly 1", saved, writers)
+		}
+		got, err := repos.Games.GetCurrentGame(ctx, game.UUID)
+		if err != nil {
+			t.Fatalf("GetCurrentGame: %v", err)
+		}
+		if got.Version != stored.Version+1 {
+			t.Errorf("Version = %d, want %d", got.Version, stored.Version+1)
+		}
+	})
+
+	t.Run("AvailableAndCompleted", func(t *testing.T) {
+		repos := backend(t)
+		x := newUser(t, repos, "list_x")
+		o := newUser(t, repos, "list_o")
+
+		waiting := newGame(x.UUID, nil, gameModel.Waiting)
+		playing := newGame(x.UUID, &o.UUID, gameModel.Playing)
+		wonX := newGame(x.UUID, &o.UUID, gameModel.WonX)
+		wonO := newGame(x.UUID, &o.UUID, gameModel.WonO)
+		draw := newGame(x.UUID, &o.UUID, gameModel.Draw)
+		saveGames(t, repos, waiting, playing, wonX, wonO, draw)
+
+		available, err := repos.Games.GetAvailableGames(ctx)
+		if err != nil {
+			t.Fatalf("GetAvailableGames: %v", err)
+		}
+		ids := gameIDs(available)
+		if !slices.Contains(ids, waiting.UUID) || slices.Contains(ids, playing.UUID) {
+			t.Errorf("GetAvailableGames = %v, want waiting %s and not playing %s", ids, waiting.UUID, playing.UUID)
+		}
+
+		// завершёнными считаются победы и ничьи пользователя
+		completed, err := repos.Games.GetComplitedGames(ctx, x.UUID)
+		if err != nil {
+			t.Fatalf("GetComplitedGames: %v", err)
+		}
+		assertSameGames(t, "GetComplitedGames(X)", gameIDs(completed), wonX.UUID, draw.UUID)
+
+		completed, err = repos.Games.GetComplitedGames(ctx, o.UUID)
+		if err != nil {
+			t.Fatalf("GetComplitedGames: %v", err)
+		}
+		assertSameGames(t, "GetComplitedGames(O)", gameIDs(completed), wonO.UUID, draw.UUID)
+	})
+
+	t.Run("FinishedNewestFirst", func(t *testing.T) {
+		repos := backend(t)
+		x := newUser(t, repos, "finished_x")
+		o := newUser(t, repos, "finished_o")
+		other := newUser(t, repos, "finished_other")
+
+		first := newGame(x.UUID, &o.UUID, gameModel.WonX)
+		second := newGame(o.UUID, &x.UUID, gameModel.Draw)
+		bot := newGame(x.UUID, nil, gameModel.WonO)
+		playing := newGame(x.UUID, &o.UUID, gameModel.Playing)
+		foreign := newGame(x.UUID, &other.UUID, gameModel.WonO)
+		for _, game := range []gameModel.Game{first, second, bot, playing, foreign} {
+			saveGames(t, repos, game)
+			// updated_at в Postgres различается не меньше чем на микросекунду
+			time.Sleep(2 * time.Millisecond)
+		}
+
+		finished, err := repos.Games.GetFinishedGamesByUser(ctx, x.UUID)
+		if err != nil {
+			t.Fatalf("GetFinishedGamesByUser: %v", err)
+		}
+		want := []uuid.UUID{foreign.UUID, bot.UUID, second.UUID, first.UUID}
+		if got := gameIDs(finished); !slices.Equal(got, want) {
+			t.Errorf("GetFinishedGamesByUser = %v, want %v", got, want)
+		}
+
+		between, err := repos.Games.GetGamesBetween(ctx, o.UUID, x.UUID)
+		if err != nil {
+			t.Fatalf("GetGamesBetween: %v", err)
+		}
+		want = []uuid.UUID{second.UUID, first.UUID}
+		if got := gameIDs(between); !slices.Equal(got, want) {
+			t.Errorf("GetGamesBetween = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("LeaderBoard", func(t *testing.T) {
+		repos := backend(t)
+		season := freshSeason(t, repos)
+		other := freshSeason(t, repos)
+		a := newUser(t, repos, "leader_a")
+		b := newUser(t, repos, "leader_b")
+		c := newUser(t, repos, "leader_c")
+
+		// A: 2 победы из 3, B: 1 из 3, C: 1 из 1 (против бота)
+		games := []gameModel.Game{
+			newGame(a.UUID, &b.UUID, gameModel.WonX),
+			newGame(b.UUID, &a.UUID, gameModel.WonO),
+			newGame(a.UUID, &b.UUID, gameModel.WonO),
+			newGame(c.UUID, nil, gameModel.WonX),
+		}
+		for i := range games {
+			games[i].SeasonID = &season.UUID
+		}
+		// игры другого сезона в таблицу не попадают
+		foreign := newGame(b.UUID, &a.UUID, gameModel.WonX)
+		foreign.SeasonID = &other.UUID
+		saveGames(t, repos, append(games, foreign)...)
+
+		leaders, err := repos.Games.GetLeaderBoard(ctx, season.UUID, 10)
+		if err != nil {
+			t.Fatalf("GetLeaderBoard: %v", err)
+		}
+		want := []gameModel.UserLeaders{
+			{Login: c.Login, UserId: c.UUID, WinRate: "100.00"},
+			{Login: a.Login, UserId: a.UUID, WinRate: "66.00"},
+			{Login: b.Login, UserId: b.UUID, WinRate: "33.00"},
+		}
+		if !slices.Equal(leaders, want) {
+			t.Errorf("GetLeaderBoard = %+v, want %+v", leaders, want)
+		}
+
+		leaders, err = repos.Games.GetLeaderBoard(ctx, season.UUID, 2)
+		if err != nil {
+			t.Fatalf("GetLeaderBoard: %v", err)
+		}
+		if !slices.Equal(leaders, want[:2]) {
+			t.Errorf("GetLeaderBoard(count=2) = %+v, want %+v", leaders, want[:2])
+		}
+	})
+
+	t.Run("DeleteAndCancel", func(t *testing.T) {
+		repos := backend(t)
+		x := newUser(t, repos, "cancel_x")
+		o := newUser(t, repos, "cancel_o")
+
+		open1 := newGame(x.UUID, nil, gameModel.Waiting)
+		open2 := newGame(x.UUID, nil, gameModel.Waiting)
+		playing := newGame(x.UUID, &o.UUID, gameModel.Playing)
+		foreign := newGame(o.UUID, nil, gameModel.Waiting)
+		saveGames(t, repos, open1, open2, playing, foreign)
+
+		cancelled, err := repos.Games.CancelWaitingGames(ctx, x.UUID)
+		if err != nil {
+			t.Fatalf("CancelWaitingGames: %v", err)
+		}
+		if cancelled != 2 {
+			t.Errorf("CancelWaitingGames = %d, want 2", cancelled)
+		}
+		for id, want := range map[uuid.UUID]gameModel.GameStatus{
+			open1.UUID:   gameModel.Cancelled,
+			playing.UUID: gameModel.Playing,
+			foreign.UUID: gameModel.Waiting,
+		} {
+			got, err := repos.Games.GetCurrentGame(ctx, id)
+			if err != nil {
+				t.Fatalf("GetCurrentGame: %v", err)
+			}
+			if got.Status != want {
+				t.Errorf("game %s status = %d, want %d", id, got.Status, want)
+			}
+		}
+
+		deleted, err := repos.Games.DeleteGame(ctx, foreign.UUID)
+		if err != nil || !deleted {
+			t.Fatalf("DeleteGame = %v, %v, want true", deleted, err)
+		}
+		if _, err := repos.Games.GetCurrentGame(ctx, foreign.UUID); err == nil {
+			t.Error("GetCurrentGame of deleted game: want error")
+		}
+		deleted, err = repos.Games.DeleteGame(ctx, foreign.UUID)
+		if err != nil || deleted {
+			t.Errorf("DeleteGame of missing game = %v, %v, want false", deleted, err)
+		}
+	})
+}
+
+// newGame собирает несохранённую игру с пустым полем 3x3
+func newGame(playerX uuid.UUID, playerO *uuid.UUID, status gameModel.GameStatus) gameModel.Game {
+	symbols := map[uuid.UUID]gameModel.Char{playerX: gameModel.CharX}
+	if playerO != nil {
+		symbols[*playerO] = gameModel.CharO
+	}
+	return gameModel.Game{
+		UUID:        uuid.New(),
+		Field:       &gameModel.GameField{Field: [][]int{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}}},
+		Status:      status,
+		PlayerX:     playerX,
+		PlayerO:     playerO,
+		CurrentTurn: playerX,
+		Symbols:     symbols,
+		DateCreate:  time.Now(),
+	}
+}
+
+// copyForMove копирует поле игры, чтобы ходы горутин не смешивались
+func copyForMove(game gameModel.Game) gameModel.Game {
+	field := make([][]int, len(game.Field.Field))
+	for i, line := range game.Field.Field {
+		field[i] = slices.Clone(line)
+	}
+	game.Field = &gameModel.GameField{Field: field}
+	return game
+}
+
+func saveGames(t *testing.T, repos Repositories, games ...gameModel.Game) {
+	t.Helper()
+	for _, game := range games {
+		if err := repos.Games.SaveGame(context.Background(), game); err != nil {
+			t.Fatalf("SaveGame: %v", err)
+		}
+	}
+}
+
+// freshSeason открывает новый активный сезон, архивируя текущий:
+// игры в нём есть только у вызывающего сценария
+func freshSeason(t *testing.T, repos Repositories) seasonModel.Season {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now()
+
+	// CreateActive ничего не делает, если активный сезон уже есть
+	if err := repos.Seasons.CreateActive(ctx, seasonModel.Season{
+		UUID: uuid.New(), Name: "contract", StartsAt: now, EndsAt: now.Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("CreateActive: %v", err)
+	}
+	active, err := repos.Seasons.GetActive(ctx)
+	if err != nil || active == nil {
+		t.Fatalf("GetActive = %v, %v, want active season", active, err)
+	}
+
+	next := seasonModel.Season{UUID: uuid.New(), Name: "contract", StartsAt: now, EndsAt: now.Add(time.Hour)}
+	archived, err := repos.Seasons.Archive(ctx, active.UUID, next)
+	if err != nil || !archived {
+		t.Fatalf("Archive = %v, %v, want true", archived, err)
+	}
+	return next
+}
+
+func gameIDs(games []gameModel.Game) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(games))
+	for _, game := range games {
+		ids = append(ids, game.UUID)
+	}
+	return ids
+}
+
+// assertSameGames сравнивает наборы id без учёта порядка
+func assertSameGames(t *testing.T, name string, got []uuid.UUID, want ...uuid.UUID) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s = %v, want %v", name, got, want)
+		return
+	}
+	for _, id := range want {
+		if !slices.Contains(got, id) {
+			t.Errorf("%s = %v, want %v", name, got, want)
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ratelimit "tic-tac-toe/internal/domain/model/ratelimit"
+
+	"github.com/google/uuid"
+)
+
+// RunLimiter проверяет хранилище бакетов ratelimit.Limiter
+func RunLimiter(t *testing.T, limiter ratelimit.Limiter) {
+	ctx := context.Background()
+	limit := ratelimit.Limit{Requests: 3, Period: time.Second}
+
+	allow := func(t *testing.T, key string) (bool, time.Duration) {
+		t.Helper()
+		allowed, retryAfter, err := limiter.Allow(ctx, key, limit)
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		return allowed, retryAfter
+	}
+
+	t.Run("BurstAndRefill", func(t *testing.T) {
+		key := "contract:" + uuid.NewString()
+		for i := range limit.Requests {
+			if allowed, _ := allow(t, key); !allowed {
+				t.Fatalf("request %d of burst rejected", i+1)
+			}
+		}
+		allowed, retryAfter := allow(t, key)
+		if allowed || retryAfter <= 0 || retryAfter > limit.Period/time.Duration(limit.Requests) {
+			t.Fatalf("request after burst: allowed = %v, retryAfter = %v", allowed, retryAfter)
+		}
+
+		// другой ключ расходует свой бакет
+		if allowed, _ := allow(t, "contract:"+uuid.NewString()); !allowed {
+			t.Error("request with another key rejected")
+		}
+
+		time.Sleep(retryAfter + 20*time.Millisecond)
+		if allowed, _ := allow(t, key); !allowed {
+			t.Error("request after refill rejected")
+		}
+	})
+
+	t.Run("Cleanup", func(t *testing.T) {
+		key := "contract:" + uuid.NewString()
+		for range limit.Requests + 1 {
+			allow(t, key)
+		}
+		// удалённый бакет снова полон
+		if err := limiter.Cleanup(ctx, time.Now().Add(time.Second)); err != nil {
+			t.Fatalf("Cleanup: %v", err)
+		}
+		if allowed, _ := allow(t, key); !allowed {
+			t.Error("request after Cleanup rejected")
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package contract
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresPool подключается к базе TEST_DATABASE_URL с применёнными миграциями
+// (goose -dir migrations postgres "$TEST_DATABASE_URL" up); без неё тест пропускается.
+// Сценарии создают записи со случайными ключами, поэтому базу можно не очищать
+func PostgresPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	pool, err := pgxpool.New(context.Background(), url)
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	if err := pool.Ping(context.Background()); err != nil {
+		pool.Close()
+		t.Fatalf("Ping: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
@@ -0,0 +1,62 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+)
+
+// RunPresence проверяет model.PresenceRepository
+func RunPresence(t *testing.T, backend Backend) {
+	ctx := context.Background()
+
+	t.Run("InGame", func(t *testing.T) {
+		repos := backend(t)
+		player := newUser(t, repos, "presence")
+		opponent := newUser(t, repos, "presence")
+		started := time.Now()
+		saveGames(t, repos, newGame(player.UUID, &opponent.UUID, gameModel.Playing))
+		// активность позже игры: пользователь в сети при любом onlineSince до неё
+		if err := repos.Presence.Touch(ctx, player.UUID, started.Add(time.Minute)); err != nil {
+			t.Fatalf("Touch: %v", err)
+		}
+
+		tests := []struct {
+			name        string
+			onlineSince time.Time
+			inGame      bool
+		}{
+			{"game updated recently", started.Add(-time.Minute), true},
+			// игру не обновляли дольше срока присутствия - брошена
+			{"abandoned game", started.Add(30 * time.Second), false},
+		}
+		for _, tt := range tests {
+			got, err := repos.Presence.Get(ctx, player.UUID, tt.onlineSince)
+			if err != nil {
+				t.Fatalf("%s: Get: %v", tt.name, err)
+			}
+			if !got.Online || got.InGame != tt.inGame {
+				t.Errorf("%s: Get = %+v, want online with InGame %v", tt.name, got, tt.inGame)
+			}
+
+			online, err := repos.Presence.ListOnline(ctx, tt.onlineSince, 1000)
+			if err != nil {
+				t.Fatalf("%s: ListOnline: %v", tt.name, err)
+			}
+			found := false
+			for _, p := range online {
+				if p.UserID == player.UUID {
+					found = true
+					if p.InGame != tt.inGame {
+						t.Errorf("%s: ListOnline InGame = %v, want %v", tt.name, p.InGame, tt.inGame)
+					}
+				}
+			}
+			if !found {
+				t.Errorf("%s: ListOnline misses %s", tt.name, player.UUID)
+			}
+		}
+	})
+}
@@ -0,0 +1,245 @@
+package contract
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	dto "tic-tac-toe/internal/storage/postgres/dto"
+
+	"github.com/google/uuid"
+)
+
+// RunTokens проверяет model.TokenRepository
+func RunTokens(t *testing.T, backend Backend) {
+	ctx := context.Background()
+
+	t.Run("SaveAndFind", func(t *testing.T) {
+		repos := backend(t)
+		user := newUser(t, repos, "token")
+		token := newToken(user.UUID, uuid.New(), time.Hour)
+
+		before := time.Now()
+		if err := repos.Tokens.Save(ctx, token); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		got, err := repos.Tokens.FindByHash(ctx, token.TokenHash)
+		if err != nil {
+			t.Fatalf("FindByHash: %v", err)
+		}
+		if got.UserID != user.UUID || got.FamilyID != token.FamilyID || got.UserAgent != token.UserAgent || got.IP != token.IP {
+			t.Errorf("FindByHash = %+v, want %+v", got, token)
+		}
+		if !sameTime(got.ExpiresAt, token.ExpiresAt) || got.CreatedAt.Before(before.Add(-time.Second)) {
+			t.Errorf("ExpiresAt = %v, CreatedAt = %v, want %v and save time", got.ExpiresAt, got.CreatedAt, token.ExpiresAt)
+		}
+		if got.RotatedAt != nil {
+			t.Errorf("RotatedAt = %v, want nil", got.RotatedAt)
+		}
+
+		if _, err := repos.Tokens.FindByHash(ctx, "missing-"+uuid.NewString()); err == nil {
+			t.Error("FindByHash of missing token: want error")
+		}
+	})
+
+	t.Run("ListByUser", func(t *testing.T) {
+		repos := backend(t)
+		user := newUser(t, repos, "sessions")
+
+		older := newToken(user.UUID, uuid.New(), time.Hour)
+		expired := newToken(user.UUID, uuid.New(), -time.Hour)
+		rotated := newToken(user.UUID, uuid.New(), time.Hour)
+		newer := newToken(user.UUID, uuid.New(), time.Hour)
+		for _, token := range []dto.RefreshToken{older, expired, rotated, newer} {
+			if err := repos.Tokens.Save(ctx, token); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			// created_at задаёт порядок списка
+			time.Sleep(2 * time.Millisecond)
+		}
+		next := newToken(user.UUID, rotated.FamilyID, time.Hour)
+		next.CreatedAt = time.Now().Add(-time.Hour)
+		if ok, err := repos.Tokens.Rotate(ctx, rotated.TokenHash, next); err != nil || !ok {
+			t.Fatalf("Rotate = %v, %v, want true", ok, err)
+		}
+
+		tokens, err := repos.Tokens.ListByUser(ctx, user.UUID)
+		if err != nil {
+			t.Fatalf("ListByUser: %v", err)
+		}
+		// next продолжает сессию rotated и сохраняет время входа - он самый старый
+		want := []string{newer.TokenHash, older.TokenHash, next.TokenHash}
+		if got := tokenHashes(tokens); !slices.Equal(got, want) {
+			t.Errorf("ListByUser = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Rotate", func(t *testing.T) {
+		repos := backend(t)
+		user := newUser(t, repos, "rotate")
+		token := newToken(user.UUID, uuid.New(), time.Hour)
+		if err := repos.Tokens.Save(ctx, token); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		saved, err := repos.Tokens.FindByHash(ctx, token.TokenHash)
+		if err != nil {
+			t.Fatalf("FindByHash: %v", err)
+		}
+
+		next := newToken(user.UUID, token.FamilyID, time.Hour)
+		next.CreatedAt = saved.CreatedAt
+		ok, err := repos.Tokens.Rotate(ctx, token.TokenHash, next)
+		if err != nil || !ok {
+			t.Fatalf("Rotate = %v, %v, want true", ok, err)
+		}
+
+		old, err := repos.Tokens.FindByHash(ctx, token.TokenHash)
+		if err != nil {
+			t.Fatalf("FindByHash: %v", err)
+		}
+		if old.RotatedAt == nil {
+			t.Error("rotated token: RotatedAt = nil")
+		}
+		got, err := repos.Tokens.FindByHash(ctx, next.TokenHash)
+		if err != nil {
+			t.Fatalf("FindByHash: %v", err)
+		}
+		if got.FamilyID != token.FamilyID || !sameTime(got.CreatedAt, saved.CreatedAt) || got.LastUsedAt == nil {
+			t.Errorf("next token = %+v, want family %s, CreatedAt %v and LastUsedAt", got, token.FamilyID, saved.CreatedAt)
+		}
+
+		// повторное использование ротированного токена
+		again := newToken(user.UUID, token.FamilyID, time.Hour)
+		ok, err = repos.Tokens.Rotate(ctx, token.TokenHash, again)
+		if err != nil || ok {
+			t.Errorf("second Rotate = %v, %v, want false", ok, err)
+		}
+		if _, err := repos.Tokens.FindByHash(ctx, again.TokenHash); err == nil {
+			t.Error("token of failed rotation was saved")
+		}
+	})
+
+	t.Run("DeleteExpired", func(t *testing.T) {
+		repos := backend(t)
+		user := newUser(t, repos, "expired")
+		save := func(token dto.RefreshToken) dto.RefreshToken {
+			t.Helper()
+			if err := repos.Tokens.Save(ctx, token); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			return token
+		}
+		rotate := func(old dto.RefreshToken, ttl time.Duration) dto.RefreshToken {
+			t.Helper()
+			next := newToken(user.UUID, old.FamilyID, ttl)
+			if ok, err := repos.Tokens.Rotate(ctx, old.TokenHash, next); err != nil || !ok {
+				t.Fatalf("Rotate = %v, %v, want true", ok, err)
+			}
+			return next
+		}
+
+		expired := save(newToken(user.UUID, uuid.New(), -time.Hour))
+		fresh := save(newToken(user.UUID, uuid.New(), time.Hour))
+		// живая сессия: старый токен истёк, но нужен для обнаружения повторного использования
+		rotated := save(newToken(user.UUID, uuid.New(), -time.Hour))
+		current := rotate(rotated, time.Hour)
+		// истекла вся сессия
+		deadRotated := save(newToken(user.UUID, uuid.New(), -2*time.Hour))
+		deadCurrent := rotate(deadRotated, -time.Hour)
+
+		n, err := repos.Tokens.DeleteExpired(ctx, time.Now())
+		if err != nil {
+			t.Fatalf("DeleteExpired: %v", err)
+		}
+		// база может быть общей с другими сценариями
+		if n < 3 {
+			t.Errorf("DeleteExpired = %d, want at least 3", n)
+		}
+		for _, token := range []dto.RefreshToken{expired, deadRotated, deadCurrent} {
+			if _, err := repos.Tokens.FindByHash(ctx, token.TokenHash); err == nil {
+				t.Errorf("expired token %s kept", token.TokenHash)
+			}
+		}
+		for _, token := range []dto.RefreshToken{fresh, rotated, current} {
+			if _, err := repos.Tokens.FindByHash(ctx, token.TokenHash); err != nil {
+				t.Errorf("token %s of a live session deleted: %v", token.TokenHash, err)
+			}
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repos := backend(t)
+		user := newUser(t, repos, "logout")
+		other := newUser(t, repos, "logout_other")
+
+		family := uuid.New()
+		first := newToken(user.UUID, family, time.Hour)
+		second := newToken(user.UUID, family, time.Hour)
+		single := newToken(user.UUID, uuid.New(), time.Hour)
+		foreign := newToken(other.UUID, uuid.New(), time.Hour)
+		for _, token := range []dto.RefreshToken{first, second, single, foreign} {
+			if err := repos.Tokens.Save(ctx, token); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+		}
+
+		// чужую сессию удалить нельзя
+		if ok, err := repos.Tokens.DeleteFamily(ctx, other.UUID, family); err != nil || ok {
+			t.Errorf("DeleteFamily of foreign session = %v, %v, want false", ok, err)
+		}
+		if ok, err := repos.Tokens.DeleteFamily(ctx, user.UUID, family); err != nil || !ok {
+			t.Errorf("DeleteFamily = %v, %v, want true", ok, err)
+		}
+		if ok, err := repos.Tokens.DeleteFamily(ctx, user.UUID, family); err != nil || ok {
+			t.Errorf("DeleteFamily of deleted session = %v, %v, want false", ok, err)
+		}
+		assertTokens(t, repos, user.UUID, single.TokenHash)
+
+		if err := repos.Tokens.DeleteByHash(ctx, single.TokenHash); err != nil {
+			t.Fatalf("DeleteByHash: %v", err)
+		}
+		assertTokens(t, repos, user.UUID)
+
+		if err := repos.Tokens.Save(ctx, newToken(user.UUID, uuid.New(), time.Hour)); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		if err := repos.Tokens.DeleteAllByUser(ctx, user.UUID); err != nil {
+			t.Fatalf("DeleteAllByUser: %v", err)
+		}
+		assertTokens(t, repos, user.UUID)
+		assertTokens(t, repos, other.UUID, foreign.TokenHash)
+	})
+}
+
+// newToken собирает refresh токен с уникальным хэшем
+func newToken(userID, familyID uuid.UUID, ttl time.Duration) dto.RefreshToken {
+	return dto.RefreshToken{
+		TokenHash: "hash-" + uuid.NewString(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(ttl),
+		UserAgent: "contract",
+		IP:        "127.0.0.1",
+	}
+}
+
+func tokenHashes(tokens []dto.RefreshToken) []string {
+	hashes := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		hashes = append(hashes, token.TokenHash)
+	}
+	return hashes
+}
+
+// assertTokens сверяет действующие токены пользователя
+func assertTokens(t *testing.T, repos Repositories, userID uuid.UUID, want ...string) {
+	t.Helper()
+	tokens, err := repos.Tokens.ListByUser(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("ListByUser: %v", err)
+	}
+	if got := tokenHashes(tokens); !slices.Equal(got, want) && len(got)+len(want) > 0 {
+		t.Errorf("ListByUser = %v, want %v", got, want)
+	}
+}
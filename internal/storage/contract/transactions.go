@@ -0,0 +1,114 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	userModel "tic-tac-toe/internal/domain/model/user"
+
+	"github.com/google/uuid"
+)
+
+// RunTransactions проверяет model.Manager: изменения репозиториев внутри
+// WithinTx фиксируются вместе или откатываются вместе
+func RunTransactions(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	errAbort := errors.New("abort")
+
+	t.Run("Commit", func(t *testing.T) {
+		repos := backend(t)
+		user := userModel.User{UUID: uuid.New(), Login: "tx_" + uuid.NewString()[:8], Password: "hash"}
+		game := newGame(user.UUID, nil, gameModel.Waiting)
+
+		err := repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+			if err := repos.Users.CreateUser(ctx, user); err != nil {
+				return err
+			}
+			return repos.Games.SaveGame(ctx, game)
+		})
+		if err != nil {
+			t.Fatalf("WithinTx: %v", err)
+		}
+		if _, err := repos.Users.GetUserByID(ctx, user.UUID); err != nil {
+			t.Errorf("user after commit: %v", err)
+		}
+		if _, err := repos.Games.GetCurrentGame(ctx, game.UUID); err != nil {
+			t.Errorf("game after commit: %v", err)
+		}
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		repos := backend(t)
+		existing := newUser(t, repos, "tx_existing")
+		user := userModel.User{UUID: uuid.New(), Login: "tx_" + uuid.NewString()[:8], Password: "hash"}
+		game := newGame(user.UUID, nil, gameModel.Waiting)
+
+		err := repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+			if err := repos.Users.CreateUser(ctx, user); err != nil {
+				return err
+			}
+			if err := repos.Users.UpdatePassword(ctx, existing.UUID, "changed"); err != nil {
+				return err
+			}
+			if err := repos.Games.SaveGame(ctx, game); err != nil {
+				return err
+			}
+			// изменения видны внутри транзакции
+			if _, err := repos.Games.GetCurrentGame(ctx, game.UUID); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("WithinTx: err = %v, want fn error", err)
+		}
+
+		if _, err := repos.Users.GetUserByID(ctx, user.UUID); !errors.Is(err, userModel.ErrUserNotFound) {
+			t.Errorf("user after rollback: err = %v, want ErrUserNotFound", err)
+		}
+		if _, err := repos.Games.GetCurrentGame(ctx, game.UUID); err == nil {
+			t.Error("game after rollback: want error")
+		}
+		got, err := repos.Users.GetUserByLogin(ctx, existing.Login)
+		if err != nil {
+			t.Fatalf("GetUserByLogin: %v", err)
+		}
+		if got.Password != existing.Password {
+			t.Errorf("password after rollback = %q, want %q", got.Password, existing.Password)
+		}
+	})
+
+	t.Run("NestedRollback", func(t *testing.T) {
+		repos := backend(t)
+		outer := userModel.User{UUID: uuid.New(), Login: "tx_" + uuid.NewString()[:8], Password: "hash"}
+		inner := userModel.User{UUID: uuid.New(), Login: "tx_" + uuid.NewString()[:8], Password: "hash"}
+
+		// вложенная транзакция откатывает только свои изменения
+		err := repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+			if err := repos.Users.CreateUser(ctx, outer); err != nil {
+				return err
+			}
+			err := repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+				if err := repos.Users.CreateUser(ctx, inner); err != nil {
+					return err
+				}
+				return errAbort
+			})
+			if !errors.Is(err, errAbort) {
+				t.Errorf("nested WithinTx: err = %v, want fn error", err)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("WithinTx: %v", err)
+		}
+		if _, err := repos.Users.GetUserByID(ctx, outer.UUID); err != nil {
+			t.Errorf("outer user: %v", err)
+		}
+		if _, err := repos.Users.GetUserByID(ctx, inner.UUID); !errors.Is(err, userModel.ErrUserNotFound) {
+			t.Errorf("inner user: err = %v, want ErrUserNotFound", err)
+		}
+	})
+}
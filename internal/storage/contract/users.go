@@ -0,0 +1,114 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	userModel "tic-tac-toe/internal/domain/model/user"
+
+	"github.com/google/uuid"
+)
+
+// RunUsers проверяет model.UserRepository
+func RunUsers(t *testing.T, backend Backend) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repos := backend(t)
+		user := newUser(t, repos, "create")
+
+		byID, err := repos.Users.GetUserByID(ctx, user.UUID)
+		if err != nil {
+			t.Fatalf("GetUserByID: %v", err)
+		}
+		if byID.UUID != user.UUID || byID.Login != user.Login || len(byID.Roles) != 0 {
+			t.Errorf("GetUserByID = %+v, want %s/%s without roles", byID, user.UUID, user.Login)
+		}
+
+		byLogin, err := repos.Users.GetUserByLogin(ctx, user.Login)
+		if err != nil {
+			t.Fatalf("GetUserByLogin: %v", err)
+		}
+		if byLogin.UUID != user.UUID || byLogin.Password != user.Password {
+			t.Errorf("GetUserByLogin = %+v, want %s with password hash", byLogin, user.UUID)
+		}
+	})
+
+	t.Run("DuplicateLogin", func(t *testing.T) {
+		repos := backend(t)
+		user := newUser(t, repos, "dup")
+
+		err := repos.Users.CreateUser(ctx, userModel.User{UUID: uuid.New(), Login: user.Login, Password: "x"})
+		if err == nil {
+			t.Error("CreateUser with taken login: want error")
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repos := backend(t)
+		missing := uuid.New()
+
+		if _, err := repos.Users.GetUserByID(ctx, missing); !errors.Is(err, userModel.ErrUserNotFound) {
+			t.Errorf("GetUserByID: err = %v, want ErrUserNotFound", err)
+		}
+		if _, err := repos.Users.GetUserByLogin(ctx, "missing_"+missing.String()[:8]); !errors.Is(err, userModel.ErrUserNotFound) {
+			t.Errorf("GetUserByLogin: err = %v, want ErrUserNotFound", err)
+		}
+		if err := repos.Users.UpdatePassword(ctx, missing, "hash"); !errors.Is(err, userModel.ErrUserNotFound) {
+			t.Errorf("UpdatePassword: err = %v, want ErrUserNotFound", err)
+		}
+		if err := repos.Users.AddRole(ctx, missing, userModel.RoleAdmin); !errors.Is(err, userModel.ErrUserNotFound) {
+			t.Errorf("AddRole: err = %v, want ErrUserNotFound", err)
+		}
+		if err := repos.Users.RemoveRole(ctx, missing, userModel.RoleAdmin); !errors.Is(err, userModel.ErrUserNotFound) {
+			t.Errorf("RemoveRole: err = %v, want ErrUserNotFound", err)
+		}
+	})
+
+	t.Run("UpdatePassword", func(t *testing.T) {
+		repos := backend(t)
+		user := newUser(t, repos, "password")
+
+		if err := repos.Users.UpdatePassword(ctx, user.UUID, "new-hash"); err != nil {
+			t.Fatalf("UpdatePassword: %v", err)
+		}
+		got, err := repos.Users.GetUserByLogin(ctx, user.Login)
+		if err != nil {
+			t.Fatalf("GetUserByLogin: %v", err)
+		}
+		if got.Password != "new-hash" {
+			t.Errorf("Password = %q, want new-hash", got.Password)
+		}
+	})
+
+	t.Run("Roles", func(t *testing.T) {
+		repos := backend(t)
+		user := newUser(t, repos, "roles")
+
+		// повторная выдача роль не дублирует
+		for range 2 {
+			if err := repos.Users.AddRole(ctx, user.UUID, userModel.RoleAdmin); err != nil {
+				t.Fatalf("AddRole: %v", err)
+			}
+		}
+		got, err := repos.Users.GetUserByID(ctx, user.UUID)
+		if err != nil {
+			t.Fatalf("GetUserByID: %v", err)
+		}
+		if len(got.Roles) != 1 || !got.HasRole(userModel.RoleAdmin) {
+			t.Errorf("Roles = %v, want [admin]", got.Roles)
+		}
+
+		if err := repos.Users.RemoveRole(ctx, user.UUID, userModel.RoleAdmin); err != nil {
+			t.Fatalf("RemoveRole: %v", err)
+		}
+		got, err = repos.Users.GetUserByID(ctx, user.UUID)
+		if err != nil {
+			t.Fatalf("GetUserByID: %v", err)
+		}
+		if len(got.Roles) != 0 {
+			t.Errorf("Roles = %v, want none", got.Roles)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/achievement"
+
+	"github.com/google/uuid"
+)
+
+type achievementRepository struct {
+	store *Store
+}
+
+func NewAchievementRepository(store *Store) model.AchievementRepository {
+	return &achievementRepository{
+		store: store,
+	}
+}
+
+func (r *achievementRepository) Grant(ctx context.Context, userID uuid.UUID, code model.Code) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	achievements := s.achievements[userID]
+	if slices.ContainsFunc(achievements, func(a model.UserAchievement) bool { return a.Code == code }) {
+		return false, nil
+	}
+	s.achievements[userID] = append(achievements, model.UserAchievement{
+		UserID:     userID,
+		Code:       code,
+		UnlockedAt: time.Now(),
+	})
+	return true, nil
+}
+
+func (r *achievementRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]model.UserAchievement, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	// выдаются по времени, поэтому уже упорядочены по unlocked_at
+	return slices.Clone(s.achievements[userID]), nil
+}
+
+// LockStats в памяти не блокирует отдельную запись: транзакция держит всё хранилище
+func (r *achievementRepository) LockStats(ctx context.Context, userID uuid.UUID) (*model.PlayerStats, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	stats, ok := s.achievementStats[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &stats, nil
+}
+
+func (r *achievementRepository) SaveStats(ctx context.Context, userID uuid.UUID, stats model.PlayerStats) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	s.achievementStats[userID] = stats
+	return nil
+}
+
+func (r *achievementRepository) CountGames(ctx context.Context, userID uuid.UUID, gameIDs []uuid.UUID) ([]uuid.UUID, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	games := s.achievementGames[userID]
+	if games == nil {
+		games = map[uuid.UUID]bool{}
+		s.achievementGames[userID] = games
+	}
+	var counted []uuid.UUID
+	for _, id := range gameIDs {
+		if !games[id] {
+			games[id] = true
+			counted = append(counted, id)
+		}
+	}
+	return counted, nil
+}
@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/user"
+
+	"github.com/google/uuid"
+)
+
+type blockRow struct {
+	blocker   uuid.UUID
+	blocked   uuid.UUID
+	createdAt time.Time
+}
+
+type friendRepository struct {
+	store *Store
+}
+
+func NewFriendRepository(store *Store) model.FriendRepository {
+	return &friendRepository{
+		store: store,
+	}
+}
+
+func (r *friendRepository) GetFriendship(ctx context.Context, a, b uuid.UUID) (*model.Friendship, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	f := s.friendship(a, b)
+	if f == nil {
+		return nil, nil
+	}
+	c := *f
+	c.AcceptedAt = copyTime(f.AcceptedAt)
+	return &c, nil
+}
+
+func (r *friendRepository) CreateRequest(ctx context.Context, requester, addressee uuid.UUID) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	// у пары может быть только одна связь, в том числе встречная заявка
+	if s.friendship(requester, addressee) != nil {
+		return false, nil
+	}
+	s.friendships = append(s.friendships, &model.Friendship{
+		RequesterID: requester,
+		AddresseeID: addressee,
+		Status:      model.FriendshipPending,
+		CreatedAt:   time.Now(),
+	})
+	return true, nil
+}
+
+func (r *friendRepository) Accept(ctx context.Context, requester, addressee uuid.UUID) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	for _, f := range s.friendships {
+		if f.RequesterID == requester && f.AddresseeID == addressee && f.Status == model.FriendshipPending {
+			now := time.Now()
+			f.Status = model.FriendshipAccepted
+			f.AcceptedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *friendRepository) DeleteFriendship(ctx context.Context, a, b uuid.UUID) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	return s.deleteFriendship(a, b), nil
+}
+
+func (r *friendRepository) ListFriends(ctx context.Context, userID uuid.UUID) ([]model.Friend, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var friends []model.Friend
+	for _, f := range s.friendships {
+		other, ok := otherSide(f, userID)
+		user, exists := s.users[other]
+		if !ok || !exists || f.Status != model.FriendshipAccepted {
+			continue
+		}
+		friends = append(friends, model.Friend{UserID: other, Login: user.Login, Since: *f.AcceptedAt})
+	}
+	slices.SortFunc(friends, func(a, b model.Friend) int {
+		return cmp.Compare(a.Login, b.Login)
+	})
+	return friends, nil
+}
+
+func (r *friendRepository) ListRequests(ctx context.Context, userID uuid.UUID) ([]model.FriendRequest, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var requests []model.FriendRequest
+	for _, f := range s.friendships {
+		other, ok := otherSide(f, userID)
+		user, exists := s.users[other]
+		if !ok || !exists || f.Status != model.FriendshipPending {
+			continue
+		}
+		requests = append(requests, model.FriendRequest{
+			UserID:    other,
+			Login:     user.Login,
+			Incoming:  f.AddresseeID == userID,
+			CreatedAt: f.CreatedAt,
+		})
+	}
+	sortByTimeDesc(requests, func(req model.FriendRequest) time.Time { return req.CreatedAt })
+	return requests, nil
+}
+
+func (r *friendRepository) FriendIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var ids []uuid.UUID
+	for _, f := range s.friendships {
+		if other, ok := otherSide(f, userID); ok && f.Status == model.FriendshipAccepted {
+			ids = append(ids, other)
+		}
+	}
+	return ids, nil
+}
+
+func (r *friendRepository) Block(ctx context.Context, blocker, blocked uuid.UUID) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	// блокировка разрывает дружбу и отменяет заявки в обе стороны
+	s.deleteFriendship(blocker, blocked)
+	if s.blockIndex(blocker, blocked) >= 0 {
+		return false, nil
+	}
+	s.blocks = append(s.blocks, blockRow{blocker: blocker, blocked: blocked, createdAt: time.Now()})
+	return true, nil
+}
+
+func (r *friendRepository) Unblock(ctx context.Context, blocker, blocked uuid.UUID) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	i := s.blockIndex(blocker, blocked)
+	if i < 0 {
+		return false, nil
+	}
+	s.blocks = slices.Delete(s.blocks, i, i+1)
+	return true, nil
+}
+
+func (r *friendRepository) ListBlocked(ctx context.Context, blocker uuid.UUID) ([]model.BlockedUser, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var blocked []model.BlockedUser
+	for _, b := range s.blocks {
+		user, ok := s.users[b.blocked]
+		if b.blocker != blocker || !ok {
+			continue
+		}
+		blocked = append(blocked, model.BlockedUser{UserID: b.blocked, Login: user.Login, CreatedAt: b.createdAt})
+	}
+	sortByTimeDesc(blocked, func(b model.BlockedUser) time.Time { return b.CreatedAt })
+	return blocked, nil
+}
+
+func (r *friendRepository) BlockedIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var ids []uuid.UUID
+	for _, b := range s.blocks {
+		var id uuid.UUID
+		switch userID {
+		case b.blocker:
+			id = b.blocked
+		case b.blocked:
+			id = b.blocker
+		default:
+			continue
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *friendRepository) IsBlockedBetween(ctx context.Context, a, b uuid.UUID) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	return s.blockIndex(a, b) >= 0 || s.blockIndex(b, a) >= 0, nil
+}
+
+// friendship ищет связь пары в любом направлении; вызывается под s.mu
+func (s *Store) friendship(a, b uuid.UUID) *model.Friendship {
+	for _, f := range s.friendships {
+		if f.RequesterID == a && f.AddresseeID == b || f.RequesterID == b && f.AddresseeID == a {
+			return f
+		}
+	}
+	return nil
+}
+
+func (s *Store) deleteFriendship(a, b uuid.UUID) bool {
+	n := len(s.friendships)
+	s.friendships = slices.DeleteFunc(s.friendships, func(f *model.Friendship) bool {
+		return f.RequesterID == a && f.AddresseeID == b || f.RequesterID == b && f.AddresseeID == a
+	})
+	return len(s.friendships) < n
+}
+
+func (s *Store) blockIndex(blocker, blocked uuid.UUID) int {
+	return slices.IndexFunc(s.blocks, func(b blockRow) bool {
+		return b.blocker == blocker && b.blocked == blocked
+	})
+}
+
+// otherSide возвращает второго участника связи; false - userID в ней не участвует
+func otherSide(f *model.Friendship, userID uuid.UUID) (uuid.UUID, bool) {
+	switch userID {
+	case f.RequesterID:
+		return f.AddresseeID, true
+	case f.AddresseeID:
+		return f.RequesterID, true
+	default:
+		return uuid.Nil, false
+	}
+}
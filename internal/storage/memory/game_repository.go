@@ -0,0 +1,274 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/game"
+
+	"github.com/google/uuid"
+)
+
+type gameRow struct {
+	game model.Game
+	// номер последнего изменения: порядок записей, изменённых в одно время
+	updated int64
+	// время последнего изменения (updated_at)
+	updatedAt time.Time
+}
+
+type gameRepository struct {
+	store *Store
+}
+
+func NewGameRepository(store *Store) model.GameRepository {
+	return &gameRepository{
+		store: store,
+	}
+}
+
+func (r *gameRepository) SaveGame(ctx context.Context, game model.Game, events ...model.Event) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	// события сериализуются до изменения игры: ошибка не должна оставить игру без событий
+	payloads := make([][]byte, 0, len(events))
+	for _, event := range events {
+		payload, err := marshalEvent(event)
+		if err != nil {
+			return err
+		}
+		payloads = append(payloads, payload)
+	}
+
+	row, ok := s.games[game.UUID]
+	if game.Version == 0 {
+		if ok {
+			return &model.ConflictError{GameID: game.UUID, Version: game.Version}
+		}
+		stored := copyGame(game)
+		stored.Version = 1
+		s.games[game.UUID] = &gameRow{game: stored, updated: s.next(), updatedAt: time.Now()}
+	} else {
+		if !ok || row.game.Version != game.Version {
+			return &model.ConflictError{GameID: game.UUID, Version: game.Version}
+		}
+		stored := copyGame(game)
+		// создатель игры не меняется, сезон не сбрасывается
+		stored.PlayerX = row.game.PlayerX
+		if stored.SeasonID == nil {
+			stored.SeasonID = row.game.SeasonID
+		}
+		stored.Version = row.game.Version + 1
+		row.game = stored
+		row.updated, row.updatedAt = s.next(), time.Now()
+	}
+
+	for i, event := range events {
+		s.insertOutboxEvent(event, payloads[i])
+	}
+	return nil
+}
+
+func (r *gameRepository) GetCurrentGame(ctx context.Context, id uuid.UUID) (model.Game, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	row, ok := s.games[id]
+	if !ok {
+		return model.Game{}, fmt.Errorf("game not found: %w", errNotFound)
+	}
+	return copyGame(row.game), nil
+}
+
+func (r *gameRepository) GetAvailableGames(ctx context.Context) ([]model.Game, error) {
+	return r.findGames(ctx, func(g model.Game) bool {
+		return g.Status == model.Waiting && g.PlayerO == nil
+	}, false), nil
+}
+
+func (r *gameRepository) GetComplitedGames(ctx context.Context, userID uuid.UUID) ([]model.Game, error) {
+	return r.findGames(ctx, func(g model.Game) bool {
+		switch g.Status {
+		case model.WonX:
+			return g.PlayerX == userID
+		case model.WonO:
+			return isPlayerO(g, userID)
+		case model.Draw:
+			return g.PlayerX == userID || isPlayerO(g, userID)
+		default:
+			return false
+		}
+	}, false), nil
+}
+
+// таблица лидеров по играм сезона seasonID: процент побед считается
+// целочисленно и выводится с двумя знаками, как ROUND(... , 2) в Postgres
+func (r *gameRepository) GetLeaderBoard(ctx context.Context, seasonID uuid.UUID, count int) ([]model.UserLeaders, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	type score struct {
+		wins, total int
+	}
+	scores := map[uuid.UUID]*score{}
+	add := func(player uuid.UUID, won bool) {
+		sc, ok := scores[player]
+		if !ok {
+			sc = &score{}
+			scores[player] = sc
+		}
+		sc.total++
+		if won {
+			sc.wins++
+		}
+	}
+	for _, row := range s.games {
+		g := row.game
+		if g.SeasonID == nil || *g.SeasonID != seasonID {
+			continue
+		}
+		add(g.PlayerX, g.Status == model.WonX)
+		if g.PlayerO != nil && *g.PlayerO != g.PlayerX {
+			add(*g.PlayerO, g.Status == model.WonO)
+		}
+	}
+
+	type leader struct {
+		model.UserLeaders
+		rate int
+	}
+	var leaders []leader
+	for userID, sc := range scores {
+		user, ok := s.users[userID]
+		if !ok {
+			continue
+		}
+		rate := sc.wins * 100 / sc.total
+		leaders = append(leaders, leader{
+			UserLeaders: model.UserLeaders{
+				Login:   user.Login,
+				UserId:  userID,
+				WinRate: fmt.Sprintf("%d.00", rate),
+			},
+			rate: rate,
+		})
+	}
+	slices.SortFunc(leaders, func(a, b leader) int {
+		return cmp.Or(cmp.Compare(b.rate, a.rate), cmp.Compare(a.Login, b.Login))
+	})
+
+	var result []model.UserLeaders
+	for _, l := range limitRows(leaders, count) {
+		result = append(result, l.UserLeaders)
+	}
+	return result, nil
+}
+
+// завершённые игры между двумя игроками, от новых к старым
+func (r *gameRepository) GetGamesBetween(ctx context.Context, playerID, opponentID uuid.UUID) ([]model.Game, error) {
+	return r.findGames(ctx, func(g model.Game) bool {
+		return g.Finished() && (g.PlayerX == playerID && isPlayerO(g, opponentID) ||
+			g.PlayerX == opponentID && isPlayerO(g, playerID))
+	}, true), nil
+}
+
+// все завершённые игры пользователя (победы, поражения, ничьи), от новых к старым
+func (r *gameRepository) GetFinishedGamesByUser(ctx context.Context, userID uuid.UUID) ([]model.Game, error) {
+	return r.findGames(ctx, func(g model.Game) bool {
+		return g.Finished() && (g.PlayerX == userID || isPlayerO(g, userID))
+	}, true), nil
+}
+
+func (r *gameRepository) DeleteGame(ctx context.Context, id uuid.UUID) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	for _, p := range s.pairings {
+		if p.GameID != nil && *p.GameID == id {
+			p.GameID = nil
+		}
+	}
+	if _, ok := s.games[id]; !ok {
+		return false, nil
+	}
+	delete(s.games, id)
+	return true, nil
+}
+
+func (r *gameRepository) CancelWaitingGames(ctx context.Context, playerX uuid.UUID) (int64, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	// турнирные игры не трогаем, иначе пара тура никогда не завершится
+	tournamentGames := map[uuid.UUID]bool{}
+	for _, p := range s.pairings {
+		if p.GameID != nil {
+			tournamentGames[*p.GameID] = true
+		}
+	}
+
+	var cancelled int64
+	for id, row := range s.games {
+		g := &row.game
+		if g.PlayerX != playerX || g.Status != model.Waiting || g.PlayerO != nil || tournamentGames[id] {
+			continue
+		}
+		g.Status = model.Cancelled
+		g.Version++
+		row.updated, row.updatedAt = s.next(), time.Now()
+		cancelled++
+	}
+	return cancelled, nil
+}
+
+// findGames возвращает копии подходящих игр; newestFirst - по времени
+// последнего изменения, от новых к старым
+func (r *gameRepository) findGames(ctx context.Context, match func(model.Game) bool, newestFirst bool) []model.Game {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var rows []*gameRow
+	for _, row := range s.games {
+		if match(row.game) {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b *gameRow) int {
+		if newestFirst {
+			return cmp.Compare(b.updated, a.updated)
+		}
+		return cmp.Compare(a.updated, b.updated)
+	})
+
+	var games []model.Game
+	for _, row := range rows {
+		games = append(games, copyGame(row.game))
+	}
+	return games
+}
+
+func isPlayerO(g model.Game, userID uuid.UUID) bool {
+	return g.PlayerO != nil && *g.PlayerO == userID
+}
+
+// copyGame копирует игру вместе с полем и символами, чтобы вызывающий
+// не мог изменить сохранённое состояние
+func copyGame(g model.Game) model.Game {
+	c := g
+	if g.Field != nil {
+		field := make([][]int, len(g.Field.Field))
+		for i, line := range g.Field.Field {
+			field[i] = slices.Clone(line)
+		}
+		c.Field = &model.GameField{Field: field}
+	}
+	c.Symbols = maps.Clone(g.Symbols)
+	c.PlayerO = copyUUID(g.PlayerO)
+	c.SeasonID = copyUUID(g.SeasonID)
+	return c
+}
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/idempotency"
+
+	"github.com/google/uuid"
+)
+
+type idempotencyKey struct {
+	userID uuid.UUID
+	key    string
+}
+
+type idempotencyRepository struct {
+	store *Store
+}
+
+func NewIdempotencyRepository(store *Store) model.Repository {
+	return &idempotencyRepository{
+		store: store,
+	}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, record model.Record, now time.Time) (model.Record, bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	// истёкший ключ занимается заново, как новый
+	key := idempotencyKey{record.UserID, record.Key}
+	if row, ok := s.idempotency[key]; ok && row.ExpiresAt.After(now) {
+		return copyRecord(*row), false, nil
+	}
+	stored := copyRecord(record)
+	stored.Response = nil
+	s.idempotency[key] = &stored
+	return copyRecord(stored), true, nil
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, userID uuid.UUID, key string, response model.Response) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if row, ok := s.idempotency[idempotencyKey{userID, key}]; ok {
+		response.Body = slices.Clone(response.Body)
+		row.Response = &response
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if row, ok := s.idempotency[idempotencyKey{userID, key}]; ok && row.Response == nil {
+		delete(s.idempotency, idempotencyKey{userID, key})
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var deleted int64
+	for key, row := range s.idempotency {
+		if row.ExpiresAt.Before(before) {
+			delete(s.idempotency, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func copyRecord(record model.Record) model.Record {
+	if record.Response != nil {
+		response := *record.Response
+		response.Body = slices.Clone(response.Body)
+		record.Response = &response
+	}
+	return record
+}
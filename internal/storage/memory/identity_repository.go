@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/identity"
+)
+
+type identityKey struct {
+	issuer  string
+	subject string
+}
+
+type identityRepository struct {
+	store *Store
+}
+
+func NewIdentityRepository(store *Store) model.IdentityRepository {
+	return &identityRepository{
+		store: store,
+	}
+}
+
+func (r *identityRepository) Find(ctx context.Context, issuer, subject string) (*model.Identity, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	identity, ok := s.identities[identityKey{issuer, subject}]
+	if !ok {
+		return nil, nil
+	}
+	return &identity, nil
+}
+
+func (r *identityRepository) Create(ctx context.Context, identity model.Identity) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	key := identityKey{identity.Issuer, identity.Subject}
+	if _, ok := s.identities[key]; ok {
+		return false, nil
+	}
+	s.identities[key] = identity
+	return true, nil
+}
+
+type loginStateRepository struct {
+	store *Store
+}
+
+func NewLoginStateRepository(store *Store) model.LoginStateRepository {
+	return &loginStateRepository{
+		store: store,
+	}
+}
+
+func (r *loginStateRepository) Save(ctx context.Context, state model.LoginState) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	// заодно удаляем брошенные входы
+	now := time.Now()
+	for key, st := range s.loginStates {
+		if st.ExpiresAt.Before(now) {
+			delete(s.loginStates, key)
+		}
+	}
+
+	if _, ok := s.loginStates[state.State]; ok {
+		return fmt.Errorf("ошибка сохранения состояния OIDC: состояние уже существует")
+	}
+	s.loginStates[state.State] = state
+	return nil
+}
+
+func (r *loginStateRepository) Consume(ctx context.Context, state string) (*model.LoginState, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	st, ok := s.loginStates[state]
+	if !ok || !st.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+	delete(s.loginStates, state)
+	return &st, nil
+}
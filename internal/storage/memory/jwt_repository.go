@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/auth"
+	dto "tic-tac-toe/internal/storage/postgres/dto"
+
+	"github.com/google/uuid"
+)
+
+type tokenRepository struct {
+	store *Store
+}
+
+func NewTokenRepository(store *Store) model.TokenRepository {
+	return &tokenRepository{
+		store: store,
+	}
+}
+
+func (t *tokenRepository) Save(ctx context.Context, token dto.RefreshToken) error {
+	s := t.store
+	defer s.lock(ctx)()
+
+	return s.insertToken(token, time.Now(), nil)
+}
+
+func (t *tokenRepository) FindByHash(ctx context.Context, hash string) (dto.RefreshToken, error) {
+	s := t.store
+	defer s.lock(ctx)()
+
+	token := s.tokenByHash(hash)
+	if token == nil {
+		return dto.RefreshToken{}, errors.New("refresh token not found")
+	}
+	return copyToken(*token), nil
+}
+
+func (t *tokenRepository) DeleteByHash(ctx context.Context, hash string) error {
+	t.deleteTokens(ctx, func(token *dto.RefreshToken) bool {
+		return token.TokenHash == hash
+	})
+	return nil
+}
+
+func (t *tokenRepository) DeleteAllByUser(ctx context.Context, userID uuid.UUID) error {
+	t.deleteTokens(ctx, func(token *dto.RefreshToken) bool {
+		return token.UserID == userID
+	})
+	return nil
+}
+
+func (t *tokenRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]dto.RefreshToken, error) {
+	s := t.store
+	defer s.lock(ctx)()
+
+	now := time.Now()
+	var tokens []dto.RefreshToken
+	// новые токены добавляются в конец: обратный обход даёт порядок от новых к старым
+	for _, token := range slices.Backward(s.tokens) {
+		if token.UserID == userID && token.ExpiresAt.After(now) && token.RotatedAt == nil {
+			tokens = append(tokens, copyToken(*token))
+		}
+	}
+	sortByTimeDesc(tokens, func(token dto.RefreshToken) time.Time { return token.CreatedAt })
+	return tokens, nil
+}
+
+func (t *tokenRepository) DeleteFamily(ctx context.Context, userID, familyID uuid.UUID) (bool, error) {
+	deleted := t.deleteTokens(ctx, func(token *dto.RefreshToken) bool {
+		return token.FamilyID == familyID && token.UserID == userID
+	})
+	return deleted > 0, nil
+}
+
+func (t *tokenRepository) Rotate(ctx context.Context, oldHash string, next dto.RefreshToken) (bool, error) {
+	s := t.store
+	defer s.lock(ctx)()
+
+	old := s.tokenByHash(oldHash)
+	if old == nil || old.RotatedAt != nil {
+		return false, nil
+	}
+	if s.tokenByHash(next.TokenHash) != nil {
+		return false, fmt.Errorf("Ошибка добавления токена в бд: токен уже существует")
+	}
+
+	now := time.Now()
+	old.RotatedAt = &now
+	// новый токен продолжает сессию: время входа и клиент сохраняются
+	return true, s.insertToken(next, next.CreatedAt, &now)
+}
+
+func (t *tokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	s := t.store
+	defer s.lock(ctx)()
+
+	// сессии, у которых ещё есть действующий токен
+	live := map[uuid.UUID]bool{}
+	for _, token := range s.tokens {
+		if !token.ExpiresAt.Before(before) {
+			live[token.FamilyID] = true
+		}
+	}
+	n := len(s.tokens)
+	s.tokens = slices.DeleteFunc(s.tokens, func(token *dto.RefreshToken) bool {
+		return token.ExpiresAt.Before(before) && !live[token.FamilyID]
+	})
+	return int64(n - len(s.tokens)), nil
+}
+
+// insertToken вызывается под s.mu
+func (s *Store) insertToken(token dto.RefreshToken, createdAt time.Time, lastUsedAt *time.Time) error {
+	if s.tokenByHash(token.TokenHash) != nil {
+		return fmt.Errorf("Ошибка добавления токена в бд: токен уже существует")
+	}
+	s.tokens = append(s.tokens, &dto.RefreshToken{
+		ID:         uuid.New(),
+		TokenHash:  token.TokenHash,
+		UserID:     token.UserID,
+		FamilyID:   token.FamilyID,
+		ExpiresAt:  token.ExpiresAt,
+		CreatedAt:  createdAt,
+		LastUsedAt: copyTime(lastUsedAt),
+		UserAgent:  token.UserAgent,
+		IP:         token.IP,
+	})
+	return nil
+}
+
+func (s *Store) tokenByHash(hash string) *dto.RefreshToken {
+	for _, token := range s.tokens {
+		if token.TokenHash == hash {
+			return token
+		}
+	}
+	return nil
+}
+
+func (t *tokenRepository) deleteTokens(ctx context.Context, match func(token *dto.RefreshToken) bool) int {
+	s := t.store
+	defer s.lock(ctx)()
+
+	n := len(s.tokens)
+	s.tokens = slices.DeleteFunc(s.tokens, match)
+	return n - len(s.tokens)
+}
+
+func copyToken(token dto.RefreshToken) dto.RefreshToken {
+	token.LastUsedAt = copyTime(token.LastUsedAt)
+	token.RotatedAt = copyTime(token.RotatedAt)
+	return token
+}
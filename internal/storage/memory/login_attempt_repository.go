@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/auth"
+)
+
+type attemptKey struct {
+	scope model.AttemptScope
+	key   string
+}
+
+type loginAttemptRepository struct {
+	store *Store
+}
+
+func NewLoginAttemptRepository(store *Store) model.LoginAttemptRepository {
+	return &loginAttemptRepository{
+		store: store,
+	}
+}
+
+func (r *loginAttemptRepository) Get(ctx context.Context, scope model.AttemptScope, key string) (*model.LoginAttempt, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	attempt, ok := s.loginAttempts[attemptKey{scope, key}]
+	if !ok {
+		return nil, nil
+	}
+	c := copyAttempt(*attempt)
+	return &c, nil
+}
+
+func (r *loginAttemptRepository) RecordFailure(ctx context.Context, scope model.AttemptScope, key string, window time.Duration) (model.LoginAttempt, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	now := time.Now()
+	attempt, ok := s.loginAttempts[attemptKey{scope, key}]
+	if !ok {
+		attempt = &model.LoginAttempt{Scope: scope, Key: key}
+		s.loginAttempts[attemptKey{scope, key}] = attempt
+	}
+	// устаревший счётчик сбрасывается вместе с истёкшей блокировкой
+	if attempt.LastFailureAt.Before(now.Add(-window)) {
+		attempt.Failures = 1
+	} else {
+		attempt.Failures++
+	}
+	if attempt.LockedUntil != nil && attempt.LockedUntil.Before(now) {
+		attempt.LockedUntil = nil
+	}
+	attempt.LastFailureAt = now
+	return copyAttempt(*attempt), nil
+}
+
+func (r *loginAttemptRepository) Lock(ctx context.Context, scope model.AttemptScope, key string, until time.Time) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	attempt, ok := s.loginAttempts[attemptKey{scope, key}]
+	if !ok {
+		return nil
+	}
+	if attempt.LockedUntil == nil || attempt.LockedUntil.Before(until) {
+		attempt.LockedUntil = &until
+	}
+	return nil
+}
+
+func (r *loginAttemptRepository) Reset(ctx context.Context, scope model.AttemptScope, key string) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if _, ok := s.loginAttempts[attemptKey{scope, key}]; !ok {
+		return false, nil
+	}
+	delete(s.loginAttempts, attemptKey{scope, key})
+	return true, nil
+}
+
+func copyAttempt(attempt model.LoginAttempt) model.LoginAttempt {
+	attempt.LockedUntil = copyTime(attempt.LockedUntil)
+	return attempt
+}
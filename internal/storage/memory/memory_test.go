@@ -0,0 +1,24 @@
+package memory_test
+
+import (
+	"testing"
+
+	"tic-tac-toe/internal/storage/contract"
+	"tic-tac-toe/internal/storage/memory"
+)
+
+func newRepositories(t *testing.T) contract.Repositories {
+	store := memory.NewStore()
+	return contract.Repositories{
+		Games:    memory.NewGameRepository(store),
+		Users:    memory.NewUserRepository(store),
+		Tokens:   memory.NewTokenRepository(store),
+		Seasons:  memory.NewSeasonRepository(store),
+		Presence: memory.NewPresenceRepository(store),
+		Tx:       memory.NewTxManager(store),
+	}
+}
+
+func TestContract(t *testing.T) {
+	contract.Run(t, newRepositories)
+}
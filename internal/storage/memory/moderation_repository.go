@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/moderation"
+
+	"github.com/google/uuid"
+)
+
+type sanctionKey struct {
+	userID uuid.UUID
+	kind   model.SanctionKind
+}
+
+type moderationRepository struct {
+	store *Store
+}
+
+func NewModerationRepository(store *Store) model.ModerationRepository {
+	return &moderationRepository{
+		store: store,
+	}
+}
+
+func (r *moderationRepository) Apply(ctx context.Context, sanction model.Sanction, entry model.LogEntry) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	sanction.ExpiresAt = copyTime(sanction.ExpiresAt)
+	s.sanctions[sanctionKey{sanction.UserID, sanction.Kind}] = sanction
+	s.moderationLog = append(s.moderationLog, copyLogEntry(entry))
+	return nil
+}
+
+func (r *moderationRepository) Lift(ctx context.Context, userID uuid.UUID, kind model.SanctionKind, entry model.LogEntry) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	// истёкшая санкция удаляется, но снятием не считается
+	sanction, ok := s.sanctions[sanctionKey{userID, kind}]
+	if !ok {
+		return false, nil
+	}
+	delete(s.sanctions, sanctionKey{userID, kind})
+	if !sanction.Active(time.Now()) {
+		return false, nil
+	}
+	s.moderationLog = append(s.moderationLog, copyLogEntry(entry))
+	return true, nil
+}
+
+func (r *moderationRepository) Active(ctx context.Context, userID uuid.UUID, kind model.SanctionKind, now time.Time) (*model.Sanction, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	sanction, ok := s.sanctions[sanctionKey{userID, kind}]
+	if !ok || !sanction.Active(now) {
+		return nil, nil
+	}
+	sanction.ExpiresAt = copyTime(sanction.ExpiresAt)
+	return &sanction, nil
+}
+
+func (r *moderationRepository) GetLog(ctx context.Context, target *uuid.UUID, limit int) ([]model.LogEntry, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var entries []model.LogEntry
+	for _, entry := range s.moderationLog {
+		if target == nil || entry.TargetID == *target {
+			entries = append(entries, copyLogEntry(entry))
+		}
+	}
+	sortByTimeDesc(entries, func(entry model.LogEntry) time.Time { return entry.CreatedAt })
+	return limitRows(entries, limit), nil
+}
+
+func copyLogEntry(entry model.LogEntry) model.LogEntry {
+	entry.ActorID = copyUUID(entry.ActorID)
+	entry.ExpiresAt = copyTime(entry.ExpiresAt)
+	return entry
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	model "tic-tac-toe/internal/domain/model/notification"
+)
+
+type notificationBroker struct {
+	mu       sync.Mutex
+	handlers map[int]func(model.Notification)
+	next     int
+}
+
+// NewNotificationBroker рассылает уведомления внутри процесса: в режиме
+// хранения в памяти инстанс один
+func NewNotificationBroker() model.Broker {
+	return &notificationBroker{
+		handlers: map[int]func(model.Notification){},
+	}
+}
+
+func (b *notificationBroker) Publish(ctx context.Context, n model.Notification) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, handler := range b.handlers {
+		handler(n)
+	}
+	return nil
+}
+
+func (b *notificationBroker) Listen(ctx context.Context, handler func(model.Notification)) error {
+	b.mu.Lock()
+	id := b.next
+	b.next++
+	b.handlers[id] = handler
+	b.mu.Unlock()
+
+	<-ctx.Done()
+
+	b.mu.Lock()
+	delete(b.handlers, id)
+	b.mu.Unlock()
+	return ctx.Err()
+}
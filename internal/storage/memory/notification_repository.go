@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/notification"
+
+	"github.com/google/uuid"
+)
+
+type notificationRepository struct {
+	store *Store
+}
+
+func NewNotificationRepository(store *Store) model.NotificationRepository {
+	return &notificationRepository{
+		store: store,
+	}
+}
+
+func (r *notificationRepository) Save(ctx context.Context, n model.Notification) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if slices.ContainsFunc(s.notifications, func(existing *model.Notification) bool { return existing.UUID == n.UUID }) {
+		return fmt.Errorf("ошибка сохранения уведомления: уведомление %s уже существует", n.UUID)
+	}
+	stored := copyNotification(n)
+	stored.ReadAt = nil
+	s.notifications = append(s.notifications, &stored)
+	return nil
+}
+
+func (r *notificationRepository) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]model.Notification, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var notifications []model.Notification
+	for _, n := range s.notifications {
+		if n.UserID == userID && (!unreadOnly || !n.Read()) {
+			notifications = append(notifications, copyNotification(*n))
+		}
+	}
+	sortByTimeDesc(notifications, func(n model.Notification) time.Time { return n.CreatedAt })
+	return limitRows(notifications, limit), nil
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, at time.Time) (int64, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var marked int64
+	for _, n := range s.notifications {
+		if n.UserID != userID || n.Read() || len(ids) > 0 && !slices.Contains(ids, n.UUID) {
+			continue
+		}
+		n.ReadAt = &at
+		marked++
+	}
+	return marked, nil
+}
+
+func copyNotification(n model.Notification) model.Notification {
+	n.Data = maps.Clone(n.Data)
+	n.ReadAt = copyTime(n.ReadAt)
+	return n
+}
@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/game"
+	dto "tic-tac-toe/internal/storage/postgres/dto"
+	"tic-tac-toe/internal/storage/postgres/mappers"
+)
+
+// событие хранится в том же JSON, что и в Postgres
+type outboxRow struct {
+	id            int64
+	eventType     model.EventType
+	payload       []byte
+	status        model.OutboxStatus
+	attempts      int
+	done          []string
+	lastError     string
+	nextAttemptAt time.Time
+	processedAt   *time.Time
+}
+
+type outboxRepository struct {
+	store *Store
+}
+
+func NewOutboxRepository(store *Store) model.OutboxRepository {
+	return &outboxRepository{
+		store: store,
+	}
+}
+
+func marshalEvent(event model.Event) ([]byte, error) {
+	payload, err := json.Marshal(mappers.GameEventFromDomainToDB(event))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации события: %w", err)
+	}
+	return payload, nil
+}
+
+// insertOutboxEvent записывает событие вместе с сохранением игры; вызывается под s.mu
+func (s *Store) insertOutboxEvent(event model.Event, payload []byte) {
+	s.outbox = append(s.outbox, &outboxRow{
+		id:            s.next(),
+		eventType:     event.Type,
+		payload:       payload,
+		status:        model.OutboxPending,
+		nextAttemptAt: event.OccurredAt,
+	})
+}
+
+func (r *outboxRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.OutboxRecord, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var records []model.OutboxRecord
+	for _, row := range s.outbox {
+		if len(records) >= limit {
+			break
+		}
+		if row.status != model.OutboxPending || row.nextAttemptAt.After(now) {
+			continue
+		}
+		var event dto.GameEventDTO
+		if err := json.Unmarshal(row.payload, &event); err != nil {
+			return nil, fmt.Errorf("ошибка десериализации события %d: %w", row.id, err)
+		}
+		row.nextAttemptAt = now.Add(lease)
+		records = append(records, model.OutboxRecord{
+			Event:    mappers.GameEventFromDBToDomain(row.id, row.eventType, event),
+			Attempts: row.attempts,
+			Done:     slices.Clone(row.done),
+		})
+	}
+	return records, nil
+}
+
+func (r *outboxRepository) Complete(ctx context.Context, id int64, now time.Time) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if row := s.findOutbox(id); row != nil {
+		row.status = model.OutboxProcessed
+		row.processedAt = &now
+		row.lastError = ""
+	}
+	return nil
+}
+
+func (r *outboxRepository) Retry(ctx context.Context, record model.OutboxRecord, lastError string, next time.Time) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if row := s.findOutbox(record.Event.ID); row != nil {
+		row.done = slices.Clone(record.Done)
+		row.attempts = record.Attempts
+		row.lastError = lastError
+		row.nextAttemptAt = next
+	}
+	return nil
+}
+
+func (r *outboxRepository) DeadLetter(ctx context.Context, record model.OutboxRecord, lastError string) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if row := s.findOutbox(record.Event.ID); row != nil {
+		row.status = model.OutboxDead
+		row.done = slices.Clone(record.Done)
+		row.attempts = record.Attempts
+		row.lastError = lastError
+	}
+	return nil
+}
+
+func (r *outboxRepository) DeleteProcessed(ctx context.Context, before time.Time) (int64, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	n := len(s.outbox)
+	s.outbox = slices.DeleteFunc(s.outbox, func(row *outboxRow) bool {
+		return row.processedAt != nil && row.processedAt.Before(before)
+	})
+	return int64(n - len(s.outbox)), nil
+}
+
+func (s *Store) findOutbox(id int64) *outboxRow {
+	for _, row := range s.outbox {
+		if row.id == id {
+			return row
+		}
+	}
+	return nil
+}
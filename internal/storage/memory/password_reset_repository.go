@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/auth"
+
+	"github.com/google/uuid"
+)
+
+type resetTokenRow struct {
+	token  model.PasswordResetToken
+	usedAt *time.Time
+}
+
+type passwordResetRepository struct {
+	store *Store
+}
+
+func NewPasswordResetRepository(store *Store) model.PasswordResetRepository {
+	return &passwordResetRepository{
+		store: store,
+	}
+}
+
+func (r *passwordResetRepository) Save(ctx context.Context, token model.PasswordResetToken) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if _, ok := s.resetTokens[token.TokenHash]; ok {
+		return fmt.Errorf("ошибка сохранения токена сброса пароля: токен уже существует")
+	}
+	s.resetTokens[token.TokenHash] = &resetTokenRow{token: token}
+	return nil
+}
+
+func (r *passwordResetRepository) Consume(ctx context.Context, hash string) (uuid.UUID, bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	now := time.Now()
+	row, ok := s.resetTokens[hash]
+	if !ok || row.usedAt != nil || !row.token.ExpiresAt.After(now) {
+		return uuid.Nil, false, nil
+	}
+	row.usedAt = &now
+	return row.token.UserID, true, nil
+}
+
+func (r *passwordResetRepository) DeleteByUser(ctx context.Context, userID uuid.UUID) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	for hash, row := range s.resetTokens {
+		if row.token.UserID == userID {
+			delete(s.resetTokens, hash)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/presence"
+
+	"github.com/google/uuid"
+)
+
+type presenceRepository struct {
+	store *Store
+}
+
+func NewPresenceRepository(store *Store) model.PresenceRepository {
+	return &presenceRepository{
+		store: store,
+	}
+}
+
+func (r *presenceRepository) Touch(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if lastSeen, ok := s.presence[userID]; !ok || lastSeen.Before(now) {
+		s.presence[userID] = now
+	}
+	return nil
+}
+
+func (r *presenceRepository) Get(ctx context.Context, userID uuid.UUID, onlineSince time.Time) (model.Presence, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	presence := model.Presence{UserID: userID}
+	lastSeen, ok := s.presence[userID]
+	if !ok {
+		return presence, nil
+	}
+	presence.LastSeen = lastSeen
+	presence.Online = lastSeen.After(onlineSince)
+	presence.InGame = presence.Online && s.playing(userID, onlineSince)
+	return presence, nil
+}
+
+func (r *presenceRepository) ListOnline(ctx context.Context, onlineSince time.Time, limit int) ([]model.Presence, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var online []model.Presence
+	for userID, lastSeen := range s.presence {
+		user, ok := s.users[userID]
+		if !ok || !lastSeen.After(onlineSince) {
+			continue
+		}
+		online = append(online, model.Presence{
+			UserID:   userID,
+			Login:    user.Login,
+			LastSeen: lastSeen,
+			Online:   true,
+			InGame:   s.playing(userID, onlineSince),
+		})
+	}
+	slices.SortFunc(online, func(a, b model.Presence) int {
+		return cmp.Compare(a.Login, b.Login)
+	})
+	return limitRows(online, limit), nil
+}
+
+// playing сообщает, участвует ли пользователь в идущей игре, в которой ходили
+// позже activeSince; брошенная игра не считается; вызывается под s.mu
+func (s *Store) playing(userID uuid.UUID, activeSince time.Time) bool {
+	for _, row := range s.games {
+		if row.game.Status == gameModel.Playing && row.updatedAt.After(activeSince) &&
+			(row.game.PlayerX == userID || isPlayerO(row.game, userID)) {
+			return true
+		}
+	}
+	return false
+}
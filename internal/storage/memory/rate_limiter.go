@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/ratelimit"
+)
+
+// rateLimiter хранит бакеты в памяти процесса: лимиты не общие для инстансов
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]model.Bucket
+}
+
+func NewRateLimiter() model.Limiter {
+	return &rateLimiter{
+		buckets: map[string]model.Bucket{},
+	}
+}
+
+func (l *rateLimiter) Allow(ctx context.Context, key string, limit model.Limit) (bool, time.Duration, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = limit.FullBucket(now)
+	}
+	bucket, allowed, retryAfter := limit.Take(bucket, now)
+	l.buckets[key] = bucket
+	return allowed, retryAfter, nil
+}
+
+func (l *rateLimiter) Cleanup(ctx context.Context, before time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, bucket := range l.buckets {
+		if bucket.UpdatedAt.Before(before) {
+			delete(l.buckets, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package memory_test
+
+import (
+	"testing"
+
+	"tic-tac-toe/internal/storage/contract"
+	"tic-tac-toe/internal/storage/memory"
+)
+
+func TestRateLimiter(t *testing.T) {
+	contract.RunLimiter(t, memory.NewRateLimiter())
+}
@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/season"
+
+	"github.com/google/uuid"
+)
+
+type seasonRepository struct {
+	store *Store
+}
+
+func NewSeasonRepository(store *Store) model.SeasonRepository {
+	return &seasonRepository{
+		store: store,
+	}
+}
+
+func (r *seasonRepository) GetActive(ctx context.Context) (*model.Season, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	active := s.activeSeason()
+	if active == nil {
+		return nil, nil
+	}
+	c := *active
+	return &c, nil
+}
+
+func (r *seasonRepository) GetSeason(ctx context.Context, id uuid.UUID) (model.Season, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	season, ok := s.seasons[id]
+	if !ok {
+		return model.Season{}, fmt.Errorf("season not found: %w", errNotFound)
+	}
+	return *season, nil
+}
+
+func (r *seasonRepository) ListSeasons(ctx context.Context) ([]model.Season, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var seasons []model.Season
+	for _, season := range s.seasons {
+		seasons = append(seasons, *season)
+	}
+	sortByTimeDesc(seasons, func(season model.Season) time.Time { return season.StartsAt })
+	return seasons, nil
+}
+
+func (r *seasonRepository) CreateActive(ctx context.Context, season model.Season) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if s.activeSeason() != nil {
+		return nil
+	}
+	if _, ok := s.seasons[season.UUID]; ok {
+		return fmt.Errorf("ошибка создания сезона: сезон %s уже существует", season.UUID)
+	}
+	season.Status = model.Active
+	s.seasons[season.UUID] = &season
+	return nil
+}
+
+func (r *seasonRepository) Archive(ctx context.Context, seasonID uuid.UUID, next model.Season) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	season, ok := s.seasons[seasonID]
+	if !ok || season.Status != model.Active {
+		return false, nil
+	}
+	if _, ok := s.seasons[next.UUID]; ok {
+		return false, fmt.Errorf("ошибка архивации сезона: сезон %s уже существует", next.UUID)
+	}
+
+	season.Status = model.Archived
+	next.Status = model.Active
+	s.seasons[next.UUID] = &next
+	return true, nil
+}
+
+func (r *seasonRepository) SaveStandings(ctx context.Context, seasonID uuid.UUID, standings []gameModel.UserLeaders) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	s.standings[seasonID] = slices.Clone(standings)
+	return nil
+}
+
+func (r *seasonRepository) GetArchivedLeaderBoard(ctx context.Context, seasonID uuid.UUID, count int) ([]gameModel.UserLeaders, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	return slices.Clone(limitRows(s.standings[seasonID], count)), nil
+}
+
+// activeSeason вызывается под s.mu
+func (s *Store) activeSeason() *model.Season {
+	for _, season := range s.seasons {
+		if season.Status == model.Active {
+			return season
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/admin"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	userModel "tic-tac-toe/internal/domain/model/user"
+)
+
+type statsRepository struct {
+	store *Store
+}
+
+func NewStatsRepository(store *Store) model.StatsRepository {
+	return &statsRepository{
+		store: store,
+	}
+}
+
+func (r *statsRepository) GetSystemStats(ctx context.Context) (model.SystemStats, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	now := time.Now()
+	stats := model.SystemStats{
+		Users:       len(s.users),
+		Tournaments: len(s.tournaments),
+		CollectedAt: now,
+	}
+	for _, user := range s.users {
+		if user.HasRole(userModel.RoleAdmin) {
+			stats.Admins++
+		}
+	}
+	for _, row := range s.games {
+		switch {
+		case row.game.Status == gameModel.Waiting:
+			stats.GamesWaiting++
+		case row.game.Status == gameModel.Playing:
+			stats.GamesPlaying++
+		case row.game.Finished():
+			stats.GamesFinished++
+		}
+	}
+	for _, token := range s.tokens {
+		if token.ExpiresAt.After(now) {
+			stats.ActiveSessions++
+		}
+	}
+	for _, attempt := range s.loginAttempts {
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+			stats.LockedLogins++
+		}
+	}
+	return stats, nil
+}
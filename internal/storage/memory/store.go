@@ -0,0 +1,236 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	achievementModel "tic-tac-toe/internal/domain/model/achievement"
+	authModel "tic-tac-toe/internal/domain/model/auth"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	idempotencyModel "tic-tac-toe/internal/domain/model/idempotency"
+	identityModel "tic-tac-toe/internal/domain/model/identity"
+	moderationModel "tic-tac-toe/internal/domain/model/moderation"
+	notificationModel "tic-tac-toe/internal/domain/model/notification"
+	seasonModel "tic-tac-toe/internal/domain/model/season"
+	tournamentModel "tic-tac-toe/internal/domain/model/tournament"
+	userModel "tic-tac-toe/internal/domain/model/user"
+	webhookModel "tic-tac-toe/internal/domain/model/webhook"
+	dto "tic-tac-toe/internal/storage/postgres/dto"
+
+	"github.com/google/uuid"
+)
+
+// запись не найдена (аналог pgx.ErrNoRows)
+var errNotFound = errors.New("no rows in result set")
+
+// Store - хранилище в памяти процесса для тестов и локальной разработки.
+// Все таблицы под одним мьютексом; данные пропадают при остановке сервера
+type Store struct {
+	mu sync.Mutex
+	tables
+}
+
+// tables - данные хранилища; копия tables - снимок для отката транзакции
+type tables struct {
+	// счётчик изменений: задаёт порядок записей, у которых совпало время
+	seq int64
+
+	games         map[uuid.UUID]*gameRow
+	outbox        []*outboxRow
+	users         map[uuid.UUID]*userModel.User
+	tokens        []*dto.RefreshToken
+	loginAttempts map[attemptKey]*authModel.LoginAttempt
+	resetTokens   map[string]*resetTokenRow
+	twoFactor     map[uuid.UUID]*authModel.TwoFactor
+	// коды восстановления пользователя: хэш -> использован
+	recoveryCodes map[uuid.UUID]map[string]bool
+	// погашенные challenge токены входа с 2FA: jti -> срок действия
+	usedChallenges map[string]time.Time
+	identities     map[identityKey]identityModel.Identity
+	loginStates    map[string]identityModel.LoginState
+	achievements   map[uuid.UUID][]achievementModel.UserAchievement
+	// счётчики достижений и учтённые в них игры пользователя
+	achievementStats map[uuid.UUID]achievementModel.PlayerStats
+	achievementGames map[uuid.UUID]map[uuid.UUID]bool
+	tournaments      map[uuid.UUID]*tournamentRow
+	participants     map[uuid.UUID][]tournamentModel.Participant
+	pairings         map[uuid.UUID]*tournamentModel.Pairing
+	seasons          map[uuid.UUID]*seasonModel.Season
+	standings        map[uuid.UUID][]gameModel.UserLeaders
+	sanctions        map[sanctionKey]moderationModel.Sanction
+	moderationLog    []moderationModel.LogEntry
+	friendships      []*userModel.Friendship
+	blocks           []blockRow
+	presence         map[uuid.UUID]time.Time
+	notifications    []*notificationModel.Notification
+	webhooks         map[uuid.UUID]*webhookRow
+	deliveries       []*webhookModel.Delivery
+	idempotency      map[idempotencyKey]*idempotencyModel.Record
+}
+
+func NewStore() *Store {
+	return &Store{tables: tables{
+		games:            map[uuid.UUID]*gameRow{},
+		users:            map[uuid.UUID]*userModel.User{},
+		loginAttempts:    map[attemptKey]*authModel.LoginAttempt{},
+		resetTokens:      map[string]*resetTokenRow{},
+		twoFactor:        map[uuid.UUID]*authModel.TwoFactor{},
+		recoveryCodes:    map[uuid.UUID]map[string]bool{},
+		usedChallenges:   map[string]time.Time{},
+		identities:       map[identityKey]identityModel.Identity{},
+		loginStates:      map[string]identityModel.LoginState{},
+		achievements:     map[uuid.UUID][]achievementModel.UserAchievement{},
+		achievementStats: map[uuid.UUID]achievementModel.PlayerStats{},
+		achievementGames: map[uuid.UUID]map[uuid.UUID]bool{},
+		tournaments:      map[uuid.UUID]*tournamentRow{},
+		participants:     map[uuid.UUID][]tournamentModel.Participant{},
+		pairings:         map[uuid.UUID]*tournamentModel.Pairing{},
+		seasons:          map[uuid.UUID]*seasonModel.Season{},
+		standings:        map[uuid.UUID][]gameModel.UserLeaders{},
+		sanctions:        map[sanctionKey]moderationModel.Sanction{},
+		presence:         map[uuid.UUID]time.Time{},
+		webhooks:         map[uuid.UUID]*webhookRow{},
+		idempotency:      map[idempotencyKey]*idempotencyModel.Record{},
+	}}
+}
+
+// txKey отмечает контекст транзакции хранилища (см. txManager.WithinTx)
+type txKey struct{}
+
+// lock захватывает хранилище на время вызова репозитория. Внутри транзакции
+// хранилище уже захвачено ею, и вызов выполняется без ожидания
+func (s *Store) lock(ctx context.Context) (unlock func()) {
+	if tx, _ := ctx.Value(txKey{}).(*Store); tx == s {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// snapshot копирует все таблицы, чтобы изменения живых записей не попали
+// в снимок; вызывается под s.mu
+func (t *tables) snapshot() tables {
+	c := *t
+	c.games = cloneRows(t.games, func(row gameRow) gameRow {
+		row.game = copyGame(row.game)
+		return row
+	})
+	c.outbox = cloneList(t.outbox, func(row outboxRow) outboxRow {
+		row.done = slices.Clone(row.done)
+		return row
+	})
+	c.users = cloneRows(t.users, func(user userModel.User) userModel.User {
+		user.Roles = slices.Clone(user.Roles)
+		return user
+	})
+	c.tokens = cloneList(t.tokens, same)
+	c.loginAttempts = cloneRows(t.loginAttempts, same)
+	c.resetTokens = cloneRows(t.resetTokens, same)
+	c.twoFactor = cloneRows(t.twoFactor, same)
+	c.recoveryCodes = cloneSets(t.recoveryCodes)
+	c.usedChallenges = maps.Clone(t.usedChallenges)
+	c.identities = maps.Clone(t.identities)
+	c.loginStates = maps.Clone(t.loginStates)
+	c.achievements = cloneSlices(t.achievements)
+	c.achievementStats = maps.Clone(t.achievementStats)
+	c.achievementGames = cloneSets(t.achievementGames)
+	c.tournaments = cloneRows(t.tournaments, same)
+	c.participants = cloneSlices(t.participants)
+	c.pairings = cloneRows(t.pairings, same)
+	c.seasons = cloneRows(t.seasons, same)
+	c.standings = cloneSlices(t.standings)
+	c.sanctions = maps.Clone(t.sanctions)
+	c.moderationLog = slices.Clone(t.moderationLog)
+	c.friendships = cloneList(t.friendships, same)
+	c.blocks = slices.Clone(t.blocks)
+	c.presence = maps.Clone(t.presence)
+	c.notifications = cloneList(t.notifications, same)
+	c.webhooks = cloneRows(t.webhooks, func(row webhookRow) webhookRow {
+		row.webhook.Events = slices.Clone(row.webhook.Events)
+		return row
+	})
+	c.deliveries = cloneList(t.deliveries, same)
+	c.idempotency = cloneRows(t.idempotency, same)
+	return c
+}
+
+// same - копия записи без изменяемых на месте срезов и карт: поля-указатели
+// репозитории не меняют, а заменяют
+func same[T any](v T) T {
+	return v
+}
+
+func cloneRows[K comparable, V any](rows map[K]*V, clone func(V) V) map[K]*V {
+	c := make(map[K]*V, len(rows))
+	for k, row := range rows {
+		v := clone(*row)
+		c[k] = &v
+	}
+	return c
+}
+
+func cloneList[V any](rows []*V, clone func(V) V) []*V {
+	c := make([]*V, 0, len(rows))
+	for _, row := range rows {
+		v := clone(*row)
+		c = append(c, &v)
+	}
+	return c
+}
+
+func cloneSlices[K comparable, V any](rows map[K][]V) map[K][]V {
+	c := make(map[K][]V, len(rows))
+	for k, items := range rows {
+		c[k] = slices.Clone(items)
+	}
+	return c
+}
+
+func cloneSets[K, V comparable](sets map[K]map[V]bool) map[K]map[V]bool {
+	c := make(map[K]map[V]bool, len(sets))
+	for k, set := range sets {
+		c[k] = maps.Clone(set)
+	}
+	return c
+}
+
+// next возвращает следующий номер изменения; вызывается под s.mu
+func (s *Store) next() int64 {
+	s.seq++
+	return s.seq
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+func copyUUID(id *uuid.UUID) *uuid.UUID {
+	if id == nil {
+		return nil
+	}
+	c := *id
+	return &c
+}
+
+// sortByTimeDesc сортирует записи от новых к старым, сохраняя порядок равных
+func sortByTimeDesc[T any](items []T, at func(T) time.Time) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		return at(b).Compare(at(a))
+	})
+}
+
+// limitRows оставляет первые limit записей (LIMIT в SQL)
+func limitRows[T any](items []T, limit int) []T {
+	if limit >= 0 && len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
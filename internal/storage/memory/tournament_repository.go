@@ -0,0 +1,212 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	model "tic-tac-toe/internal/domain/model/tournament"
+
+	"github.com/google/uuid"
+)
+
+type tournamentRow struct {
+	tournament model.Tournament
+	// порядок создания при одинаковом created_at
+	created int64
+}
+
+type tournamentRepository struct {
+	store *Store
+}
+
+func NewTournamentRepository(store *Store) model.TournamentRepository {
+	return &tournamentRepository{
+		store: store,
+	}
+}
+
+func (r *tournamentRepository) CreateTournament(ctx context.Context, t model.Tournament) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if _, ok := s.tournaments[t.UUID]; ok {
+		return fmt.Errorf("ошибка создания турнира: турнир %s уже существует", t.UUID)
+	}
+	s.tournaments[t.UUID] = &tournamentRow{tournament: t, created: s.next()}
+	return nil
+}
+
+func (r *tournamentRepository) GetTournament(ctx context.Context, id uuid.UUID) (model.Tournament, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	row, ok := s.tournaments[id]
+	if !ok {
+		return model.Tournament{}, fmt.Errorf("tournament not found: %w", errNotFound)
+	}
+	return row.tournament, nil
+}
+
+func (r *tournamentRepository) ListTournaments(ctx context.Context) ([]model.Tournament, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var rows []*tournamentRow
+	for _, row := range s.tournaments {
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b *tournamentRow) int {
+		return cmp.Or(b.tournament.CreatedAt.Compare(a.tournament.CreatedAt), cmp.Compare(b.created, a.created))
+	})
+
+	var tournaments []model.Tournament
+	for _, row := range rows {
+		tournaments = append(tournaments, row.tournament)
+	}
+	return tournaments, nil
+}
+
+func (r *tournamentRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status model.Status) error {
+	r.update(ctx, id, func(t *model.Tournament) bool {
+		t.Status = status
+		return true
+	})
+	return nil
+}
+
+func (r *tournamentRepository) UpdateRounds(ctx context.Context, id uuid.UUID, rounds int) error {
+	r.update(ctx, id, func(t *model.Tournament) bool {
+		t.Rounds = rounds
+		return true
+	})
+	return nil
+}
+
+func (r *tournamentRepository) AdvanceRound(ctx context.Context, id uuid.UUID, from, to int) (bool, error) {
+	return r.update(ctx, id, func(t *model.Tournament) bool {
+		if t.CurrentRound != from {
+			return false
+		}
+		t.CurrentRound = to
+		return true
+	}), nil
+}
+
+func (r *tournamentRepository) AddParticipant(ctx context.Context, p model.Participant) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if _, ok := s.tournaments[p.TournamentID]; !ok {
+		return fmt.Errorf("ошибка добавления участника: турнир %s не найден", p.TournamentID)
+	}
+	participants := s.participants[p.TournamentID]
+	if slices.ContainsFunc(participants, func(existing model.Participant) bool { return existing.UserID == p.UserID }) {
+		return fmt.Errorf("ошибка добавления участника: %s уже участвует", p.UserID)
+	}
+	s.participants[p.TournamentID] = append(participants, p)
+	return nil
+}
+
+func (r *tournamentRepository) GetParticipants(ctx context.Context, tournamentID uuid.UUID) ([]model.Participant, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	participants := slices.Clone(s.participants[tournamentID])
+	slices.SortStableFunc(participants, func(a, b model.Participant) int {
+		return cmp.Compare(a.Seed, b.Seed)
+	})
+	return participants, nil
+}
+
+func (r *tournamentRepository) SavePairings(ctx context.Context, pairings []model.Pairing) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	// пары сохраняются целиком или не сохраняются вовсе, как пакет в Postgres
+	type board struct {
+		tournamentID uuid.UUID
+		round, board int
+	}
+	taken := map[board]bool{}
+	for _, p := range s.pairings {
+		taken[board{p.TournamentID, p.Round, p.Board}] = true
+	}
+	ids := map[uuid.UUID]bool{}
+	for _, p := range pairings {
+		key := board{p.TournamentID, p.Round, p.Board}
+		if _, ok := s.pairings[p.UUID]; ok || ids[p.UUID] || taken[key] {
+			return fmt.Errorf("ошибка сохранения пар: пара %s уже существует", p.UUID)
+		}
+		ids[p.UUID] = true
+		taken[key] = true
+	}
+	for _, p := range pairings {
+		stored := copyPairing(p)
+		s.pairings[p.UUID] = &stored
+	}
+	return nil
+}
+
+func (r *tournamentRepository) GetPairings(ctx context.Context, tournamentID uuid.UUID) ([]model.Pairing, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var pairings []model.Pairing
+	for _, p := range s.pairings {
+		if p.TournamentID == tournamentID {
+			pairings = append(pairings, copyPairing(*p))
+		}
+	}
+	slices.SortFunc(pairings, func(a, b model.Pairing) int {
+		return cmp.Or(cmp.Compare(a.Round, b.Round), cmp.Compare(a.Board, b.Board))
+	})
+	return pairings, nil
+}
+
+func (r *tournamentRepository) FindPairingByGame(ctx context.Context, gameID uuid.UUID) (*model.Pairing, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	for _, p := range s.pairings {
+		if p.GameID != nil && *p.GameID == gameID {
+			c := copyPairing(*p)
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *tournamentRepository) UpdatePairing(ctx context.Context, p model.Pairing) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	stored, ok := s.pairings[p.UUID]
+	if !ok {
+		return nil
+	}
+	stored.PlayerX = p.PlayerX
+	stored.PlayerO = copyUUID(p.PlayerO)
+	stored.GameID = copyUUID(p.GameID)
+	stored.Result = p.Result
+	return nil
+}
+
+// update изменяет турнир, если он есть; fn сообщает, было ли изменение
+func (r *tournamentRepository) update(ctx context.Context, id uuid.UUID, fn func(t *model.Tournament) bool) bool {
+	s := r.store
+	defer s.lock(ctx)()
+
+	row, ok := s.tournaments[id]
+	if !ok {
+		return false
+	}
+	return fn(&row.tournament)
+}
+
+func copyPairing(p model.Pairing) model.Pairing {
+	p.PlayerO = copyUUID(p.PlayerO)
+	p.GameID = copyUUID(p.GameID)
+	return p
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/auth"
+
+	"github.com/google/uuid"
+)
+
+type twoFactorRepository struct {
+	store *Store
+}
+
+func NewTwoFactorRepository(store *Store) model.TwoFactorRepository {
+	return &twoFactorRepository{
+		store: store,
+	}
+}
+
+func (r *twoFactorRepository) Get(ctx context.Context, userID uuid.UUID) (*model.TwoFactor, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	tf, ok := s.twoFactor[userID]
+	if !ok {
+		return nil, nil
+	}
+	c := *tf
+	c.EnabledAt = copyTime(tf.EnabledAt)
+	return &c, nil
+}
+
+func (r *twoFactorRepository) SaveSecret(ctx context.Context, userID uuid.UUID, secret string) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	// включённую 2FA перезаписать нельзя - только отключить
+	tf, ok := s.twoFactor[userID]
+	if !ok {
+		s.twoFactor[userID] = &model.TwoFactor{UserID: userID, Secret: secret}
+		return true, nil
+	}
+	if tf.Enabled() {
+		return false, nil
+	}
+	tf.Secret = secret
+	return true, nil
+}
+
+func (r *twoFactorRepository) Enable(ctx context.Context, userID uuid.UUID, step int64, recoveryHashes []string) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if tf, ok := s.twoFactor[userID]; ok {
+		now := time.Now()
+		tf.EnabledAt = &now
+		tf.LastUsedStep = step
+	}
+	codes := make(map[string]bool, len(recoveryHashes))
+	for _, hash := range recoveryHashes {
+		codes[hash] = false
+	}
+	s.recoveryCodes[userID] = codes
+	return nil
+}
+
+func (r *twoFactorRepository) Disable(ctx context.Context, userID uuid.UUID) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	delete(s.recoveryCodes, userID)
+	delete(s.twoFactor, userID)
+	return nil
+}
+
+func (r *twoFactorRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	tf, ok := s.twoFactor[userID]
+	if !ok || tf.LastUsedStep >= step {
+		return false, nil
+	}
+	tf.LastUsedStep = step
+	return true, nil
+}
+
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	used, ok := s.recoveryCodes[userID][hash]
+	if !ok || used {
+		return false, nil
+	}
+	s.recoveryCodes[userID][hash] = true
+	return true, nil
+}
+
+func (r *twoFactorRepository) UseChallenge(ctx context.Context, userID uuid.UUID, jti string, expiresAt time.Time) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	// истёкший токен уже не предъявить, его запись не нужна
+	now := time.Now()
+	for id, until := range s.usedChallenges {
+		if until.Before(now) {
+			delete(s.usedChallenges, id)
+		}
+	}
+	if _, ok := s.usedChallenges[jti]; ok {
+		return false, nil
+	}
+	s.usedChallenges[jti] = expiresAt
+	return true, nil
+}
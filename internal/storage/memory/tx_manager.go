@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"context"
+
+	model "tic-tac-toe/internal/domain/model/transaction"
+)
+
+// txManager выполняет транзакцию, захватив всё хранилище: остальные вызовы ждут
+// её завершения. Перед fn снимается копия таблиц, и при ошибке она возвращается
+// на место - изменения fn откатываются целиком
+type txManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) model.Manager {
+	return &txManager{
+		store: store,
+	}
+}
+
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	s := m.store
+	defer s.lock(ctx)()
+
+	// вложенная транзакция откатывает только свои изменения, как точка сохранения в Postgres
+	saved := s.tables.snapshot()
+	if err := fn(context.WithValue(ctx, txKey{}, s)); err != nil {
+		s.tables = saved
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	model "tic-tac-toe/internal/domain/model/user"
+
+	"github.com/google/uuid"
+)
+
+type userRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) model.UserRepository {
+	return &userRepository{
+		store: store,
+	}
+}
+
+func (r *userRepository) CreateUser(ctx context.Context, user model.User) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if _, ok := s.users[user.UUID]; ok {
+		return fmt.Errorf("Ошибка создания пользователя: пользователь %s уже существует", user.UUID)
+	}
+	if s.userByLogin(user.Login) != nil {
+		return fmt.Errorf("Ошибка создания пользователя: логин %q уже занят", user.Login)
+	}
+	// роли выдаются отдельно, как и в Postgres
+	s.users[user.UUID] = &model.User{
+		UUID:     user.UUID,
+		Login:    user.Login,
+		Password: user.Password,
+		Roles:    []model.Role{},
+	}
+	return nil
+}
+
+func (r *userRepository) GetUserByID(ctx context.Context, id uuid.UUID) (model.User, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	user, ok := s.users[id]
+	if !ok {
+		return model.User{}, model.ErrUserNotFound
+	}
+	// хэш пароля по id не отдаётся
+	return model.User{
+		UUID:  user.UUID,
+		Login: user.Login,
+		Roles: slices.Clone(user.Roles),
+	}, nil
+}
+
+func (r *userRepository) GetUserByLogin(ctx context.Context, login string) (*model.User, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	user := s.userByLogin(login)
+	if user == nil {
+		return nil, model.ErrUserNotFound
+	}
+	c := *user
+	c.Roles = slices.Clone(user.Roles)
+	return &c, nil
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	return r.update(ctx, id, func(user *model.User) {
+		user.Password = passwordHash
+	})
+}
+
+func (r *userRepository) AddRole(ctx context.Context, id uuid.UUID, role model.Role) error {
+	return r.update(ctx, id, func(user *model.User) {
+		if !user.HasRole(role) {
+			user.Roles = append(user.Roles, role)
+		}
+	})
+}
+
+func (r *userRepository) RemoveRole(ctx context.Context, id uuid.UUID, role model.Role) error {
+	return r.update(ctx, id, func(user *model.User) {
+		user.Roles = slices.DeleteFunc(user.Roles, func(r model.Role) bool {
+			return r == role
+		})
+	})
+}
+
+func (r *userRepository) update(ctx context.Context, id uuid.UUID, fn func(user *model.User)) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	user, ok := s.users[id]
+	if !ok {
+		return model.ErrUserNotFound
+	}
+	fn(user)
+	return nil
+}
+
+// userByLogin вызывается под s.mu
+func (s *Store) userByLogin(login string) *model.User {
+	for _, user := range s.users {
+		if user.Login == login {
+			return user
+		}
+	}
+	return nil
+}
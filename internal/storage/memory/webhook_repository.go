@@ -0,0 +1,214 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/webhook"
+
+	"github.com/google/uuid"
+)
+
+type webhookRow struct {
+	webhook model.Webhook
+	// порядок создания при одинаковом created_at
+	created int64
+}
+
+type webhookRepository struct {
+	store *Store
+}
+
+func NewWebhookRepository(store *Store) model.WebhookRepository {
+	return &webhookRepository{
+		store: store,
+	}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, w model.Webhook) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	if _, ok := s.webhooks[w.UUID]; ok {
+		return fmt.Errorf("ошибка сохранения вебхука: вебхук %s уже существует", w.UUID)
+	}
+	w.Events = slices.Clone(w.Events)
+	s.webhooks[w.UUID] = &webhookRow{webhook: w, created: s.next()}
+	return nil
+}
+
+func (r *webhookRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]model.Webhook, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var rows []*webhookRow
+	for _, row := range s.webhooks {
+		if row.webhook.OwnerID == ownerID {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b *webhookRow) int {
+		return cmp.Or(a.webhook.CreatedAt.Compare(b.webhook.CreatedAt), cmp.Compare(a.created, b.created))
+	})
+
+	var webhooks []model.Webhook
+	for _, row := range rows {
+		// ключ подписи показывается только при создании
+		w := row.webhook
+		w.Secret = ""
+		w.Events = slices.Clone(w.Events)
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepository) CountByOwner(ctx context.Context, ownerID uuid.UUID) (int, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	count := 0
+	for _, row := range s.webhooks {
+		if row.webhook.OwnerID == ownerID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id, ownerID uuid.UUID) (bool, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	row, ok := s.webhooks[id]
+	if !ok || row.webhook.OwnerID != ownerID {
+		return false, nil
+	}
+	delete(s.webhooks, id)
+	s.deliveries = slices.DeleteFunc(s.deliveries, func(d *model.Delivery) bool {
+		return d.WebhookID == id
+	})
+	return true, nil
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, id, ownerID uuid.UUID, limit int) ([]model.Delivery, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	row, ok := s.webhooks[id]
+	if !ok || row.webhook.OwnerID != ownerID {
+		return nil, nil
+	}
+	var deliveries []model.Delivery
+	for _, d := range s.deliveries {
+		if d.WebhookID == id {
+			deliveries = append(deliveries, copyDelivery(*d))
+		}
+	}
+	sortByTimeDesc(deliveries, func(d model.Delivery) time.Time { return d.CreatedAt })
+	return limitRows(deliveries, limit), nil
+}
+
+func (r *webhookRepository) Enqueue(ctx context.Context, event gameModel.Event, now time.Time) error {
+	payload, err := marshalEvent(event)
+	if err != nil {
+		return err
+	}
+
+	s := r.store
+	defer s.lock(ctx)()
+
+	players := event.Players()
+	for id, row := range s.webhooks {
+		w := row.webhook
+		if !w.Subscribed(event.Type) || !w.AllGames && !slices.Contains(players, w.OwnerID) {
+			continue
+		}
+		if slices.ContainsFunc(s.deliveries, func(d *model.Delivery) bool {
+			return d.WebhookID == id && d.EventID == event.ID
+		}) {
+			continue
+		}
+		s.deliveries = append(s.deliveries, &model.Delivery{
+			UUID:          uuid.New(),
+			WebhookID:     id,
+			EventID:       event.ID,
+			Event:         event.Type,
+			Payload:       payload,
+			Status:        model.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	return nil
+}
+
+func (r *webhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.Delivery, error) {
+	s := r.store
+	defer s.lock(ctx)()
+
+	var due []*model.Delivery
+	for _, d := range s.deliveries {
+		if d.Status == model.DeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *model.Delivery) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+
+	var deliveries []model.Delivery
+	for _, d := range limitRows(due, limit) {
+		d.NextAttemptAt = now.Add(lease)
+		webhook := s.webhooks[d.WebhookID].webhook
+		deliveries = append(deliveries, model.Delivery{
+			UUID:      d.UUID,
+			WebhookID: d.WebhookID,
+			EventID:   d.EventID,
+			Event:     d.Event,
+			Payload:   slices.Clone(d.Payload),
+			Status:    model.DeliveryPending,
+			Attempts:  d.Attempts,
+			CreatedAt: d.CreatedAt,
+			URL:       webhook.URL,
+			Secret:    webhook.Secret,
+		})
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepository) SaveAttempt(ctx context.Context, d model.Delivery) error {
+	s := r.store
+	defer s.lock(ctx)()
+
+	i := slices.IndexFunc(s.deliveries, func(existing *model.Delivery) bool { return existing.UUID == d.UUID })
+	if i < 0 {
+		return nil
+	}
+	stored := s.deliveries[i]
+	stored.Status = d.Status
+	stored.Attempts = d.Attempts
+	stored.LastError = d.LastError
+	stored.NextAttemptAt = d.NextAttemptAt
+	if d.ResponseCode != nil {
+		code := *d.ResponseCode
+		stored.ResponseCode = &code
+	} else {
+		stored.ResponseCode = nil
+	}
+	stored.DeliveredAt = copyTime(d.DeliveredAt)
+	return nil
+}
+
+func copyDelivery(d model.Delivery) model.Delivery {
+	d.Payload = slices.Clone(d.Payload)
+	if d.ResponseCode != nil {
+		code := *d.ResponseCode
+		d.ResponseCode = &code
+	}
+	d.DeliveredAt = copyTime(d.DeliveredAt)
+	return d
+}
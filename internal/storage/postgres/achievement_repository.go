@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	model "tic-tac-toe/internal/domain/model/achievement"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type achievementRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAchievementRepository(pool *pgxpool.Pool) model.AchievementRepository {
+	return &achievementRepository{
+		pool: pool,
+	}
+}
+
+func (r *achievementRepository) Grant(ctx context.Context, userID uuid.UUID, code model.Code) (bool, error) {
+	query := `INSERT INTO user_achievements (user_id, code)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, code) DO NOTHING`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, userID, code)
+	if err != nil {
+		return false, fmt.Errorf("ошибка выдачи достижения: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *achievementRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]model.UserAchievement, error) {
+	query := `SELECT code, unlocked_at
+		FROM user_achievements
+		WHERE user_id = $1
+		ORDER BY unlocked_at`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения достижений: %w", err)
+	}
+	defer rows.Close()
+
+	var achievements []model.UserAchievement
+	for rows.Next() {
+		var (
+			code       model.Code
+			unlockedAt time.Time
+		)
+		if err := rows.Scan(&code, &unlockedAt); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		achievements = append(achievements, model.UserAchievement{
+			UserID:     userID,
+			Code:       code,
+			UnlockedAt: unlockedAt,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %w", err)
+	}
+	return achievements, nil
+}
+
+func (r *achievementRepository) LockStats(ctx context.Context, userID uuid.UUID) (*model.PlayerStats, error) {
+	// строка создаётся заранее, чтобы одновременное заведение счётчиков ждало на блокировке
+	_, err := conn(ctx, r.pool).Exec(ctx, `INSERT INTO achievement_stats (user_id)
+		VALUES ($1)
+		ON CONFLICT (user_id) DO NOTHING`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания счётчиков достижений: %w", err)
+	}
+
+	query := `SELECT initialized, games_played, wins, draws, bot_wins, win_streak, fastest_win
+		FROM achievement_stats
+		WHERE user_id = $1
+		FOR UPDATE`
+
+	var (
+		initialized bool
+		stats       model.PlayerStats
+	)
+	err = conn(ctx, r.pool).QueryRow(ctx, query, userID).Scan(&initialized, &stats.GamesPlayed, &stats.Wins,
+		&stats.Draws, &stats.BotWins, &stats.CurrentWinStreak, &stats.FastestWin)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения счётчиков достижений: %w", err)
+	}
+	if !initialized {
+		return nil, nil
+	}
+	return &stats, nil
+}
+
+func (r *achievementRepository) SaveStats(ctx context.Context, userID uuid.UUID, stats model.PlayerStats) error {
+	query := `INSERT INTO achievement_stats (user_id, initialized, games_played, wins, draws, bot_wins, win_streak, fastest_win, updated_at)
+		VALUES ($1, TRUE, $2, $3, $4, $5, $6, $7, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET initialized = TRUE,
+			games_played = EXCLUDED.games_played,
+			wins = EXCLUDED.wins,
+			draws = EXCLUDED.draws,
+			bot_wins = EXCLUDED.bot_wins,
+			win_streak = EXCLUDED.win_streak,
+			fastest_win = EXCLUDED.fastest_win,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := conn(ctx, r.pool).Exec(ctx, query, userID, stats.GamesPlayed, stats.Wins, stats.Draws,
+		stats.BotWins, stats.CurrentWinStreak, stats.FastestWin)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения счётчиков достижений: %w", err)
+	}
+	return nil
+}
+
+func (r *achievementRepository) CountGames(ctx context.Context, userID uuid.UUID, gameIDs []uuid.UUID) ([]uuid.UUID, error) {
+	query := `INSERT INTO achievement_games (user_id, game_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT (user_id, game_id) DO NOTHING
+		RETURNING game_id`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID, gameIDs)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка учёта игр в счётчиках: %w", err)
+	}
+	defer rows.Close()
+
+	var counted []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		counted = append(counted, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %w", err)
+	}
+	return counted, nil
+}
@@ -0,0 +1,24 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"tic-tac-toe/internal/storage/contract"
+	"tic-tac-toe/internal/storage/postgres"
+)
+
+func newRepositories(t *testing.T) contract.Repositories {
+	pool := contract.PostgresPool(t)
+	return contract.Repositories{
+		Games:    postgres.NewGameRepository(pool),
+		Users:    postgres.NewUserRepository(pool),
+		Tokens:   postgres.NewTokenRepository(pool),
+		Seasons:  postgres.NewSeasonRepository(pool),
+		Presence: postgres.NewPresenceRepository(pool),
+		Tx:       postgres.NewTxManager(pool),
+	}
+}
+
+func TestContract(t *testing.T) {
+	contract.Run(t, newRepositories)
+}
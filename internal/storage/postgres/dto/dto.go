@@ -29,9 +29,30 @@ type UserDTO struct {
 	Password string    `db:"password"`
 }
 
+// refresh токены одной сессии образуют семейство: при ротации старый токен
+// помечается RotatedAt, новый наследует FamilyID
 type RefreshToken struct {
-	TokenHash string    `db:"token_hash"`
-	UserID    uuid.UUID `db:"uuid"`
-	ExpiresAt time.Time `db:"expires_at"`
-	CreatedAt time.Time `db:"created_at"`
+	ID         uuid.UUID  `db:"id"`
+	TokenHash  string     `db:"token_hash"`
+	UserID     uuid.UUID  `db:"uuid"`
+	FamilyID   uuid.UUID  `db:"family_id"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RotatedAt  *time.Time `db:"rotated_at"`
+	UserAgent  string     `db:"user_agent"`
+	IP         string     `db:"ip"`
+}
+
+// событие игры в outbox (JSON, поле data тела вебхука)
+type GameEventDTO struct {
+	GameID     uuid.UUID        `json:"game_id"`
+	PlayerID   *uuid.UUID       `json:"player_id"`
+	PlayerX    uuid.UUID        `json:"player_x"`
+	PlayerO    *uuid.UUID       `json:"player_o"`
+	Status     model.GameStatus `json:"status"`
+	Field      [][]int          `json:"field"`
+	Winner     *uuid.UUID       `json:"winner"`
+	SeasonID   *uuid.UUID       `json:"season_id,omitempty"`
+	OccurredAt time.Time        `json:"occurred_at"`
 }
@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	model "tic-tac-toe/internal/domain/model/user"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type friendRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewFriendRepository(pool *pgxpool.Pool) model.FriendRepository {
+	return &friendRepository{
+		pool: pool,
+	}
+}
+
+func (r *friendRepository) GetFriendship(ctx context.Context, a, b uuid.UUID) (*model.Friendship, error) {
+	query := `SELECT requester_id, addressee_id, status, created_at, accepted_at
+		FROM friendships
+		WHERE (requester_id = $1 AND addressee_id = $2) OR (requester_id = $2 AND addressee_id = $1)`
+
+	var (
+		f      model.Friendship
+		status string
+	)
+	err := conn(ctx, r.pool).QueryRow(ctx, query, a, b).Scan(&f.RequesterID, &f.AddresseeID, &status, &f.CreatedAt, &f.AcceptedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка получения заявки в друзья: %w", err)
+	}
+	f.Status = model.FriendshipStatus(status)
+	return &f, nil
+}
+
+func (r *friendRepository) CreateRequest(ctx context.Context, requester, addressee uuid.UUID) (bool, error) {
+	// конфликт и по первичному ключу, и по индексу пары (встречная заявка)
+	query := `INSERT INTO friendships (requester_id, addressee_id, status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, requester, addressee, string(model.FriendshipPending))
+	if err != nil {
+		return false, fmt.Errorf("ошибка создания заявки в друзья: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *friendRepository) Accept(ctx context.Context, requester, addressee uuid.UUID) (bool, error) {
+	query := `UPDATE friendships SET status = $3, accepted_at = NOW()
+		WHERE requester_id = $1 AND addressee_id = $2 AND status = $4`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, requester, addressee,
+		string(model.FriendshipAccepted), string(model.FriendshipPending))
+	if err != nil {
+		return false, fmt.Errorf("ошибка принятия заявки в друзья: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *friendRepository) DeleteFriendship(ctx context.Context, a, b uuid.UUID) (bool, error) {
+	query := `DELETE FROM friendships
+		WHERE (requester_id = $1 AND addressee_id = $2) OR (requester_id = $2 AND addressee_id = $1)`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, a, b)
+	if err != nil {
+		return false, fmt.Errorf("ошибка удаления из друзей: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *friendRepository) ListFriends(ctx context.Context, userID uuid.UUID) ([]model.Friend, error) {
+	query := `SELECT u.uuid, u.login, f.accepted_at
+		FROM friendships f
+		JOIN users u ON u.uuid = CASE WHEN f.requester_id = $1 THEN f.addressee_id ELSE f.requester_id END
+		WHERE (f.requester_id = $1 OR f.addressee_id = $1) AND f.status = $2
+		ORDER BY u.login`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID, string(model.FriendshipAccepted))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения друзей: %w", err)
+	}
+	defer rows.Close()
+
+	var friends []model.Friend
+	for rows.Next() {
+		var f model.Friend
+		if err := rows.Scan(&f.UserID, &f.Login, &f.Since); err != nil {
+			return nil, fmt.Errorf("ошибка чтения друзей: %w", err)
+		}
+		friends = append(friends, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения друзей: %w", err)
+	}
+	return friends, nil
+}
+
+func (r *friendRepository) ListRequests(ctx context.Context, userID uuid.UUID) ([]model.FriendRequest, error) {
+	query := `SELECT u.uuid, u.login, f.addressee_id = $1, f.created_at
+		FROM friendships f
+		JOIN users u ON u.uuid = CASE WHEN f.requester_id = $1 THEN f.addressee_id ELSE f.requester_id END
+		WHERE (f.requester_id = $1 OR f.addressee_id = $1) AND f.status = $2
+		ORDER BY f.created_at DESC`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID, string(model.FriendshipPending))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения заявок в друзья: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []model.FriendRequest
+	for rows.Next() {
+		var req model.FriendRequest
+		if err := rows.Scan(&req.UserID, &req.Login, &req.Incoming, &req.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения заявок в друзья: %w", err)
+		}
+		requests = append(requests, req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения заявок в друзья: %w", err)
+	}
+	return requests, nil
+}
+
+func (r *friendRepository) FriendIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	query := `SELECT CASE WHEN requester_id = $1 THEN addressee_id ELSE requester_id END
+		FROM friendships
+		WHERE (requester_id = $1 OR addressee_id = $1) AND status = $2`
+
+	return r.queryIDs(ctx, query, userID, string(model.FriendshipAccepted))
+}
+
+func (r *friendRepository) Block(ctx context.Context, blocker, blocked uuid.UUID) (bool, error) {
+	tx, err := conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `INSERT INTO user_blocks (blocker_id, blocked_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, blocker, blocked)
+	if err != nil {
+		return false, fmt.Errorf("ошибка блокировки пользователя: %w", err)
+	}
+	// блокировка разрывает дружбу и отменяет заявки в обе стороны
+	_, err = tx.Exec(ctx, `DELETE FROM friendships
+		WHERE (requester_id = $1 AND addressee_id = $2) OR (requester_id = $2 AND addressee_id = $1)`, blocker, blocked)
+	if err != nil {
+		return false, fmt.Errorf("ошибка удаления из друзей: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *friendRepository) Unblock(ctx context.Context, blocker, blocked uuid.UUID) (bool, error) {
+	tag, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2`, blocker, blocked)
+	if err != nil {
+		return false, fmt.Errorf("ошибка разблокировки пользователя: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *friendRepository) ListBlocked(ctx context.Context, blocker uuid.UUID) ([]model.BlockedUser, error) {
+	query := `SELECT u.uuid, u.login, b.created_at
+		FROM user_blocks b
+		JOIN users u ON u.uuid = b.blocked_id
+		WHERE b.blocker_id = $1
+		ORDER BY b.created_at DESC`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, blocker)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения чёрного списка: %w", err)
+	}
+	defer rows.Close()
+
+	var blocked []model.BlockedUser
+	for rows.Next() {
+		var b model.BlockedUser
+		if err := rows.Scan(&b.UserID, &b.Login, &b.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения чёрного списка: %w", err)
+		}
+		blocked = append(blocked, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения чёрного списка: %w", err)
+	}
+	return blocked, nil
+}
+
+func (r *friendRepository) BlockedIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	query := `SELECT blocked_id FROM user_blocks WHERE blocker_id = $1
+		UNION
+		SELECT blocker_id FROM user_blocks WHERE blocked_id = $1`
+
+	return r.queryIDs(ctx, query, userID)
+}
+
+func (r *friendRepository) IsBlockedBetween(ctx context.Context, a, b uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (
+		SELECT 1 FROM user_blocks
+		WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1))`
+
+	var blocked bool
+	if err := conn(ctx, r.pool).QueryRow(ctx, query, a, b).Scan(&blocked); err != nil {
+		return false, fmt.Errorf("ошибка проверки блокировки: %w", err)
+	}
+	return blocked, nil
+}
+
+func (r *friendRepository) queryIDs(ctx context.Context, query string, args ...any) ([]uuid.UUID, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения пользователей: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("ошибка чтения пользователей: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения пользователей: %w", err)
+	}
+	return ids, nil
+}
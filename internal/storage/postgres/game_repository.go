@@ -23,16 +23,22 @@ func NewGameRepository(pool *pgxpool.Pool) model.GameRepository {
 	}
 }
 
-func (r *gameRepositoryDB) SaveGame(ctx context.Context, game model.Game) error {
-	query := `INSERT INTO games(uuid, field, status, player_x, player_o, current_turn, symbols, created_at) 
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
-	ON CONFLICT (uuid)
-	DO UPDATE SET field = $2, status = $3,
+func (r *gameRepositoryDB) SaveGame(ctx context.Context, game model.Game, events ...model.Event) error {
+	// новая игра вставляется, сохранённая - обновляется, только если её версия не изменилась
+	query := `INSERT INTO games(uuid, field, status, player_x, player_o, current_turn, symbols, created_at, season_id) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
+	ON CONFLICT (uuid) DO NOTHING`
+	if game.Version > 0 {
+		query = `UPDATE games SET field = $2, status = $3,
 			player_o = $5,
 			current_turn = $6,
-			symbols = $7, 
+			symbols = $7,
 			created_at = $8,
-			updated_at = NOW()`
+			season_id = COALESCE($9, games.season_id),
+			version = version + 1,
+			updated_at = NOW()
+		WHERE uuid = $1 AND version = $10`
+	}
 
 	// Сериализуем поле в JSON
 	fieldJSON, err := json.Marshal(game.Field.Field)
@@ -45,16 +51,37 @@ func (r *gameRepositoryDB) SaveGame(ctx context.Context, game model.Game) error
 		return fmt.Errorf("ошибка сериализации поля: %w", err)
 	}
 
-	_, err = r.pool.Exec(ctx, query, game.UUID, fieldJSON, game.Status,
-		game.PlayerX, game.PlayerO, game.CurrentTurn, symbolJSON, game.DateCreate)
+	tx, err := conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	args := []any{game.UUID, fieldJSON, game.Status,
+		game.PlayerX, game.PlayerO, game.CurrentTurn, symbolJSON, game.DateCreate, game.SeasonID}
+	if game.Version > 0 {
+		args = append(args, game.Version)
+	}
+	tag, err := tx.Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("ошибка сохранения игры: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return &model.ConflictError{GameID: game.UUID, Version: game.Version}
+	}
+	for _, event := range events {
+		if err := insertOutboxEvent(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
 	return nil
 }
 
 func (r *gameRepositoryDB) GetCurrentGame(ctx context.Context, id uuid.UUID) (model.Game, error) {
-	query := `SELECT uuid, field, status, player_x, player_o, current_turn, symbols, created_at 
+	query := `SELECT uuid, field, status, player_x, player_o, current_turn, symbols, created_at, version 
 	FROM games 
 	WHERE uuid = $1`
 
@@ -67,9 +94,10 @@ func (r *gameRepositoryDB) GetCurrentGame(ctx context.Context, id uuid.UUID) (mo
 		currentTurn uuid.UUID
 		symbolJSON  []byte
 		dateCreate  time.Time
+		version     int
 	)
 
-	err := r.pool.QueryRow(ctx, query, id).Scan(&gameUUID, &fieldJSON, &status, &playerX, &playerO, &currentTurn, &symbolJSON, &dateCreate)
+	err := conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(&gameUUID, &fieldJSON, &status, &playerX, &playerO, &currentTurn, &symbolJSON, &dateCreate, &version)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return model.Game{}, fmt.Errorf("game not found: %w", err)
@@ -96,6 +124,7 @@ func (r *gameRepositoryDB) GetCurrentGame(ctx context.Context, id uuid.UUID) (mo
 		CurrentTurn: currentTurn,
 		Symbols:     symbolData,
 		DateCreate:  dateCreate,
+		Version:     version,
 	}, nil
 }
 
@@ -104,7 +133,7 @@ func (r *gameRepositoryDB) GetAvailableGames(ctx context.Context) ([]model.Game,
 	FROM games 
 	WHERE status = $1 and player_o IS NULL`
 
-	rows, err := r.pool.Query(ctx, query, model.Waiting)
+	rows, err := conn(ctx, r.pool).Query(ctx, query, model.Waiting)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения игры: %w", err)
 	}
@@ -156,7 +185,7 @@ func (r *gameRepositoryDB) GetComplitedGames(ctx context.Context, userID uuid.UU
 	(status = 4 AND (player_x = $1 OR player_o = $1))
 	`
 
-	rows, err := r.pool.Query(ctx, query, userID)
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения игры: %w", err)
 	}
@@ -205,7 +234,8 @@ func (r *gameRepositoryDB) GetComplitedGames(ctx context.Context, userID uuid.UU
 	return games, nil
 }
 
-func (r *gameRepositoryDB) GetLeaderBoard(ctx context.Context, count int) ([]model.UserLeaders, error) {
+// таблица лидеров по играм сезона seasonID
+func (r *gameRepositoryDB) GetLeaderBoard(ctx context.Context, seasonID uuid.UUID, count int) ([]model.UserLeaders, error) {
 	query := `SELECT 
 	u.login,
     u.uuid,
@@ -219,11 +249,12 @@ func (r *gameRepositoryDB) GetLeaderBoard(ctx context.Context, count int) ([]mod
     	) AS win_rate
 	FROM users u
 	JOIN games g ON g.player_x = u.uuid OR g.player_o = u.uuid 
+	WHERE g.season_id = $2
 	GROUP BY u.uuid
 	ORDER BY win_rate DESC
 	LIMIT $1;`
 
-	rows, err := r.pool.Query(ctx, query, count)
+	rows, err := conn(ctx, r.pool).Query(ctx, query, count, seasonID)
 
 	if err != nil {
 		return []model.UserLeaders{}, fmt.Errorf("ошибка получения таблицы: %w", err)
@@ -253,3 +284,120 @@ func (r *gameRepositoryDB) GetLeaderBoard(ctx context.Context, count int) ([]mod
 
 	return leaders, nil
 }
+
+// завершённые игры между двумя игроками, от новых к старым
+func (r *gameRepositoryDB) GetGamesBetween(ctx context.Context, playerID, opponentID uuid.UUID) ([]model.Game, error) {
+	query := `SELECT uuid, field, status, player_x, player_o, current_turn, symbols, created_at
+	FROM games
+	WHERE status IN ($3, $4, $5)
+	AND (
+		(player_x = $1 AND player_o = $2)
+		OR
+		(player_x = $2 AND player_o = $1)
+	)
+	ORDER BY updated_at DESC, created_at DESC`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, playerID, opponentID, model.WonX, model.WonO, model.Draw)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения игр: %w", err)
+	}
+	return scanGames(rows)
+}
+
+// все завершённые игры пользователя (победы, поражения, ничьи), от новых к старым
+func (r *gameRepositoryDB) GetFinishedGamesByUser(ctx context.Context, userID uuid.UUID) ([]model.Game, error) {
+	query := `SELECT uuid, field, status, player_x, player_o, current_turn, symbols, created_at
+	FROM games
+	WHERE status IN ($2, $3, $4)
+	AND (player_x = $1 OR player_o = $1)
+	ORDER BY updated_at DESC, created_at DESC`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID, model.WonX, model.WonO, model.Draw)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения игр: %w", err)
+	}
+	return scanGames(rows)
+}
+
+// удаляет игру вместе со ссылками на неё из пар турнира
+func (r *gameRepositoryDB) DeleteGame(ctx context.Context, id uuid.UUID) (bool, error) {
+	tx, err := conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// у пар турнира нет внешнего ключа на игру - отвязываем вручную
+	if _, err := tx.Exec(ctx, `UPDATE tournament_pairings SET game_id = NULL WHERE game_id = $1`, id); err != nil {
+		return false, fmt.Errorf("ошибка отвязки игры от турнира: %w", err)
+	}
+	tag, err := tx.Exec(ctx, `DELETE FROM games WHERE uuid = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("ошибка удаления игры: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *gameRepositoryDB) CancelWaitingGames(ctx context.Context, playerX uuid.UUID) (int64, error) {
+	// турнирные игры не трогаем, иначе пара тура никогда не завершится
+	query := `UPDATE games SET status = $2, version = version + 1, updated_at = NOW()
+		WHERE player_x = $1 AND status = $3 AND player_o IS NULL
+			AND NOT EXISTS (SELECT 1 FROM tournament_pairings p WHERE p.game_id = games.uuid)`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, playerX, model.Cancelled, model.Waiting)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка отмены игр: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// сканирует строки с полным набором колонок игры
+func scanGames(rows pgx.Rows) ([]model.Game, error) {
+	defer rows.Close()
+	var games []model.Game
+
+	for rows.Next() {
+		var (
+			gameUUID    uuid.UUID
+			fieldJSON   []byte
+			status      model.GameStatus
+			playerX     uuid.UUID
+			playerO     *uuid.UUID
+			currentTurn uuid.UUID
+			symbolJSON  []byte
+			dateCreate  time.Time
+		)
+
+		if err := rows.Scan(&gameUUID, &fieldJSON, &status, &playerX, &playerO, &currentTurn, &symbolJSON, &dateCreate); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		var fieldData [][]int
+		if err := json.Unmarshal(fieldJSON, &fieldData); err != nil {
+			return nil, fmt.Errorf("ошибка десериализации поля: %w", err)
+		}
+		var symbolData map[uuid.UUID]model.Char
+		if err := json.Unmarshal(symbolJSON, &symbolData); err != nil {
+			return nil, fmt.Errorf("ошибка десериализации поля: %w", err)
+		}
+
+		games = append(games, model.Game{
+			UUID:        gameUUID,
+			Field:       &model.GameField{Field: fieldData},
+			Status:      status,
+			PlayerX:     playerX,
+			PlayerO:     playerO,
+			CurrentTurn: currentTurn,
+			Symbols:     symbolData,
+			DateCreate:  dateCreate,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %w", err)
+	}
+
+	return games, nil
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/idempotency"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type idempotencyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewIdempotencyRepository(pool *pgxpool.Pool) model.Repository {
+	return &idempotencyRepository{
+		pool: pool,
+	}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, record model.Record, now time.Time) (model.Record, bool, error) {
+	// истёкший ключ занимается заново, как новый
+	query := `INSERT INTO idempotency_keys (user_id, key, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, key) DO UPDATE
+			SET request_hash = EXCLUDED.request_hash, status_code = NULL, content_type = '', body = NULL,
+				created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+			WHERE idempotency_keys.expires_at <= EXCLUDED.created_at`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, record.UserID, record.Key, record.RequestHash, now, record.ExpiresAt)
+	if err != nil {
+		return model.Record{}, false, fmt.Errorf("ошибка сохранения ключа идемпотентности: %w", err)
+	}
+	if tag.RowsAffected() > 0 {
+		return record, true, nil
+	}
+
+	existing := model.Record{UserID: record.UserID, Key: record.Key}
+	var (
+		statusCode  *int
+		contentType string
+		body        []byte
+	)
+	err = conn(ctx, r.pool).QueryRow(ctx, `SELECT request_hash, status_code, content_type, body, expires_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2`, record.UserID, record.Key).
+		Scan(&existing.RequestHash, &statusCode, &contentType, &body, &existing.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// ключ освободили между запросами
+			return r.Reserve(ctx, record, now)
+		}
+		return model.Record{}, false, fmt.Errorf("ошибка получения ключа идемпотентности: %w", err)
+	}
+	if statusCode != nil {
+		existing.Response = &model.Response{StatusCode: *statusCode, ContentType: contentType, Body: body}
+	}
+	return existing, false, nil
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, userID uuid.UUID, key string, response model.Response) error {
+	query := `UPDATE idempotency_keys SET status_code = $3, content_type = $4, body = $5
+		WHERE user_id = $1 AND key = $2`
+
+	_, err := conn(ctx, r.pool).Exec(ctx, query, userID, key, response.StatusCode, response.ContentType, response.Body)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения ответа по ключу идемпотентности: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2 AND status_code IS NULL`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, userID, key); err != nil {
+		return fmt.Errorf("ошибка освобождения ключа идемпотентности: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка удаления ключей идемпотентности: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	model "tic-tac-toe/internal/domain/model/identity"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type identityRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewIdentityRepository(pool *pgxpool.Pool) model.IdentityRepository {
+	return &identityRepository{
+		pool: pool,
+	}
+}
+
+func (r *identityRepository) Find(ctx context.Context, issuer, subject string) (*model.Identity, error) {
+	query := `SELECT issuer, subject, user_id
+		FROM user_identities
+		WHERE issuer = $1 AND subject = $2`
+
+	var identity model.Identity
+	err := conn(ctx, r.pool).QueryRow(ctx, query, issuer, subject).Scan(&identity.Issuer, &identity.Subject, &identity.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка поиска внешней учётной записи: %w", err)
+	}
+	return &identity, nil
+}
+
+func (r *identityRepository) Create(ctx context.Context, identity model.Identity) (bool, error) {
+	query := `INSERT INTO user_identities (issuer, subject, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (issuer, subject) DO NOTHING`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, identity.Issuer, identity.Subject, identity.UserID)
+	if err != nil {
+		return false, fmt.Errorf("ошибка связывания внешней учётной записи: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+type loginStateRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLoginStateRepository(pool *pgxpool.Pool) model.LoginStateRepository {
+	return &loginStateRepository{
+		pool: pool,
+	}
+}
+
+func (r *loginStateRepository) Save(ctx context.Context, state model.LoginState) error {
+	// заодно удаляем брошенные входы
+	if _, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM oidc_login_states WHERE expires_at < NOW()`); err != nil {
+		return fmt.Errorf("ошибка очистки состояний OIDC: %w", err)
+	}
+
+	query := `INSERT INTO oidc_login_states (state, code_verifier, nonce, expires_at)
+		VALUES ($1, $2, $3, $4)`
+	_, err := conn(ctx, r.pool).Exec(ctx, query, state.State, state.CodeVerifier, state.Nonce, state.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения состояния OIDC: %w", err)
+	}
+	return nil
+}
+
+func (r *loginStateRepository) Consume(ctx context.Context, state string) (*model.LoginState, error) {
+	query := `DELETE FROM oidc_login_states
+		WHERE state = $1 AND expires_at > NOW()
+		RETURNING state, code_verifier, nonce, expires_at`
+
+	var s model.LoginState
+	err := conn(ctx, r.pool).QueryRow(ctx, query, state).Scan(&s.State, &s.CodeVerifier, &s.Nonce, &s.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка получения состояния OIDC: %w", err)
+	}
+	return &s, nil
+}
@@ -4,9 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
+
 	model "tic-tac-toe/internal/domain/model/auth"
 	dto "tic-tac-toe/internal/storage/postgres/dto"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -24,9 +25,9 @@ func NewTokenRepository(pool *pgxpool.Pool) model.TokenRepository {
 }
 
 func (t *tokenRepository) Save(ctx context.Context, token dto.RefreshToken) error {
-	query := `INSERT INTO refresh_tokens (token_hash, user_id, expires_at)
-		VALUES ($1,$2,$3)`
-	_, err := t.pool.Exec(ctx, query, token.TokenHash, token.UserID, token.ExpiresAt)
+	query := `INSERT INTO refresh_tokens (token_hash, user_id, family_id, expires_at, user_agent, ip)
+		VALUES ($1,$2,$3,$4,$5,$6)`
+	_, err := conn(ctx, t.pool).Exec(ctx, query, token.TokenHash, token.UserID, token.FamilyID, token.ExpiresAt, token.UserAgent, token.IP)
 	if err != nil {
 		return fmt.Errorf("Ошибка добавления токена в бд: %w", err)
 	}
@@ -34,33 +35,24 @@ func (t *tokenRepository) Save(ctx context.Context, token dto.RefreshToken) erro
 }
 
 func (t *tokenRepository) FindByHash(ctx context.Context, hash string) (dto.RefreshToken, error) {
-	query := `SELECT user_id, expires_at, created_at FROM refresh_tokens
+	query := `SELECT id, token_hash, user_id, family_id, expires_at, created_at, last_used_at, rotated_at, user_agent, ip
+		FROM refresh_tokens
 		WHERE token_hash = $1`
 
-	var (
-		userId    uuid.UUID
-		expiresAt time.Time
-		createdAt time.Time
-	)
-	err := t.pool.QueryRow(ctx, query, hash).Scan(&userId, &expiresAt, &createdAt)
+	token, err := scanRefreshToken(conn(ctx, t.pool).QueryRow(ctx, query, hash))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return dto.RefreshToken{}, errors.New("refresh token not found")
 		}
 		return dto.RefreshToken{}, fmt.Errorf("ошибка поиска токена: %w", err)
 	}
-	return dto.RefreshToken{
-		TokenHash: hash,
-		UserID:    userId,
-		ExpiresAt: expiresAt,
-		CreatedAt: createdAt,
-	}, nil
+	return token, nil
 }
 
 func (t *tokenRepository) DeleteByHash(ctx context.Context, hash string) error {
 	query := `DELETE FROM refresh_tokens WHERE token_hash = $1`
 
-	_, err := t.pool.Exec(ctx, query, hash)
+	_, err := conn(ctx, t.pool).Exec(ctx, query, hash)
 	if err != nil {
 		return fmt.Errorf("Ошибка удаления токена в бд: %w", err)
 	}
@@ -70,9 +62,96 @@ func (t *tokenRepository) DeleteByHash(ctx context.Context, hash string) error {
 func (t *tokenRepository) DeleteAllByUser(ctx context.Context, userID uuid.UUID) error {
 	query := `DELETE FROM refresh_tokens WHERE user_id = $1`
 
-	_, err := t.pool.Exec(ctx, query, userID)
+	_, err := conn(ctx, t.pool).Exec(ctx, query, userID)
 	if err != nil {
 		return fmt.Errorf("Ошибка удаления токена в бд: %w", err)
 	}
 	return nil
 }
+
+func (t *tokenRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]dto.RefreshToken, error) {
+	query := `SELECT id, token_hash, user_id, family_id, expires_at, created_at, last_used_at, rotated_at, user_agent, ip
+		FROM refresh_tokens
+		WHERE user_id = $1 AND expires_at > NOW() AND rotated_at IS NULL
+		ORDER BY created_at DESC`
+
+	rows, err := conn(ctx, t.pool).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения сессий: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []dto.RefreshToken
+	for rows.Next() {
+		token, err := scanRefreshToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %w", err)
+	}
+	return tokens, nil
+}
+
+func (t *tokenRepository) DeleteFamily(ctx context.Context, userID, familyID uuid.UUID) (bool, error) {
+	query := `DELETE FROM refresh_tokens WHERE family_id = $1 AND user_id = $2`
+
+	tag, err := conn(ctx, t.pool).Exec(ctx, query, familyID, userID)
+	if err != nil {
+		return false, fmt.Errorf("Ошибка удаления токена в бд: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (t *tokenRepository) Rotate(ctx context.Context, oldHash string, next dto.RefreshToken) (bool, error) {
+	tx, err := conn(ctx, t.pool).Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE refresh_tokens SET rotated_at = NOW()
+		WHERE token_hash = $1 AND rotated_at IS NULL`, oldHash)
+	if err != nil {
+		return false, fmt.Errorf("Ошибка ротации токена в бд: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	// новый токен продолжает сессию: время входа и клиент сохраняются
+	_, err = tx.Exec(ctx, `INSERT INTO refresh_tokens
+		(token_hash, user_id, family_id, expires_at, created_at, last_used_at, user_agent, ip)
+		VALUES ($1, $2, $3, $4, $5, NOW(), $6, $7)`,
+		next.TokenHash, next.UserID, next.FamilyID, next.ExpiresAt, next.CreatedAt, next.UserAgent, next.IP)
+	if err != nil {
+		return false, fmt.Errorf("Ошибка добавления токена в бд: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return true, nil
+}
+
+func (t *tokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM refresh_tokens t
+		WHERE t.expires_at < $1
+			AND NOT EXISTS (SELECT 1 FROM refresh_tokens f WHERE f.family_id = t.family_id AND f.expires_at >= $1)`
+
+	tag, err := conn(ctx, t.pool).Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка очистки refresh токенов: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanRefreshToken(row pgx.Row) (dto.RefreshToken, error) {
+	var token dto.RefreshToken
+	err := row.Scan(&token.ID, &token.TokenHash, &token.UserID, &token.FamilyID, &token.ExpiresAt, &token.CreatedAt,
+		&token.LastUsedAt, &token.RotatedAt, &token.UserAgent, &token.IP)
+	return token, err
+}
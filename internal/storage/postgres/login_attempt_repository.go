@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/auth"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type loginAttemptRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLoginAttemptRepository(pool *pgxpool.Pool) model.LoginAttemptRepository {
+	return &loginAttemptRepository{
+		pool: pool,
+	}
+}
+
+func (r *loginAttemptRepository) Get(ctx context.Context, scope model.AttemptScope, key string) (*model.LoginAttempt, error) {
+	query := `SELECT scope, key, failures, last_failure_at, locked_until
+		FROM login_attempts
+		WHERE scope = $1 AND key = $2`
+
+	attempt, err := scanLoginAttempt(conn(ctx, r.pool).QueryRow(ctx, query, scope, key))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка получения попыток входа: %w", err)
+	}
+	return &attempt, nil
+}
+
+func (r *loginAttemptRepository) RecordFailure(ctx context.Context, scope model.AttemptScope, key string, window time.Duration) (model.LoginAttempt, error) {
+	// устаревший счётчик сбрасывается вместе с истёкшей блокировкой
+	query := `INSERT INTO login_attempts (scope, key, failures, last_failure_at)
+		VALUES ($1, $2, 1, NOW())
+		ON CONFLICT (scope, key) DO UPDATE SET
+			failures = CASE
+				WHEN login_attempts.last_failure_at < NOW() - make_interval(secs => $3) THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			locked_until = CASE
+				WHEN login_attempts.locked_until < NOW() THEN NULL
+				ELSE login_attempts.locked_until
+			END,
+			last_failure_at = NOW()
+		RETURNING scope, key, failures, last_failure_at, locked_until`
+
+	attempt, err := scanLoginAttempt(conn(ctx, r.pool).QueryRow(ctx, query, scope, key, window.Seconds()))
+	if err != nil {
+		return model.LoginAttempt{}, fmt.Errorf("ошибка сохранения попытки входа: %w", err)
+	}
+	return attempt, nil
+}
+
+func (r *loginAttemptRepository) Lock(ctx context.Context, scope model.AttemptScope, key string, until time.Time) error {
+	query := `UPDATE login_attempts
+		SET locked_until = GREATEST(COALESCE(locked_until, $3), $3)
+		WHERE scope = $1 AND key = $2`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, scope, key, until); err != nil {
+		return fmt.Errorf("ошибка блокировки входа: %w", err)
+	}
+	return nil
+}
+
+func (r *loginAttemptRepository) Reset(ctx context.Context, scope model.AttemptScope, key string) (bool, error) {
+	query := `DELETE FROM login_attempts WHERE scope = $1 AND key = $2`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, scope, key)
+	if err != nil {
+		return false, fmt.Errorf("ошибка сброса попыток входа: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanLoginAttempt(row pgx.Row) (model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	err := row.Scan(&attempt.Scope, &attempt.Key, &attempt.Failures, &attempt.LastFailureAt, &attempt.LockedUntil)
+	return attempt, err
+}
@@ -28,3 +28,33 @@ func CurrentGameFromDomainToDB(model model.Game) dto.CurrentGameDTO {
 		Symbols:     model.Symbols,
 	}
 }
+
+func GameEventFromDomainToDB(event model.Event) dto.GameEventDTO {
+	return dto.GameEventDTO{
+		GameID:     event.GameID,
+		PlayerID:   event.PlayerID,
+		PlayerX:    event.PlayerX,
+		PlayerO:    event.PlayerO,
+		Status:     event.Status,
+		Field:      event.Field,
+		Winner:     event.Winner,
+		SeasonID:   event.SeasonID,
+		OccurredAt: event.OccurredAt,
+	}
+}
+
+func GameEventFromDBToDomain(id int64, eventType model.EventType, dbModel dto.GameEventDTO) model.Event {
+	return model.Event{
+		ID:         id,
+		Type:       eventType,
+		GameID:     dbModel.GameID,
+		PlayerID:   dbModel.PlayerID,
+		PlayerX:    dbModel.PlayerX,
+		PlayerO:    dbModel.PlayerO,
+		Status:     dbModel.Status,
+		Field:      dbModel.Field,
+		Winner:     dbModel.Winner,
+		SeasonID:   dbModel.SeasonID,
+		OccurredAt: dbModel.OccurredAt,
+	}
+}
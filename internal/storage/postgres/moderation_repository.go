@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/moderation"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type moderationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewModerationRepository(pool *pgxpool.Pool) model.ModerationRepository {
+	return &moderationRepository{
+		pool: pool,
+	}
+}
+
+func (r *moderationRepository) Apply(ctx context.Context, sanction model.Sanction, entry model.LogEntry) error {
+	tx, err := conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO user_sanctions (user_id, kind, reason, created_by, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, kind)
+		DO UPDATE SET reason = $3, created_by = $4, created_at = $5, expires_at = $6`
+	_, err = tx.Exec(ctx, query, sanction.UserID, string(sanction.Kind), sanction.Reason,
+		sanction.CreatedBy, sanction.CreatedAt, sanction.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения санкции: %w", err)
+	}
+	if err := insertLogEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+func (r *moderationRepository) Lift(ctx context.Context, userID uuid.UUID, kind model.SanctionKind, entry model.LogEntry) (bool, error) {
+	tx, err := conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// истёкшая санкция удаляется, но снятием не считается
+	query := `DELETE FROM user_sanctions
+		WHERE user_id = $1 AND kind = $2
+		RETURNING expires_at IS NULL OR expires_at > NOW()`
+
+	var active bool
+	err = tx.QueryRow(ctx, query, userID, string(kind)).Scan(&active)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return false, fmt.Errorf("ошибка снятия санкции: %w", err)
+	}
+	if active {
+		if err := insertLogEntry(ctx, tx, entry); err != nil {
+			return false, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return active, nil
+}
+
+func (r *moderationRepository) Active(ctx context.Context, userID uuid.UUID, kind model.SanctionKind, now time.Time) (*model.Sanction, error) {
+	query := `SELECT user_id, kind, reason, created_by, created_at, expires_at
+		FROM user_sanctions
+		WHERE user_id = $1 AND kind = $2 AND (expires_at IS NULL OR expires_at > $3)`
+
+	var (
+		sanction  model.Sanction
+		kindValue string
+		createdBy *uuid.UUID
+	)
+	err := conn(ctx, r.pool).QueryRow(ctx, query, userID, string(kind), now).Scan(&sanction.UserID, &kindValue,
+		&sanction.Reason, &createdBy, &sanction.CreatedAt, &sanction.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка получения санкции: %w", err)
+	}
+	sanction.Kind = model.SanctionKind(kindValue)
+	if createdBy != nil {
+		sanction.CreatedBy = *createdBy
+	}
+	return &sanction, nil
+}
+
+func (r *moderationRepository) GetLog(ctx context.Context, target *uuid.UUID, limit int) ([]model.LogEntry, error) {
+	query := `SELECT uuid, actor_id, target_id, action, reason, expires_at, created_at
+		FROM moderation_log
+		WHERE $1::uuid IS NULL OR target_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, target, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения журнала модерации: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []model.LogEntry
+	for rows.Next() {
+		var (
+			entry  model.LogEntry
+			action string
+		)
+		if err := rows.Scan(&entry.UUID, &entry.ActorID, &entry.TargetID, &action,
+			&entry.Reason, &entry.ExpiresAt, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения журнала модерации: %w", err)
+		}
+		entry.Action = model.Action(action)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения журнала модерации: %w", err)
+	}
+	return entries, nil
+}
+
+func insertLogEntry(ctx context.Context, tx pgx.Tx, entry model.LogEntry) error {
+	query := `INSERT INTO moderation_log (uuid, actor_id, target_id, action, reason, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := tx.Exec(ctx, query, entry.UUID, entry.ActorID, entry.TargetID, string(entry.Action),
+		entry.Reason, entry.ExpiresAt, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка записи в журнал модерации: %w", err)
+	}
+	return nil
+}
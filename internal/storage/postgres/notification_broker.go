@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/notification"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// канал LISTEN/NOTIFY новых уведомлений
+const notificationChannel = "notifications"
+
+// notificationMessage - уведомление в payload NOTIFY (не больше 8000 байт)
+type notificationMessage struct {
+	UUID      uuid.UUID         `json:"uuid"`
+	UserID    uuid.UUID         `json:"user_id"`
+	Kind      string            `json:"kind"`
+	Data      map[string]string `json:"data"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+type notificationBroker struct {
+	pool *pgxpool.Pool
+}
+
+// NewNotificationBroker рассылает уведомления через LISTEN/NOTIFY: каждый инстанс
+// получает уведомления, созданные любым инстансом
+func NewNotificationBroker(pool *pgxpool.Pool) model.Broker {
+	return &notificationBroker{
+		pool: pool,
+	}
+}
+
+func (b *notificationBroker) Publish(ctx context.Context, n model.Notification) error {
+	payload, err := json.Marshal(notificationMessage{
+		UUID:      n.UUID,
+		UserID:    n.UserID,
+		Kind:      string(n.Kind),
+		Data:      n.Data,
+		CreatedAt: n.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации уведомления: %w", err)
+	}
+	// в транзакции NOTIFY доставляется при фиксации, при откате не доставляется
+	if _, err := conn(ctx, b.pool).Exec(ctx, `SELECT pg_notify($1, $2)`, notificationChannel, string(payload)); err != nil {
+		return fmt.Errorf("ошибка отправки уведомления: %w", err)
+	}
+	return nil
+}
+
+func (b *notificationBroker) Listen(ctx context.Context, handler func(model.Notification)) error {
+	pooled, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка подключения к БД: %w", err)
+	}
+	// соединение с LISTEN не возвращается в пул
+	c := pooled.Hijack()
+	defer c.Close(context.Background())
+
+	if _, err := c.Exec(ctx, "LISTEN "+notificationChannel); err != nil {
+		return fmt.Errorf("ошибка подписки на уведомления: %w", err)
+	}
+	for {
+		notification, err := c.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("ошибка ожидания уведомления: %w", err)
+		}
+		var m notificationMessage
+		if err := json.Unmarshal([]byte(notification.Payload), &m); err != nil {
+			log.Printf("Некорректное уведомление в канале %s: %v", notificationChannel, err)
+			continue
+		}
+		handler(model.Notification{
+			UUID:      m.UUID,
+			UserID:    m.UserID,
+			Kind:      model.Kind(m.Kind),
+			Data:      m.Data,
+			CreatedAt: m.CreatedAt,
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/notification"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type notificationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewNotificationRepository(pool *pgxpool.Pool) model.NotificationRepository {
+	return &notificationRepository{
+		pool: pool,
+	}
+}
+
+func (r *notificationRepository) Save(ctx context.Context, n model.Notification) error {
+	query := `INSERT INTO notifications (uuid, user_id, kind, data, created_at)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := conn(ctx, r.pool).Exec(ctx, query, n.UUID, n.UserID, string(n.Kind), n.Data, n.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения уведомления: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepository) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]model.Notification, error) {
+	query := `SELECT uuid, kind, data, created_at, read_at
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $3`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения уведомлений: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []model.Notification
+	for rows.Next() {
+		var (
+			n    = model.Notification{UserID: userID}
+			kind string
+		)
+		if err := rows.Scan(&n.UUID, &kind, &n.Data, &n.CreatedAt, &n.ReadAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения уведомлений: %w", err)
+		}
+		n.Kind = model.Kind(kind)
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения уведомлений: %w", err)
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, at time.Time) (int64, error) {
+	query := `UPDATE notifications
+		SET read_at = $2
+		WHERE user_id = $1 AND read_at IS NULL
+			AND (cardinality($3::uuid[]) = 0 OR uuid = ANY($3))`
+
+	if ids == nil {
+		ids = []uuid.UUID{}
+	}
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, userID, at, ids)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка отметки уведомлений: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/game"
+	dto "tic-tac-toe/internal/storage/postgres/dto"
+	"tic-tac-toe/internal/storage/postgres/mappers"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type outboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewOutboxRepository(pool *pgxpool.Pool) model.OutboxRepository {
+	return &outboxRepository{
+		pool: pool,
+	}
+}
+
+// insertOutboxEvent записывает событие в транзакции, сохраняющей игру
+func insertOutboxEvent(ctx context.Context, tx pgx.Tx, event model.Event) error {
+	payload, err := json.Marshal(mappers.GameEventFromDomainToDB(event))
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации события: %w", err)
+	}
+	query := `INSERT INTO outbox (event_type, game_id, players, payload, created_at, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $5)`
+	_, err = tx.Exec(ctx, query, string(event.Type), event.GameID, event.Players(), payload, event.OccurredAt)
+	if err != nil {
+		return fmt.Errorf("ошибка записи события в outbox: %w", err)
+	}
+	return nil
+}
+
+func (r *outboxRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.OutboxRecord, error) {
+	query := `UPDATE outbox o
+		SET next_attempt_at = $2
+		WHERE o.id IN (
+			SELECT id FROM outbox
+			WHERE status = $4 AND next_attempt_at <= $1
+			ORDER BY id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING o.id, o.event_type, o.payload, o.attempts, o.done`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, now, now.Add(lease), limit, model.OutboxPending)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выборки событий outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var records []model.OutboxRecord
+	for rows.Next() {
+		var (
+			record    model.OutboxRecord
+			id        int64
+			eventType string
+			payload   []byte
+		)
+		if err := rows.Scan(&id, &eventType, &payload, &record.Attempts, &record.Done); err != nil {
+			return nil, fmt.Errorf("ошибка чтения событий outbox: %w", err)
+		}
+		var event dto.GameEventDTO
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("ошибка десериализации события %d: %w", id, err)
+		}
+		record.Event = mappers.GameEventFromDBToDomain(id, model.EventType(eventType), event)
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения событий outbox: %w", err)
+	}
+	// UPDATE ... RETURNING не сохраняет порядок подзапроса
+	slices.SortFunc(records, func(a, b model.OutboxRecord) int {
+		return cmp.Compare(a.Event.ID, b.Event.ID)
+	})
+	return records, nil
+}
+
+func (r *outboxRepository) Complete(ctx context.Context, id int64, now time.Time) error {
+	query := `UPDATE outbox SET status = $3, processed_at = $2, last_error = '' WHERE id = $1`
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, id, now, model.OutboxProcessed); err != nil {
+		return fmt.Errorf("ошибка обновления события outbox: %w", err)
+	}
+	return nil
+}
+
+func (r *outboxRepository) Retry(ctx context.Context, record model.OutboxRecord, lastError string, next time.Time) error {
+	query := `UPDATE outbox
+		SET done = $2, attempts = $3, last_error = $4, next_attempt_at = $5
+		WHERE id = $1`
+	_, err := conn(ctx, r.pool).Exec(ctx, query, record.Event.ID, record.Done, record.Attempts, lastError, next)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления события outbox: %w", err)
+	}
+	return nil
+}
+
+func (r *outboxRepository) DeadLetter(ctx context.Context, record model.OutboxRecord, lastError string) error {
+	query := `UPDATE outbox
+		SET status = $5, done = $2, attempts = $3, last_error = $4
+		WHERE id = $1`
+	_, err := conn(ctx, r.pool).Exec(ctx, query, record.Event.ID, record.Done, record.Attempts, lastError, model.OutboxDead)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления события outbox: %w", err)
+	}
+	return nil
+}
+
+func (r *outboxRepository) DeleteProcessed(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM outbox WHERE processed_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка очистки outbox: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	model "tic-tac-toe/internal/domain/model/auth"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type passwordResetRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPasswordResetRepository(pool *pgxpool.Pool) model.PasswordResetRepository {
+	return &passwordResetRepository{
+		pool: pool,
+	}
+}
+
+func (r *passwordResetRepository) Save(ctx context.Context, token model.PasswordResetToken) error {
+	query := `INSERT INTO password_reset_tokens (token_hash, user_id, expires_at)
+		VALUES ($1, $2, $3)`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, token.TokenHash, token.UserID, token.ExpiresAt); err != nil {
+		return fmt.Errorf("ошибка сохранения токена сброса пароля: %w", err)
+	}
+	return nil
+}
+
+func (r *passwordResetRepository) Consume(ctx context.Context, hash string) (uuid.UUID, bool, error) {
+	// условное обновление: из двух одновременных запросов токен погасит только один
+	query := `UPDATE password_reset_tokens
+		SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id`
+
+	var userID uuid.UUID
+	err := conn(ctx, r.pool).QueryRow(ctx, query, hash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, false, nil
+		}
+		return uuid.Nil, false, fmt.Errorf("ошибка погашения токена сброса пароля: %w", err)
+	}
+	return userID, true, nil
+}
+
+func (r *passwordResetRepository) DeleteByUser(ctx context.Context, userID uuid.UUID) error {
+	query := `DELETE FROM password_reset_tokens WHERE user_id = $1`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("ошибка удаления токенов сброса пароля: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/presence"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type presenceRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPresenceRepository(pool *pgxpool.Pool) model.PresenceRepository {
+	return &presenceRepository{
+		pool: pool,
+	}
+}
+
+func (r *presenceRepository) Touch(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	query := `INSERT INTO user_presence (user_id, last_seen_at)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id)
+		DO UPDATE SET last_seen_at = GREATEST(user_presence.last_seen_at, $2)`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, userID, now); err != nil {
+		return fmt.Errorf("ошибка обновления присутствия: %w", err)
+	}
+	return nil
+}
+
+func (r *presenceRepository) Get(ctx context.Context, userID uuid.UUID, onlineSince time.Time) (model.Presence, error) {
+	// брошенная игра (без ходов дольше срока присутствия) не считается
+	query := `SELECT last_seen_at,
+			EXISTS (SELECT 1 FROM games g
+				WHERE g.status = $2 AND g.updated_at > $3 AND (g.player_x = $1 OR g.player_o = $1))
+		FROM user_presence
+		WHERE user_id = $1`
+
+	presence := model.Presence{UserID: userID}
+	var playing bool
+	err := conn(ctx, r.pool).QueryRow(ctx, query, userID, gameModel.Playing, onlineSince).Scan(&presence.LastSeen, &playing)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return presence, nil
+		}
+		return model.Presence{}, fmt.Errorf("ошибка получения присутствия: %w", err)
+	}
+	presence.Online = presence.LastSeen.After(onlineSince)
+	presence.InGame = presence.Online && playing
+	return presence, nil
+}
+
+func (r *presenceRepository) ListOnline(ctx context.Context, onlineSince time.Time, limit int) ([]model.Presence, error) {
+	query := `SELECT u.uuid, u.login, p.last_seen_at,
+			EXISTS (SELECT 1 FROM games g
+				WHERE g.status = $2 AND g.updated_at > $1 AND (g.player_x = u.uuid OR g.player_o = u.uuid))
+		FROM user_presence p
+		JOIN users u ON u.uuid = p.user_id
+		WHERE p.last_seen_at > $1
+		ORDER BY u.login
+		LIMIT $3`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, onlineSince, gameModel.Playing, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения пользователей в сети: %w", err)
+	}
+	defer rows.Close()
+
+	var online []model.Presence
+	for rows.Next() {
+		p := model.Presence{Online: true}
+		if err := rows.Scan(&p.UserID, &p.Login, &p.LastSeen, &p.InGame); err != nil {
+			return nil, fmt.Errorf("ошибка чтения пользователей в сети: %w", err)
+		}
+		online = append(online, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения пользователей в сети: %w", err)
+	}
+	return online, nil
+}
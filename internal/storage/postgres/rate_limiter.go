@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/ratelimit"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// rateLimiter хранит бакеты в PostgreSQL: лимиты общие для всех инстансов
+type rateLimiter struct {
+	pool *pgxpool.Pool
+}
+
+func NewRateLimiter(pool *pgxpool.Pool) model.Limiter {
+	return &rateLimiter{
+		pool: pool,
+	}
+}
+
+func (l *rateLimiter) Allow(ctx context.Context, key string, limit model.Limit) (bool, time.Duration, error) {
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return false, 0, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// новый ключ получает полный бакет; затем строка блокируется до конца
+	// транзакции, и параллельные запросы с любых инстансов расходуют токены по очереди
+	now := time.Now()
+	full := limit.FullBucket(now)
+	_, err = tx.Exec(ctx, `INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO NOTHING`, key, full.Tokens, full.UpdatedAt)
+	if err != nil {
+		return false, 0, fmt.Errorf("ошибка создания бакета: %w", err)
+	}
+
+	var bucket model.Bucket
+	err = tx.QueryRow(ctx, `SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE`, key).
+		Scan(&bucket.Tokens, &bucket.UpdatedAt)
+	if err != nil {
+		return false, 0, fmt.Errorf("ошибка получения бакета: %w", err)
+	}
+
+	bucket, allowed, retryAfter := limit.Take(bucket, time.Now())
+
+	_, err = tx.Exec(ctx, `UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3 WHERE key = $1`,
+		key, bucket.Tokens, bucket.UpdatedAt)
+	if err != nil {
+		return false, 0, fmt.Errorf("ошибка сохранения бакета: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, 0, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return allowed, retryAfter, nil
+}
+
+func (l *rateLimiter) Cleanup(ctx context.Context, before time.Time) error {
+	if _, err := l.pool.Exec(ctx, `DELETE FROM rate_limit_buckets WHERE updated_at < $1`, before); err != nil {
+		return fmt.Errorf("ошибка очистки бакетов: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"tic-tac-toe/internal/storage/contract"
+	"tic-tac-toe/internal/storage/postgres"
+)
+
+func TestRateLimiter(t *testing.T) {
+	contract.RunLimiter(t, postgres.NewRateLimiter(contract.PostgresPool(t)))
+}
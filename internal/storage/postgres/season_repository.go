@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/season"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type seasonRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSeasonRepository(pool *pgxpool.Pool) model.SeasonRepository {
+	return &seasonRepository{
+		pool: pool,
+	}
+}
+
+func (r *seasonRepository) GetActive(ctx context.Context) (*model.Season, error) {
+	query := `SELECT uuid, name, starts_at, ends_at, status
+		FROM seasons
+		WHERE status = $1`
+
+	season, err := scanSeason(conn(ctx, r.pool).QueryRow(ctx, query, model.Active))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка получения сезона: %w", err)
+	}
+	return &season, nil
+}
+
+func (r *seasonRepository) GetSeason(ctx context.Context, id uuid.UUID) (model.Season, error) {
+	query := `SELECT uuid, name, starts_at, ends_at, status
+		FROM seasons
+		WHERE uuid = $1`
+
+	season, err := scanSeason(conn(ctx, r.pool).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Season{}, fmt.Errorf("season not found: %w", err)
+		}
+		return model.Season{}, fmt.Errorf("ошибка получения сезона: %w", err)
+	}
+	return season, nil
+}
+
+func (r *seasonRepository) ListSeasons(ctx context.Context) ([]model.Season, error) {
+	query := `SELECT uuid, name, starts_at, ends_at, status
+		FROM seasons
+		ORDER BY starts_at DESC`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения сезонов: %w", err)
+	}
+	defer rows.Close()
+
+	var seasons []model.Season
+	for rows.Next() {
+		season, err := scanSeason(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		seasons = append(seasons, season)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %w", err)
+	}
+	return seasons, nil
+}
+
+func (r *seasonRepository) CreateActive(ctx context.Context, season model.Season) error {
+	query := `INSERT INTO seasons (uuid, name, starts_at, ends_at, status)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (status) WHERE status = 0 DO NOTHING`
+
+	_, err := conn(ctx, r.pool).Exec(ctx, query, season.UUID, season.Name, season.StartsAt, season.EndsAt, model.Active)
+	if err != nil {
+		return fmt.Errorf("ошибка создания сезона: %w", err)
+	}
+	return nil
+}
+
+func (r *seasonRepository) Archive(ctx context.Context, seasonID uuid.UUID, next model.Season) (bool, error) {
+	tx, err := conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE seasons SET status = $2 WHERE uuid = $1 AND status = $3`,
+		seasonID, model.Archived, model.Active)
+	if err != nil {
+		return false, fmt.Errorf("ошибка закрытия сезона: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO seasons (uuid, name, starts_at, ends_at, status)
+		VALUES ($1, $2, $3, $4, $5)`, next.UUID, next.Name, next.StartsAt, next.EndsAt, model.Active)
+	if err != nil {
+		return false, fmt.Errorf("ошибка архивации сезона: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return true, nil
+}
+
+func (r *seasonRepository) SaveStandings(ctx context.Context, seasonID uuid.UUID, standings []gameModel.UserLeaders) error {
+	batch := &pgx.Batch{}
+	for i, leader := range standings {
+		batch.Queue(`INSERT INTO season_standings (season_id, place, user_id, login, win_rate)
+			VALUES ($1, $2, $3, $4, $5)`, seasonID, i+1, leader.UserId, leader.Login, leader.WinRate)
+	}
+	if err := conn(ctx, r.pool).SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("ошибка сохранения таблицы сезона: %w", err)
+	}
+	return nil
+}
+
+func (r *seasonRepository) GetArchivedLeaderBoard(ctx context.Context, seasonID uuid.UUID, count int) ([]gameModel.UserLeaders, error) {
+	query := `SELECT login, user_id, win_rate::TEXT
+		FROM season_standings
+		WHERE season_id = $1
+		ORDER BY place
+		LIMIT $2`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, seasonID, count)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения таблицы: %w", err)
+	}
+	defer rows.Close()
+
+	var leaders []gameModel.UserLeaders
+	for rows.Next() {
+		var leader gameModel.UserLeaders
+		if err := rows.Scan(&leader.Login, &leader.UserId, &leader.WinRate); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		leaders = append(leaders, leader)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %w", err)
+	}
+	return leaders, nil
+}
+
+func scanSeason(row pgx.Row) (model.Season, error) {
+	var s model.Season
+	err := row.Scan(&s.UUID, &s.Name, &s.StartsAt, &s.EndsAt, &s.Status)
+	return s, err
+}
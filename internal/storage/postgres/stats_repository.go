@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	model "tic-tac-toe/internal/domain/model/admin"
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	userModel "tic-tac-toe/internal/domain/model/user"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type statsRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewStatsRepository(pool *pgxpool.Pool) model.StatsRepository {
+	return &statsRepository{
+		pool: pool,
+	}
+}
+
+func (r *statsRepository) GetSystemStats(ctx context.Context) (model.SystemStats, error) {
+	query := `SELECT
+		(SELECT COUNT(*) FROM users),
+		(SELECT COUNT(*) FROM users WHERE $1 = ANY(roles)),
+		(SELECT COUNT(*) FROM games WHERE status = $2),
+		(SELECT COUNT(*) FROM games WHERE status = $3),
+		(SELECT COUNT(*) FROM games WHERE status IN ($4, $5, $6)),
+		(SELECT COUNT(*) FROM refresh_tokens WHERE expires_at > NOW()),
+		(SELECT COUNT(*) FROM tournaments),
+		(SELECT COUNT(*) FROM login_attempts WHERE locked_until > NOW())`
+
+	var stats model.SystemStats
+	err := conn(ctx, r.pool).QueryRow(ctx, query, string(userModel.RoleAdmin),
+		gameModel.Waiting, gameModel.Playing, gameModel.WonX, gameModel.WonO, gameModel.Draw).Scan(
+		&stats.Users, &stats.Admins,
+		&stats.GamesWaiting, &stats.GamesPlaying, &stats.GamesFinished,
+		&stats.ActiveSessions, &stats.Tournaments, &stats.LockedLogins)
+	if err != nil {
+		return model.SystemStats{}, fmt.Errorf("ошибка получения статистики: %w", err)
+	}
+	stats.CollectedAt = time.Now()
+	return stats, nil
+}
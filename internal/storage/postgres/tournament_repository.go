@@ -0,0 +1,230 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	model "tic-tac-toe/internal/domain/model/tournament"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type tournamentRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTournamentRepository(pool *pgxpool.Pool) model.TournamentRepository {
+	return &tournamentRepository{
+		pool: pool,
+	}
+}
+
+func (r *tournamentRepository) CreateTournament(ctx context.Context, t model.Tournament) error {
+	query := `INSERT INTO tournaments (uuid, name, format, status, owner_id, current_round, rounds, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := conn(ctx, r.pool).Exec(ctx, query, t.UUID, t.Name, t.Format, t.Status, t.OwnerID, t.CurrentRound, t.Rounds, t.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка создания турнира: %w", err)
+	}
+	return nil
+}
+
+func (r *tournamentRepository) GetTournament(ctx context.Context, id uuid.UUID) (model.Tournament, error) {
+	query := `SELECT uuid, name, format, status, owner_id, current_round, rounds, created_at
+		FROM tournaments
+		WHERE uuid = $1`
+
+	t, err := scanTournament(conn(ctx, r.pool).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Tournament{}, fmt.Errorf("tournament not found: %w", err)
+		}
+		return model.Tournament{}, fmt.Errorf("ошибка получения турнира: %w", err)
+	}
+	return t, nil
+}
+
+func (r *tournamentRepository) ListTournaments(ctx context.Context) ([]model.Tournament, error) {
+	query := `SELECT uuid, name, format, status, owner_id, current_round, rounds, created_at
+		FROM tournaments
+		ORDER BY created_at DESC`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения турниров: %w", err)
+	}
+	defer rows.Close()
+
+	var tournaments []model.Tournament
+	for rows.Next() {
+		t, err := scanTournament(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		tournaments = append(tournaments, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %w", err)
+	}
+	return tournaments, nil
+}
+
+func (r *tournamentRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status model.Status) error {
+	query := `UPDATE tournaments SET status = $2, updated_at = NOW() WHERE uuid = $1`
+
+	_, err := conn(ctx, r.pool).Exec(ctx, query, id, status)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления турнира: %w", err)
+	}
+	return nil
+}
+
+func (r *tournamentRepository) UpdateRounds(ctx context.Context, id uuid.UUID, rounds int) error {
+	query := `UPDATE tournaments SET rounds = $2, updated_at = NOW() WHERE uuid = $1`
+
+	_, err := conn(ctx, r.pool).Exec(ctx, query, id, rounds)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления турнира: %w", err)
+	}
+	return nil
+}
+
+func (r *tournamentRepository) AdvanceRound(ctx context.Context, id uuid.UUID, from, to int) (bool, error) {
+	query := `UPDATE tournaments SET current_round = $3, updated_at = NOW()
+		WHERE uuid = $1 AND current_round = $2`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, id, from, to)
+	if err != nil {
+		return false, fmt.Errorf("ошибка смены тура: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *tournamentRepository) AddParticipant(ctx context.Context, p model.Participant) error {
+	query := `INSERT INTO tournament_participants (tournament_id, user_id, seed)
+		VALUES ($1, $2, $3)`
+
+	_, err := conn(ctx, r.pool).Exec(ctx, query, p.TournamentID, p.UserID, p.Seed)
+	if err != nil {
+		return fmt.Errorf("ошибка добавления участника: %w", err)
+	}
+	return nil
+}
+
+func (r *tournamentRepository) GetParticipants(ctx context.Context, tournamentID uuid.UUID) ([]model.Participant, error) {
+	query := `SELECT user_id, seed
+		FROM tournament_participants
+		WHERE tournament_id = $1
+		ORDER BY seed`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, tournamentID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения участников: %w", err)
+	}
+	defer rows.Close()
+
+	var participants []model.Participant
+	for rows.Next() {
+		var (
+			userID uuid.UUID
+			seed   int
+		)
+		if err := rows.Scan(&userID, &seed); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		participants = append(participants, model.Participant{
+			TournamentID: tournamentID,
+			UserID:       userID,
+			Seed:         seed,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %w", err)
+	}
+	return participants, nil
+}
+
+func (r *tournamentRepository) SavePairings(ctx context.Context, pairings []model.Pairing) error {
+	query := `INSERT INTO tournament_pairings (uuid, tournament_id, round, board, player_x, player_o, game_id, result)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	batch := &pgx.Batch{}
+	for _, p := range pairings {
+		batch.Queue(query, p.UUID, p.TournamentID, p.Round, p.Board, p.PlayerX, p.PlayerO, p.GameID, p.Result)
+	}
+	if err := conn(ctx, r.pool).SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("ошибка сохранения пар: %w", err)
+	}
+	return nil
+}
+
+func (r *tournamentRepository) GetPairings(ctx context.Context, tournamentID uuid.UUID) ([]model.Pairing, error) {
+	query := `SELECT uuid, tournament_id, round, board, player_x, player_o, game_id, result
+		FROM tournament_pairings
+		WHERE tournament_id = $1
+		ORDER BY round, board`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, tournamentID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения пар: %w", err)
+	}
+	defer rows.Close()
+
+	var pairings []model.Pairing
+	for rows.Next() {
+		p, err := scanPairing(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		pairings = append(pairings, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %w", err)
+	}
+	return pairings, nil
+}
+
+func (r *tournamentRepository) FindPairingByGame(ctx context.Context, gameID uuid.UUID) (*model.Pairing, error) {
+	query := `SELECT uuid, tournament_id, round, board, player_x, player_o, game_id, result
+		FROM tournament_pairings
+		WHERE game_id = $1`
+
+	p, err := scanPairing(conn(ctx, r.pool).QueryRow(ctx, query, gameID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка поиска пары: %w", err)
+	}
+	return &p, nil
+}
+
+func (r *tournamentRepository) UpdatePairing(ctx context.Context, p model.Pairing) error {
+	query := `UPDATE tournament_pairings
+		SET player_x = $2, player_o = $3, game_id = $4, result = $5
+		WHERE uuid = $1`
+
+	_, err := conn(ctx, r.pool).Exec(ctx, query, p.UUID, p.PlayerX, p.PlayerO, p.GameID, p.Result)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления пары: %w", err)
+	}
+	return nil
+}
+
+func scanTournament(row pgx.Row) (model.Tournament, error) {
+	var t model.Tournament
+	err := row.Scan(&t.UUID, &t.Name, &t.Format, &t.Status, &t.OwnerID, &t.CurrentRound, &t.Rounds, &t.CreatedAt)
+	return t, err
+}
+
+func scanPairing(row pgx.Row) (model.Pairing, error) {
+	var p model.Pairing
+	err := row.Scan(&p.UUID, &p.TournamentID, &p.Round, &p.Board, &p.PlayerX, &p.PlayerO, &p.GameID, &p.Result)
+	return p, err
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	model "tic-tac-toe/internal/domain/model/auth"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type twoFactorRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTwoFactorRepository(pool *pgxpool.Pool) model.TwoFactorRepository {
+	return &twoFactorRepository{
+		pool: pool,
+	}
+}
+
+func (r *twoFactorRepository) Get(ctx context.Context, userID uuid.UUID) (*model.TwoFactor, error) {
+	query := `SELECT user_id, secret, enabled_at, last_used_step
+		FROM user_two_factor
+		WHERE user_id = $1`
+
+	var tf model.TwoFactor
+	err := conn(ctx, r.pool).QueryRow(ctx, query, userID).Scan(&tf.UserID, &tf.Secret, &tf.EnabledAt, &tf.LastUsedStep)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка получения настроек 2FA: %w", err)
+	}
+	return &tf, nil
+}
+
+func (r *twoFactorRepository) SaveSecret(ctx context.Context, userID uuid.UUID, secret string) (bool, error) {
+	// включённую 2FA перезаписать нельзя - только отключить
+	query := `INSERT INTO user_two_factor (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, created_at = NOW()
+		WHERE user_two_factor.enabled_at IS NULL`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, userID, secret)
+	if err != nil {
+		return false, fmt.Errorf("ошибка сохранения секрета 2FA: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *twoFactorRepository) Enable(ctx context.Context, userID uuid.UUID, step int64, recoveryHashes []string) error {
+	tx, err := conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE user_two_factor
+		SET enabled_at = NOW(), last_used_step = $2
+		WHERE user_id = $1`, userID, step)
+	if err != nil {
+		return fmt.Errorf("ошибка включения 2FA: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("ошибка удаления кодов восстановления: %w", err)
+	}
+	for _, hash := range recoveryHashes {
+		_, err := tx.Exec(ctx, `INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения кода восстановления: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) Disable(ctx context.Context, userID uuid.UUID) error {
+	tx, err := conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("ошибка удаления кодов восстановления: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM user_two_factor WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("ошибка отключения 2FA: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	query := `UPDATE user_two_factor
+		SET last_used_step = $2
+		WHERE user_id = $1 AND last_used_step < $2`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("ошибка проверки кода 2FA: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string) (bool, error) {
+	query := `UPDATE user_recovery_codes
+		SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, userID, hash)
+	if err != nil {
+		return false, fmt.Errorf("ошибка проверки кода восстановления: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *twoFactorRepository) UseChallenge(ctx context.Context, userID uuid.UUID, jti string, expiresAt time.Time) (bool, error) {
+	// истёкший токен уже не предъявить, его запись не нужна
+	_, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM used_challenge_tokens
+		WHERE user_id = $1 AND expires_at < NOW()`, userID)
+	if err != nil {
+		return false, fmt.Errorf("ошибка удаления истёкших challenge токенов: %w", err)
+	}
+
+	query := `INSERT INTO used_challenge_tokens (jti, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, jti, userID, expiresAt)
+	if err != nil {
+		return false, fmt.Errorf("ошибка погашения challenge токена: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -2,7 +2,6 @@ package postgres
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	model "tic-tac-toe/internal/domain/model/user"
 
@@ -10,7 +9,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var ErrUserNotFound = errors.New("user not found")
+var ErrUserNotFound = model.ErrUserNotFound
 
 type userRepository struct {
 	pool *pgxpool.Pool
@@ -25,7 +24,7 @@ func NewUserRepository(pool *pgxpool.Pool) model.UserRepository {
 func (r *userRepository) CreateUser(ctx context.Context, user model.User) error {
 	query := `INSERT INTO users (uuid, login, password)
 		VALUES ($1,$2,$3)`
-	_, err := r.pool.Exec(ctx, query, user.UUID, user.Login, user.Password)
+	_, err := conn(ctx, r.pool).Exec(ctx, query, user.UUID, user.Login, user.Password)
 	if err != nil {
 		return fmt.Errorf("Ошибка создания пользователя: %w", err)
 	}
@@ -33,30 +32,33 @@ func (r *userRepository) CreateUser(ctx context.Context, user model.User) error
 }
 
 func (r *userRepository) GetUserByID(ctx context.Context, id uuid.UUID) (model.User, error) {
-	query := `SELECT uuid, login
+	query := `SELECT uuid, login, roles
 		FROM users
 		WHERE uuid = $1`
 
 	var userID uuid.UUID
 	var userLogin string
-	err := r.pool.QueryRow(ctx, query, id).Scan(&userID, &userLogin)
+	var roles []string
+	err := conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(&userID, &userLogin, &roles)
 	if err != nil {
 		return model.User{}, ErrUserNotFound
 	}
 	return model.User{
 		UUID:  userID,
 		Login: userLogin,
+		Roles: toRoles(roles),
 	}, nil
 }
 
 func (r *userRepository) GetUserByLogin(ctx context.Context, login string) (*model.User, error) {
-	query := `SELECT uuid, login, password 
+	query := `SELECT uuid, login, password, roles
 		FROM users
 		WHERE login = $1`
 
 	var userID uuid.UUID
 	var userLogin, userPassword string
-	err := r.pool.QueryRow(ctx, query, login).Scan(&userID, &userLogin, &userPassword)
+	var roles []string
+	err := conn(ctx, r.pool).QueryRow(ctx, query, login).Scan(&userID, &userLogin, &userPassword, &roles)
 	if err != nil {
 		return nil, ErrUserNotFound
 	}
@@ -64,5 +66,58 @@ func (r *userRepository) GetUserByLogin(ctx context.Context, login string) (*mod
 		UUID:     userID,
 		Login:    userLogin,
 		Password: userPassword,
+		Roles:    toRoles(roles),
 	}, nil
 }
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	query := `UPDATE users SET password = $2 WHERE uuid = $1`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, id, passwordHash)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления пароля: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) AddRole(ctx context.Context, id uuid.UUID, role model.Role) error {
+	query := `UPDATE users
+		SET roles = CASE WHEN $2 = ANY(roles) THEN roles ELSE array_append(roles, $2) END,
+			updated_at = NOW()
+		WHERE uuid = $1`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, id, string(role))
+	if err != nil {
+		return fmt.Errorf("ошибка назначения роли: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) RemoveRole(ctx context.Context, id uuid.UUID, role model.Role) error {
+	query := `UPDATE users
+		SET roles = array_remove(roles, $2), updated_at = NOW()
+		WHERE uuid = $1`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, id, string(role))
+	if err != nil {
+		return fmt.Errorf("ошибка снятия роли: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func toRoles(names []string) []model.Role {
+	roles := make([]model.Role, 0, len(names))
+	for _, n := range names {
+		roles = append(roles, model.Role(n))
+	}
+	return roles
+}
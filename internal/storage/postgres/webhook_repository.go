@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	model "tic-tac-toe/internal/domain/model/webhook"
+	"tic-tac-toe/internal/storage/postgres/mappers"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type webhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepository(pool *pgxpool.Pool) model.WebhookRepository {
+	return &webhookRepository{
+		pool: pool,
+	}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, w model.Webhook) error {
+	query := `INSERT INTO webhooks (uuid, owner_id, url, secret, events, all_games, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := conn(ctx, r.pool).Exec(ctx, query, w.UUID, w.OwnerID, w.URL, w.Secret, eventNames(w.Events), w.AllGames, w.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения вебхука: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]model.Webhook, error) {
+	query := `SELECT uuid, url, events, all_games, created_at
+		FROM webhooks
+		WHERE owner_id = $1
+		ORDER BY created_at`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения вебхуков: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []model.Webhook
+	for rows.Next() {
+		var (
+			w      = model.Webhook{OwnerID: ownerID}
+			events []string
+		)
+		if err := rows.Scan(&w.UUID, &w.URL, &events, &w.AllGames, &w.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения вебхуков: %w", err)
+		}
+		w.Events = toEventTypes(events)
+		webhooks = append(webhooks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения вебхуков: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepository) CountByOwner(ctx context.Context, ownerID uuid.UUID) (int, error) {
+	var count int
+	err := conn(ctx, r.pool).QueryRow(ctx, `SELECT COUNT(*) FROM webhooks WHERE owner_id = $1`, ownerID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка подсчёта вебхуков: %w", err)
+	}
+	return count, nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id, ownerID uuid.UUID) (bool, error) {
+	tag, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM webhooks WHERE uuid = $1 AND owner_id = $2`, id, ownerID)
+	if err != nil {
+		return false, fmt.Errorf("ошибка удаления вебхука: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, id, ownerID uuid.UUID, limit int) ([]model.Delivery, error) {
+	query := `SELECT d.uuid, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+			d.response_code, d.last_error, d.next_attempt_at, d.created_at, d.delivered_at
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.uuid = d.webhook_id
+		WHERE d.webhook_id = $1 AND w.owner_id = $2
+		ORDER BY d.created_at DESC
+		LIMIT $3`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, id, ownerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения журнала доставки: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []model.Delivery
+	for rows.Next() {
+		var (
+			d         = model.Delivery{WebhookID: id}
+			eventType string
+			status    string
+		)
+		if err := rows.Scan(&d.UUID, &d.EventID, &eventType, &d.Payload, &status, &d.Attempts,
+			&d.ResponseCode, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.DeliveredAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения журнала доставки: %w", err)
+		}
+		d.Event = gameModel.EventType(eventType)
+		d.Status = model.DeliveryStatus(status)
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения журнала доставки: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepository) Enqueue(ctx context.Context, event gameModel.Event, now time.Time) error {
+	payload, err := json.Marshal(mappers.GameEventFromDomainToDB(event))
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации события: %w", err)
+	}
+	query := `INSERT INTO webhook_deliveries (uuid, webhook_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+		SELECT gen_random_uuid(), w.uuid, $1, $2, $3, $4, $5, $5
+		FROM webhooks w
+		WHERE $2 = ANY(w.events) AND (w.all_games OR w.owner_id = ANY($6))
+		ON CONFLICT (webhook_id, event_id) DO NOTHING`
+
+	_, err = conn(ctx, r.pool).Exec(ctx, query, event.ID, string(event.Type), payload, string(model.DeliveryPending), now, event.Players())
+	if err != nil {
+		return fmt.Errorf("ошибка создания доставок: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.Delivery, error) {
+	query := `UPDATE webhook_deliveries d
+		SET next_attempt_at = $2
+		FROM webhooks w
+		WHERE w.uuid = d.webhook_id
+			AND d.uuid IN (
+				SELECT uuid FROM webhook_deliveries
+				WHERE status = $3 AND next_attempt_at <= $1
+				ORDER BY next_attempt_at
+				LIMIT $4
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING d.uuid, d.webhook_id, d.event_id, d.event_type, d.payload, d.attempts, d.created_at, w.url, w.secret`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, now, now.Add(lease), string(model.DeliveryPending), limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выборки доставок: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []model.Delivery
+	for rows.Next() {
+		var (
+			d         = model.Delivery{Status: model.DeliveryPending}
+			eventType string
+		)
+		if err := rows.Scan(&d.UUID, &d.WebhookID, &d.EventID, &eventType, &d.Payload, &d.Attempts,
+			&d.CreatedAt, &d.URL, &d.Secret); err != nil {
+			return nil, fmt.Errorf("ошибка чтения доставок: %w", err)
+		}
+		d.Event = gameModel.EventType(eventType)
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения доставок: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepository) SaveAttempt(ctx context.Context, d model.Delivery) error {
+	query := `UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, response_code = $4, last_error = $5,
+			next_attempt_at = $6, delivered_at = $7
+		WHERE uuid = $1`
+
+	_, err := conn(ctx, r.pool).Exec(ctx, query, d.UUID, string(d.Status), d.Attempts, d.ResponseCode,
+		d.LastError, d.NextAttemptAt, d.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения попытки доставки: %w", err)
+	}
+	return nil
+}
+
+func eventNames(events []gameModel.EventType) []string {
+	names := make([]string, 0, len(events))
+	for _, e := range events {
+		names = append(names, string(e))
+	}
+	return names
+}
+
+func toEventTypes(names []string) []gameModel.EventType {
+	events := make([]gameModel.EventType, 0, len(names))
+	for _, name := range names {
+		events = append(events, gameModel.EventType(name))
+	}
+	return events
+}
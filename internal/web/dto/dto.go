@@ -2,6 +2,7 @@ package dto
 
 import (
 	model "tic-tac-toe/internal/domain/model/game"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -28,8 +29,19 @@ type SignUpRequest struct {
 }
 
 type UserResponse struct {
-	UUID  uuid.UUID `json:"uuid"`
-	Login string    `json:"login"`
+	UUID         uuid.UUID             `json:"uuid"`
+	Login        string                `json:"login"`
+	Roles        []string              `json:"roles,omitempty"`
+	Online       bool                  `json:"online"`
+	InGame       bool                  `json:"in_game"`
+	Achievements []AchievementResponse `json:"achievements,omitempty"`
+}
+
+type AchievementResponse struct {
+	Code        string    `json:"code"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	UnlockedAt  time.Time `json:"unlocked_at"`
 }
 
 type NewGameRequest struct {
@@ -46,6 +58,21 @@ type LeaderResponse struct {
 	WinRate string    `json:"win_rate"`
 }
 
+type StreakResponse struct {
+	Winner *uuid.UUID `json:"winner"`
+	Count  int        `json:"count"`
+}
+
+type HeadToHeadResponse struct {
+	PlayerID     uuid.UUID      `json:"player_uuid"`
+	OpponentID   uuid.UUID      `json:"opponent_uuid"`
+	Total        int            `json:"total_games"`
+	PlayerWins   int            `json:"player_wins"`
+	OpponentWins int            `json:"opponent_wins"`
+	Draws        int            `json:"draws"`
+	LastGames    []GameResponse `json:"last_games"`
+	Streak       StreakResponse `json:"streak"`
+}
 
 type JwtRequest struct {
 	Login    string `json:"login" validate:"required,min=3"`
@@ -58,6 +85,257 @@ type JwtResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// ответ на вход: пара токенов, либо challenge токен, если включена 2FA
+type LoginResponse struct {
+	*JwtResponse
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+}
+
+// ответ на вход в режиме cookie: токены в HttpOnly cookie, в теле только CSRF токен
+type CookieSessionResponse struct {
+	Type      string `json:"token_type"`
+	CSRFToken string `json:"csrf_token"`
+	ExpiresIn int    `json:"expires_in"`
+}
+
+// второй шаг входа: challenge токен и код TOTP или код восстановления
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
 type RefreshJwtRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+type PasswordResetRequest struct {
+	Login string `json:"login"`
+}
+
+type PasswordResetConfirmRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// снятие блокировки входа: указывается login или ip
+type UnlockRequest struct {
+	Login string `json:"login,omitempty"`
+	IP    string `json:"ip,omitempty"`
+}
+
+type SessionResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+}
+
+type NewTournamentRequest struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+	Rounds int    `json:"rounds,omitempty"`
+}
+
+type TournamentResponse struct {
+	UUID         uuid.UUID   `json:"uuid"`
+	Name         string      `json:"name"`
+	Format       string      `json:"format"`
+	Status       string      `json:"status"`
+	OwnerID      uuid.UUID   `json:"owner_uuid"`
+	CurrentRound int         `json:"current_round"`
+	Rounds       int         `json:"rounds"`
+	CreatedAt    time.Time   `json:"created_at"`
+	Participants []uuid.UUID `json:"participants,omitempty"`
+}
+
+type StandingResponse struct {
+	Place    int       `json:"place"`
+	UserID   uuid.UUID `json:"uuid"`
+	Played   int       `json:"played"`
+	Wins     int       `json:"wins"`
+	Draws    int       `json:"draws"`
+	Losses   int       `json:"losses"`
+	Byes     int       `json:"byes"`
+	Points   float64   `json:"points"`
+	Buchholz float64   `json:"buchholz"`
+}
+
+type PairingResponse struct {
+	Board   int        `json:"board"`
+	PlayerX uuid.UUID  `json:"player_x"`
+	PlayerO *uuid.UUID `json:"player_o"`
+	GameID  *uuid.UUID `json:"game_uuid"`
+	Result  string     `json:"result"`
+	Winner  *uuid.UUID `json:"winner"`
+}
+
+type RoundResponse struct {
+	Round    int               `json:"round"`
+	Pairings []PairingResponse `json:"pairings"`
+}
+
+type SeasonResponse struct {
+	UUID     uuid.UUID `json:"uuid"`
+	Name     string    `json:"name"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Status   string    `json:"status"`
+}
+
+// JSON Web Key (RFC 7517) для публикации в /.well-known/jwks.json
+type JWKResponse struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSResponse struct {
+	Keys []JWKResponse `json:"keys"`
+}
+
+// сводка по системе (GET /admin/stats)
+type SystemStatsResponse struct {
+	Users          int       `json:"users"`
+	Admins         int       `json:"admins"`
+	GamesWaiting   int       `json:"games_waiting"`
+	GamesPlaying   int       `json:"games_playing"`
+	GamesFinished  int       `json:"games_finished"`
+	ActiveSessions int       `json:"active_sessions"`
+	Tournaments    int       `json:"tournaments"`
+	LockedLogins   int       `json:"locked_logins"`
+	CollectedAt    time.Time `json:"collected_at"`
+}
+
+// бан или запрет писать; без expires_at - бессрочно.
+// При снятии санкции используется только reason
+type SanctionRequest struct {
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type SanctionResponse struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	Kind      string     `json:"kind"`
+	Reason    string     `json:"reason"`
+	CreatedBy uuid.UUID  `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type ModerationLogEntryResponse struct {
+	UUID      uuid.UUID  `json:"uuid"`
+	ActorID   *uuid.UUID `json:"actor_id"`
+	TargetID  uuid.UUID  `json:"target_id"`
+	Action    string     `json:"action"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type FriendResponse struct {
+	UUID  uuid.UUID `json:"uuid"`
+	Login string    `json:"login"`
+	Since time.Time `json:"since"`
+}
+
+type FriendRequestResponse struct {
+	UUID  uuid.UUID `json:"uuid"`
+	Login string    `json:"login"`
+	// incoming - заявка мне, outgoing - моя заявка
+	Direction string    `json:"direction"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// результат отправки заявки: pending или accepted (была встречная заявка)
+type FriendshipStatusResponse struct {
+	Status string `json:"status"`
+}
+
+type BlockedUserResponse struct {
+	UUID      uuid.UUID `json:"uuid"`
+	Login     string    `json:"login"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// пользователь в сети (GET /users/online)
+type PresenceResponse struct {
+	UUID     uuid.UUID `json:"uuid"`
+	Login    string    `json:"login"`
+	InGame   bool      `json:"in_game"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+type NotificationResponse struct {
+	UUID      uuid.UUID         `json:"uuid"`
+	Kind      string            `json:"kind"`
+	Data      map[string]string `json:"data"`
+	Read      bool              `json:"read"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// отметка прочитанными; без ids - все уведомления
+type MarkNotificationsReadRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+type MarkNotificationsReadResponse struct {
+	Marked int64 `json:"marked"`
+}
+
+// регистрация вебхука; all_games - все игры (только администратор)
+type CreateWebhookRequest struct {
+	URL      string   `json:"url"`
+	Events   []string `json:"events"`
+	AllGames bool     `json:"all_games"`
+}
+
+type WebhookResponse struct {
+	UUID      uuid.UUID `json:"uuid"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	AllGames  bool      `json:"all_games"`
+	CreatedAt time.Time `json:"created_at"`
+	// ключ подписи; возвращается только при создании
+	Secret string `json:"secret,omitempty"`
+}
+
+type WebhookDeliveryResponse struct {
+	UUID          uuid.UUID  `json:"uuid"`
+	EventID       int64      `json:"event_id"`
+	Event         string     `json:"event"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	ResponseCode  *int       `json:"response_code"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
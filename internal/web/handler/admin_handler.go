@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	userModel "tic-tac-toe/internal/domain/model/user"
+	adminService "tic-tac-toe/internal/service/admin_service"
+	gameService "tic-tac-toe/internal/service/game_service"
+	userService "tic-tac-toe/internal/service/user_service"
+	"tic-tac-toe/internal/web/mappers"
+	"tic-tac-toe/internal/web/middleware"
+
+	"github.com/google/uuid"
+)
+
+type AdminAPI struct {
+	adminServis adminService.AdminService
+}
+
+func NewAdminAPI(servis adminService.AdminService) *AdminAPI {
+	return &AdminAPI{
+		adminServis: servis,
+	}
+}
+
+// сводка по системе
+func (api *AdminAPI) HandlerStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	stats, err := api.adminServis.Stats(r.Context())
+	if err != nil {
+		log.Printf("Error fetching stats: %v", err)
+		http.Error(w, "Failed to fetch stats", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(mappers.SystemStatsFromDomainToWeb(stats)); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// удаление игры: DELETE /admin/games/{id}
+func (api *AdminAPI) HandlerDeleteGame(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	actor, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	gameID, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/admin/games/"))
+	if err != nil {
+		http.Error(w, "Invalid game ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := api.adminServis.DeleteGame(ctx, actor, gameID); err != nil {
+		if errors.Is(err, gameService.ErrGameNotFound) {
+			http.Error(w, "Game not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting game %s: %v", gameID, err)
+		http.Error(w, "Failed to delete game", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// роли пользователя: PUT|DELETE /admin/users/{uuid}/roles/{role}
+func (api *AdminAPI) HandlerUserRole(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	actor, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userPart, rolePart, found := strings.Cut(strings.TrimPrefix(r.URL.Path, "/admin/users/"), "/roles/")
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	userID, err := uuid.Parse(userPart)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	role := userModel.Role(rolePart)
+
+	if r.Method == http.MethodPut {
+		err = api.adminServis.GrantRole(ctx, actor, userID, role)
+	} else {
+		err = api.adminServis.RevokeRole(ctx, actor, userID, role)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, userService.ErrUnknownRole):
+			http.Error(w, "Unknown role", http.StatusBadRequest)
+		case errors.Is(err, userService.ErrUserNotFound):
+			http.Error(w, "User not found", http.StatusNotFound)
+		case errors.Is(err, adminService.ErrCannotRevokeOwnAdmin):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Printf("Error changing role %s for %s: %v", role, userID, err)
+			http.Error(w, "Failed to change role", http.StatusInternalServerError)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
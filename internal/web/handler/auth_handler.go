@@ -2,13 +2,20 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
+	authModel "tic-tac-toe/internal/domain/model/auth"
+	achievement "tic-tac-toe/internal/service/achievement_service"
 	auth "tic-tac-toe/internal/service/auth_service"
 	jwt "tic-tac-toe/internal/service/jwt_service"
+	presence "tic-tac-toe/internal/service/presence_service"
 	user "tic-tac-toe/internal/service/user_service"
 	dto "tic-tac-toe/internal/web/dto"
 	mappers "tic-tac-toe/internal/web/mappers"
@@ -18,16 +25,23 @@ import (
 )
 
 type AuthAPI struct {
-	authServis auth.AuthService
-	userServis user.UserService
-	jwt        jwt.JwtProvider
+	authServis   auth.AuthService
+	userServis   user.UserService
+	jwt          jwt.JwtProvider
+	achievements achievement.AchievementService
+	cookies      middleware.CookieConfig
+	presence     presence.PresenceService
 }
 
-func NewAuthAPI(servis auth.AuthService, user user.UserService, jwt jwt.JwtProvider) *AuthAPI {
+func NewAuthAPI(servis auth.AuthService, user user.UserService, jwt jwt.JwtProvider, achievements achievement.AchievementService,
+	cookies middleware.CookieConfig, presence presence.PresenceService) *AuthAPI {
 	return &AuthAPI{
-		authServis: servis,
-		userServis: user,
-		jwt:        jwt,
+		authServis:   servis,
+		userServis:   user,
+		jwt:          jwt,
+		achievements: achievements,
+		cookies:      cookies,
+		presence:     presence,
 	}
 }
 
@@ -70,12 +84,21 @@ func (api *AuthAPI) HandlerAuthorization(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
-	user, err := api.authServis.Login(r.Context(), req)
+	client := authModel.Client{
+		UserAgent: r.UserAgent(),
+		IP:        middleware.ClientIP(r),
+	}
+	user, err := api.authServis.Login(r.Context(), req, client)
 	if err != nil {
-		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+		writeLoginError(w, err)
+		return
+	}
+	if user.JwtResponse != nil {
+		writeTokens(w, api.cookies, api.cookies.CookieMode(r), *user.JwtResponse)
 		return
 	}
 
+	// нужен второй фактор: возвращаем challenge токен
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(user); err != nil {
@@ -105,6 +128,8 @@ func (api *AuthAPI) HandlerGetUserUUID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := mappers.UserFromDomainToWeb(user)
+	response.Achievements = api.userAchievements(r, user.UUID)
+	response.Online, response.InGame = api.userPresence(r, user.UUID)
 
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -134,6 +159,8 @@ func (api *AuthAPI) HandlerGetCurrentUser(w http.ResponseWriter, r *http.Request
 	}
 
 	response := mappers.UserFromDomainToWeb(user)
+	response.Achievements = api.userAchievements(r, user.UUID)
+	response.Online, response.InGame = api.userPresence(r, user.UUID)
 
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -141,36 +168,494 @@ func (api *AuthAPI) HandlerGetCurrentUser(w http.ResponseWriter, r *http.Request
 	}
 }
 
-// обновление аксес токена
+// обновление пары токенов с ротацией refresh токена
 func (api *AuthAPI) HandlerRefreshAccessToken(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	var req dto.RefreshJwtRequest
+	refreshToken, fromCookie, ok := api.refreshTokenFromRequest(w, r)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+
+	token, err := api.authServis.RotateRefreshToken(ctx, refreshToken)
+	if err != nil {
+		if errors.Is(err, auth.ErrTokenReused) {
+			http.Error(w, "Refresh token reuse detected, please log in again", http.StatusUnauthorized)
+		} else if errors.Is(err, auth.ErrUserBanned) {
+			http.Error(w, "User is banned", http.StatusForbidden)
+		} else {
+			http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		}
+		return
+	}
+	// токены из cookie возвращаются в cookie
+	writeTokens(w, api.cookies, fromCookie, token)
+}
+
+// выход: отзыв текущего refresh токена
+func (api *AuthAPI) HandlerLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	refreshToken, fromCookie, ok := api.refreshTokenFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if fromCookie {
+		api.cookies.ClearAuthCookies(w)
+	}
+
+	if err := api.authServis.Logout(r.Context(), refreshToken); err != nil {
+		if errors.Is(err, auth.ErrTokenInvalid) || errors.Is(err, auth.ErrTokenNotFound) {
+			http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		} else {
+			http.Error(w, "Failed to logout", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// выход со всех устройств
+func (api *AuthAPI) HandlerLogoutAll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	userUUID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := api.authServis.LogoutAll(ctx, userUUID); err != nil {
+		http.Error(w, "Failed to logout", http.StatusInternalServerError)
+		return
+	}
+	if api.cookies.Enabled {
+		api.cookies.ClearAuthCookies(w)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// список активных сессий
+func (api *AuthAPI) HandlerGetSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	userUUID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sessions, err := api.authServis.ListSessions(ctx, userUUID)
+	if err != nil {
+		http.Error(w, "Failed to fetch sessions", http.StatusInternalServerError)
+		return
+	}
+	response := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, mappers.SessionFromDBToWeb(session))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// отзыв одной сессии: DELETE /auth/sessions/{id}
+func (api *AuthAPI) HandlerRevokeSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	userUUID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/auth/sessions/"))
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := api.authServis.RevokeSession(ctx, userUUID, sessionID); err != nil {
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to revoke session", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// второй шаг входа с 2FA: challenge токен + код TOTP или код восстановления
+func (api *AuthAPI) HandlerTwoFactorLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req dto.TwoFactorLoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
-	if req.RefreshToken == "" {
-		http.Error(w, "refresh_token is required", http.StatusBadRequest)
+	if req.ChallengeToken == "" || req.Code == "" {
+		http.Error(w, "challenge_token and code are required", http.StatusBadRequest)
 		return
 	}
-	ctx := r.Context()
+	client := authModel.Client{
+		UserAgent: r.UserAgent(),
+		IP:        middleware.ClientIP(r),
+	}
 
-	token, err := api.authServis.RefreshAccess(ctx, req.RefreshToken)
+	token, err := api.authServis.LoginTwoFactor(r.Context(), req.ChallengeToken, req.Code, client)
 	if err != nil {
-		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		writeLoginError(w, err)
+		return
+	}
+	writeTokens(w, api.cookies, api.cookies.CookieMode(r), token)
+}
+
+// начало настройки 2FA: секрет и ссылка otpauth:// для приложения-аутентификатора
+func (api *AuthAPI) HandlerTwoFactorSetup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	secret, uri, err := api.authServis.SetupTwoFactor(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, auth.ErrTwoFactorEnabled) {
+			http.Error(w, "Two-factor authentication already enabled", http.StatusConflict)
+			return
+		}
+		log.Printf("Error setting up 2FA for user_id=%s: %v", userID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(token); err != nil {
+	if err := json.NewEncoder(w).Encode(dto.TwoFactorSetupResponse{Secret: secret, OtpauthURI: uri}); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// включение 2FA первым кодом из приложения; в ответе коды восстановления
+func (api *AuthAPI) HandlerTwoFactorEnable(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req dto.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	codes, err := api.authServis.EnableTwoFactor(r.Context(), userID, req.Code)
+	if err != nil {
+		writeTwoFactorError(w, userID, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(dto.RecoveryCodesResponse{RecoveryCodes: codes}); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// отключение 2FA по коду TOTP или коду восстановления
+func (api *AuthAPI) HandlerTwoFactorDisable(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req dto.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := api.authServis.DisableTwoFactor(r.Context(), userID, req.Code); err != nil {
+		writeTwoFactorError(w, userID, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// смена пароля авторизованным пользователем; все сессии завершаются
+func (api *AuthAPI) HandlerChangePassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req dto.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := api.authServis.ChangePassword(r.Context(), userID, req.OldPassword, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidCredentials):
+			http.Error(w, "Invalid old password", http.StatusForbidden)
+		case errors.Is(err, user.ErrValidationFailed):
+			http.Error(w, "New password must be at least 6 characters", http.StatusBadRequest)
+		default:
+			log.Printf("Error changing password for user_id=%s: %v", userID, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+	// refresh токены отозваны - cookie сессии больше не нужны
+	if api.cookies.Enabled {
+		api.cookies.ClearAuthCookies(w)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// запрос сброса пароля; ответ одинаков для существующих и несуществующих логинов
+func (api *AuthAPI) HandlerRequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req dto.PasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Login == "" {
+		http.Error(w, "login is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := api.authServis.RequestPasswordReset(r.Context(), req.Login); err != nil {
+		log.Printf("Error requesting password reset for login=%s: %v", req.Login, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// подтверждение сброса пароля одноразовым токеном
+func (api *AuthAPI) HandlerConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req dto.PasswordResetConfirmRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Token == "" {
+		http.Error(w, "token is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := api.authServis.ConfirmPasswordReset(r.Context(), req.Token, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrResetTokenInvalid):
+			http.Error(w, "Invalid or expired reset token", http.StatusBadRequest)
+		case errors.Is(err, user.ErrValidationFailed):
+			http.Error(w, "New password must be at least 6 characters", http.StatusBadRequest)
+		default:
+			log.Printf("Error confirming password reset: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// снятие блокировки входа по логину или IP (администрирование)
+func (api *AuthAPI) HandlerUnlockLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req dto.UnlockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	var scope authModel.AttemptScope
+	var key string
+	switch {
+	case req.Login != "" && req.IP == "":
+		scope, key = authModel.AttemptScopeLogin, req.Login
+	case req.IP != "" && req.Login == "":
+		scope, key = authModel.AttemptScopeIP, req.IP
+	default:
+		http.Error(w, "Exactly one of login or ip is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := api.authServis.Unlock(r.Context(), scope, key); err != nil {
+		if errors.Is(err, auth.ErrNotLocked) {
+			http.Error(w, "No failed login attempts recorded", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error unlocking %s=%s: %v", scope, key, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// публичные ключи проверки JWT (JWKS)
+func (api *AuthAPI) HandlerJWKS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	response := mappers.JWKSFromDomainToWeb(api.jwt.PublicKeys())
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		log.Printf("Error encoding response: %v", err)
 	}
 }
 
 // /////////////////////////////////////////////////////////////////////
+// ошибки входа (пароль или код 2FA)
+func writeLoginError(w http.ResponseWriter, err error) {
+	var throttled *auth.ThrottleError
+	switch {
+	case errors.As(err, &throttled):
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		if errors.Is(err, auth.ErrAccountLocked) {
+			http.Error(w, "Account temporarily locked", http.StatusLocked)
+		} else {
+			http.Error(w, "Too many login attempts", http.StatusTooManyRequests)
+		}
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+	case errors.Is(err, auth.ErrTokenInvalid), errors.Is(err, auth.ErrUserNotFound):
+		http.Error(w, "Invalid or expired challenge token", http.StatusUnauthorized)
+	case errors.Is(err, auth.ErrInvalidCode):
+		http.Error(w, "Invalid two-factor code", http.StatusUnauthorized)
+	case errors.Is(err, auth.ErrUserBanned):
+		http.Error(w, "User is banned", http.StatusForbidden)
+	default:
+		log.Printf("Error during login: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+// writeTokens отдаёт пару токенов в теле ответа или, в режиме cookie,
+// выставляет HttpOnly cookie и возвращает только CSRF токен
+func writeTokens(w http.ResponseWriter, cookies middleware.CookieConfig, cookieMode bool, tokens dto.JwtResponse) {
+	var response any = tokens
+	if cookieMode {
+		csrf := cookies.SetAuthCookies(w, tokens.AccessToken, tokens.RefreshToken, jwt.AccessTokenTTL, jwt.RefreshTokenTTL)
+		response = dto.CookieSessionResponse{
+			Type:      "Cookie",
+			CSRFToken: csrf,
+			ExpiresIn: int(jwt.AccessTokenTTL.Seconds()),
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// refreshTokenFromRequest берёт refresh токен из тела запроса или из cookie;
+// запрос с cookie должен пройти проверку CSRF
+func (api *AuthAPI) refreshTokenFromRequest(w http.ResponseWriter, r *http.Request) (string, bool, bool) {
+	var req dto.RefreshJwtRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return "", false, false
+	}
+	if req.RefreshToken != "" {
+		return req.RefreshToken, false, true
+	}
+	token, ok := api.cookies.RefreshTokenFromCookie(r)
+	if !ok {
+		http.Error(w, "refresh_token is required", http.StatusBadRequest)
+		return "", false, false
+	}
+	if !middleware.ValidCSRF(r) {
+		http.Error(w, "invalid CSRF token", http.StatusForbidden)
+		return "", false, false
+	}
+	return token, true, true
+}
+
+func writeTwoFactorError(w http.ResponseWriter, userID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, auth.ErrInvalidCode):
+		http.Error(w, "Invalid two-factor code", http.StatusBadRequest)
+	case errors.Is(err, auth.ErrTwoFactorNotSetUp):
+		http.Error(w, "Two-factor authentication is not set up", http.StatusConflict)
+	case errors.Is(err, auth.ErrTwoFactorEnabled):
+		http.Error(w, "Two-factor authentication already enabled", http.StatusConflict)
+	default:
+		log.Printf("Error updating 2FA for user_id=%s: %v", userID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+// достижения для профиля; при ошибке профиль отдаётся без них
+func (api *AuthAPI) userAchievements(r *http.Request, userID uuid.UUID) []dto.AchievementResponse {
+	achievements, err := api.achievements.GetUserAchievements(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching achievements for user_id=%s: %v", userID, err)
+		return nil
+	}
+	return mappers.AchievementsFromDomainToWeb(achievements, api.achievements.Catalog())
+}
+
+// userPresence возвращает флаги online/in_game; при ошибке - оба false
+func (api *AuthAPI) userPresence(r *http.Request, userID uuid.UUID) (online, inGame bool) {
+	p, err := api.presence.Get(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching presence for user_id=%s: %v", userID, err)
+		return false, false
+	}
+	return p.Online, p.InGame
+}
+
 func (api *AuthAPI) userUUIDFromPath(path string) (uuid.UUID, error) {
 	// Парсим путь: /user/{uuid}
 	cleanPath := strings.TrimPrefix(path, "/user/")
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	auth "tic-tac-toe/internal/service/auth_service"
+	dto "tic-tac-toe/internal/web/dto"
+	"tic-tac-toe/internal/web/middleware"
+
+	"github.com/google/uuid"
+)
+
+// rotatingAuth выдаёт новую пару токенов и запоминает предъявленный refresh токен
+type rotatingAuth struct {
+	auth.AuthService
+	got []string
+}
+
+func (a *rotatingAuth) RotateRefreshToken(ctx context.Context, refreshToken string) (dto.JwtResponse, error) {
+	a.got = append(a.got, refreshToken)
+	return dto.JwtResponse{Type: "Bearer", AccessToken: "new-access", RefreshToken: "new-refresh"}, nil
+}
+
+// sessionOwner - отзыв сессии удаётся только её владельцу
+type sessionOwner struct {
+	auth.AuthService
+	owner, session uuid.UUID
+}
+
+func (a *sessionOwner) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if userID != a.owner || sessionID != a.session {
+		return auth.ErrSessionNotFound
+	}
+	return nil
+}
+
+func serveRefresh(service auth.AuthService, r *http.Request) *httptest.ResponseRecorder {
+	api := &AuthAPI{authServis: service, cookies: middleware.CookieConfig{Enabled: true}}
+	w := httptest.NewRecorder()
+	api.HandlerRefreshAccessToken(w, r)
+	return w
+}
+
+func refreshCookieRequest(body, csrfHeader string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(body))
+	r.AddCookie(&http.Cookie{Name: middleware.RefreshTokenCookie, Value: "old-refresh"})
+	r.AddCookie(&http.Cookie{Name: middleware.CSRFCookie, Value: "csrf-1"})
+	if csrfHeader != "" {
+		r.Header.Set(middleware.CSRFHeader, csrfHeader)
+	}
+	return r
+}
+
+func TestRefreshFromCookie(t *testing.T) {
+	service := &rotatingAuth{}
+	w := serveRefresh(service, refreshCookieRequest("", "csrf-1"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if len(service.got) != 1 || service.got[0] != "old-refresh" {
+		t.Errorf("rotated tokens = %v, want [old-refresh]", service.got)
+	}
+
+	// обе cookie заменены новой парой, токены в тело не попадают
+	cookies := map[string]*http.Cookie{}
+	for _, c := range w.Result().Cookies() {
+		cookies[c.Name] = c
+	}
+	if c := cookies[middleware.AccessTokenCookie]; c == nil || c.Value != "new-access" || !c.HttpOnly {
+		t.Errorf("access cookie = %+v, want HttpOnly new-access", c)
+	}
+	if c := cookies[middleware.RefreshTokenCookie]; c == nil || c.Value != "new-refresh" || !c.HttpOnly || c.Path != "/auth" {
+		t.Errorf("refresh cookie = %+v, want HttpOnly new-refresh on /auth", c)
+	}
+	if c := cookies[middleware.CSRFCookie]; c == nil || c.Value == "" || c.Value == "csrf-1" {
+		t.Errorf("csrf cookie = %+v, want a new token", c)
+	}
+	if strings.Contains(w.Body.String(), "new-refresh") {
+		t.Errorf("body = %s, want no tokens in cookie mode", w.Body.String())
+	}
+}
+
+func TestRefreshFromCookieCSRF(t *testing.T) {
+	for name, header := range map[string]string{"missing": "", "mismatched": "csrf-2"} {
+		service := &rotatingAuth{}
+		w := serveRefresh(service, refreshCookieRequest("", header))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s CSRF header: status = %d, want 403", name, w.Code)
+		}
+		if len(service.got) != 0 {
+			t.Errorf("%s CSRF header: token rotated without CSRF check", name)
+		}
+	}
+}
+
+func TestRefreshFromBody(t *testing.T) {
+	// токен из тела важнее cookie и не требует CSRF; ответ - в теле, без cookie
+	service := &rotatingAuth{}
+	w := serveRefresh(service, refreshCookieRequest(`{"refresh_token":"body-refresh"}`, ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if len(service.got) != 1 || service.got[0] != "body-refresh" {
+		t.Errorf("rotated tokens = %v, want [body-refresh]", service.got)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Errorf("set cookies %v, want none for body token", w.Result().Cookies())
+	}
+	if !strings.Contains(w.Body.String(), `"refresh_token":"new-refresh"`) {
+		t.Errorf("body = %s, want the new refresh token", w.Body.String())
+	}
+}
+
+func TestRevokeSessionStatus(t *testing.T) {
+	service := &sessionOwner{owner: uuid.New(), session: uuid.New()}
+	api := &AuthAPI{authServis: service}
+
+	tests := []struct {
+		name   string
+		userID uuid.UUID
+		path   string
+		want   int
+	}{
+		{"own session", service.owner, "/auth/sessions/" + service.session.String(), http.StatusNoContent},
+		{"foreign session", uuid.New(), "/auth/sessions/" + service.session.String(), http.StatusNotFound},
+		{"invalid id", service.owner, "/auth/sessions/abc", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodDelete, tt.path, nil)
+		r = r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, tt.userID))
+		w := httptest.NewRecorder()
+		api.HandlerRevokeSession(w, r)
+		if w.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	service "tic-tac-toe/internal/service/user_service"
+	dto "tic-tac-toe/internal/web/dto"
+	"tic-tac-toe/internal/web/mappers"
+	"tic-tac-toe/internal/web/middleware"
+
+	"github.com/google/uuid"
+)
+
+type FriendAPI struct {
+	friendServis service.FriendService
+}
+
+func NewFriendAPI(servis service.FriendService) *FriendAPI {
+	return &FriendAPI{
+		friendServis: servis,
+	}
+}
+
+// список друзей
+func (api *FriendAPI) HandlerGetFriends(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	friends, err := api.friendServis.ListFriends(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching friends of %s: %v", userID, err)
+		http.Error(w, "Failed to fetch friends", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, mappers.FriendsFromDomainToWeb(friends))
+}
+
+// входящие и исходящие заявки
+func (api *FriendAPI) HandlerGetRequests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	requests, err := api.friendServis.ListRequests(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching friend requests of %s: %v", userID, err)
+		http.Error(w, "Failed to fetch friend requests", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, mappers.FriendRequestsFromDomainToWeb(requests))
+}
+
+// отправка заявки: POST /friends/requests/{uuid}
+func (api *FriendAPI) HandlerSendRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, friendID, ok := usersFromPath(w, r, "/friends/requests/", "")
+	if !ok {
+		return
+	}
+	status, err := api.friendServis.SendRequest(r.Context(), userID, friendID)
+	if err != nil {
+		writeFriendError(w, err)
+		return
+	}
+	writeJSON(w, dto.FriendshipStatusResponse{Status: string(status)})
+}
+
+// принятие заявки: POST /friends/requests/{uuid}/accept
+func (api *FriendAPI) HandlerAcceptRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, requesterID, ok := usersFromPath(w, r, "/friends/requests/", "/accept")
+	if !ok {
+		return
+	}
+	if err := api.friendServis.AcceptRequest(r.Context(), userID, requesterID); err != nil {
+		writeFriendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// отклонение заявки: POST /friends/requests/{uuid}/decline
+func (api *FriendAPI) HandlerDeclineRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, requesterID, ok := usersFromPath(w, r, "/friends/requests/", "/decline")
+	if !ok {
+		return
+	}
+	if err := api.friendServis.DeclineRequest(r.Context(), userID, requesterID); err != nil {
+		writeFriendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// удаление из друзей или отмена своей заявки: DELETE /friends/{uuid}
+func (api *FriendAPI) HandlerRemoveFriend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, friendID, ok := usersFromPath(w, r, "/friends/", "")
+	if !ok {
+		return
+	}
+	if err := api.friendServis.RemoveFriend(r.Context(), userID, friendID); err != nil {
+		writeFriendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// чёрный список
+func (api *FriendAPI) HandlerGetBlocked(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	blocked, err := api.friendServis.ListBlocked(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching block list of %s: %v", userID, err)
+		http.Error(w, "Failed to fetch block list", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, mappers.BlockedUsersFromDomainToWeb(blocked))
+}
+
+// блокировка и разблокировка: PUT|DELETE /blocks/{uuid}
+func (api *FriendAPI) HandlerBlock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, targetID, ok := usersFromPath(w, r, "/blocks/", "")
+	if !ok {
+		return
+	}
+	var err error
+	if r.Method == http.MethodPut {
+		err = api.friendServis.Block(r.Context(), userID, targetID)
+	} else {
+		err = api.friendServis.Unblock(r.Context(), userID, targetID)
+	}
+	if err != nil {
+		writeFriendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// usersFromPath возвращает текущего пользователя и UUID из пути prefix{uuid}suffix
+func usersFromPath(w http.ResponseWriter, r *http.Request, prefix, suffix string) (userID, targetID uuid.UUID, ok bool) {
+	userID, ok = middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return userID, targetID, false
+	}
+	targetID, err := uuid.Parse(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, prefix), suffix))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return userID, targetID, false
+	}
+	return userID, targetID, true
+}
+
+func writeFriendError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrCannotFriendSelf):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrFriendRequestAbsent),
+		errors.Is(err, service.ErrNotFriends), errors.Is(err, service.ErrNotBlocked):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrAlreadyFriends), errors.Is(err, service.ErrFriendRequestExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrUserBlocked):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Printf("Error handling friends request: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, response any) {
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	model "tic-tac-toe/internal/domain/model/game"
 	dsDto "tic-tac-toe/internal/storage/postgres/dto"
@@ -18,6 +19,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// количество последних игр в статистике личных встреч по умолчанию
+const defaultLastGames = 10
+
 type GameAPI struct {
 	gameServis service.GameServices
 }
@@ -57,11 +61,15 @@ func (api *GameAPI) HandlerMakeMove(w http.ResponseWriter, r *http.Request) {
 	// Вызываем сервис для обработки хода (вся бизнес-логика там)
 	updatedGame, err := api.gameServis.MakeMove(ctx, gameUUID, userID, newField)
 	if err != nil {
-		switch err {
-		case service.ErrNotYourTurn:
+		var conflict *model.ConflictError
+		switch {
+		case errors.Is(err, service.ErrNotYourTurn):
 			http.Error(w, err.Error(), http.StatusForbidden)
-		case service.ErrInvalidMove, service.ErrGameFinished:
+		case errors.Is(err, service.ErrInvalidMove), errors.Is(err, service.ErrGameFinished):
 			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.As(err, &conflict):
+			// игру изменил параллельный запрос: клиенту нужно перечитать её
+			http.Error(w, conflict.Error(), http.StatusConflict)
 		default:
 			http.Error(w, "Internal server error: ", http.StatusInternalServerError)
 		}
@@ -148,7 +156,14 @@ func (api *GameAPI) HandlerGetAvailableGames(w http.ResponseWriter, r *http.Requ
 	}
 
 	ctx := r.Context()
-	games, err := api.gameServis.GetAvailableGames(ctx)
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	friendsOnly := r.URL.Query().Get("friends") == "true"
+
+	games, err := api.gameServis.GetAvailableGames(ctx, userID, friendsOnly)
 	if err != nil {
 		http.Error(w, "Failed to fetch available games", http.StatusInternalServerError)
 		return
@@ -215,8 +230,14 @@ func (api *GameAPI) HandlerJoinGame(w http.ResponseWriter, r *http.Request) {
 
 	gameCurrent, err := api.gameServis.JoinGame(ctx, gameUUID, playerO)
 	if err != nil {
+		var conflict *model.ConflictError
 		if errors.Is(err, service.ErrGameFull) || errors.Is(err, service.ErrCannotJoinOwn) || errors.Is(err, service.ErrGameNotWaiting) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else if errors.Is(err, service.ErrPlayerBlocked) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+		} else if errors.As(err, &conflict) {
+			// к игре одновременно присоединился другой игрок
+			http.Error(w, conflict.Error(), http.StatusConflict)
 		} else {
 			http.Error(w, "Failed to join game", http.StatusInternalServerError)
 		}
@@ -291,7 +312,65 @@ func (api *GameAPI) HandlerGetLeaderBoard(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// статистика личных встреч: /user/{uuid}/vs/{otherUuid}?last=N
+func (api *GameAPI) HandlerGetHeadToHead(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	playerID, opponentID, err := api.headToHeadFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lastCount := defaultLastGames
+	if last := r.URL.Query().Get("last"); last != "" {
+		lastCount, err = strconv.Atoi(last)
+		if err != nil || lastCount <= 0 {
+			http.Error(w, "Invalid last parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	headToHead, err := api.gameServis.GetHeadToHead(ctx, playerID, opponentID, lastCount)
+	if err != nil {
+		if errors.Is(err, service.ErrSamePlayer) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to fetch head-to-head", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := webMappers.HeadToHeadFromDomainToWeb(headToHead)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
 // ////////////////////////////////////////////////////////////////////////
+func (api *GameAPI) headToHeadFromPath(path string) (uuid.UUID, uuid.UUID, error) {
+	// Парсим путь: /user/{uuid}/vs/{otherUuid}
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 4 || parts[0] != "user" || parts[2] != "vs" {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("Invalid path format")
+	}
+	playerID, err := uuid.Parse(parts[1])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("Invalid format UUID")
+	}
+	opponentID, err := uuid.Parse(parts[3])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("Invalid format UUID")
+	}
+	return playerID, opponentID, nil
+}
+
 func (api *GameAPI) gameUUIDFromPath(path string) (uuid.UUID, error) {
 	// Парсим путь: /game/{uuid}
 	cleanPath := strings.TrimPrefix(path, "/game/")
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	model "tic-tac-toe/internal/domain/model/moderation"
+	moderation "tic-tac-toe/internal/service/moderation_service"
+	userService "tic-tac-toe/internal/service/user_service"
+	dto "tic-tac-toe/internal/web/dto"
+	"tic-tac-toe/internal/web/mappers"
+	"tic-tac-toe/internal/web/middleware"
+
+	"github.com/google/uuid"
+)
+
+// размер журнала модерации по умолчанию и максимальный
+const (
+	defaultModerationLogLimit = 50
+	maxModerationLogLimit     = 500
+)
+
+type ModerationAPI struct {
+	moderationServis moderation.ModerationService
+}
+
+func NewModerationAPI(servis moderation.ModerationService) *ModerationAPI {
+	return &ModerationAPI{
+		moderationServis: servis,
+	}
+}
+
+// бан: POST|DELETE /admin/users/{uuid}/ban
+func (api *ModerationAPI) HandlerBan(w http.ResponseWriter, r *http.Request) {
+	api.handleSanction(w, r, model.SanctionBan)
+}
+
+// запрет писать в чат: POST|DELETE /admin/users/{uuid}/mute
+func (api *ModerationAPI) HandlerMute(w http.ResponseWriter, r *http.Request) {
+	api.handleSanction(w, r, model.SanctionMute)
+}
+
+func (api *ModerationAPI) handleSanction(w http.ResponseWriter, r *http.Request, kind model.SanctionKind) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	actor, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userPart := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/admin/users/"), "/"+string(kind))
+	userID, err := uuid.Parse(userPart)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	var req dto.SanctionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		if kind == model.SanctionBan {
+			err = api.moderationServis.Unban(ctx, actor, userID, req.Reason)
+		} else {
+			err = api.moderationServis.Unmute(ctx, actor, userID, req.Reason)
+		}
+		if err != nil {
+			writeModerationError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var sanction model.Sanction
+	if kind == model.SanctionBan {
+		sanction, err = api.moderationServis.Ban(ctx, actor, userID, req.Reason, req.ExpiresAt)
+	} else {
+		sanction, err = api.moderationServis.Mute(ctx, actor, userID, req.Reason, req.ExpiresAt)
+	}
+	if err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(mappers.SanctionFromDomainToWeb(sanction)); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// журнал модерации: GET /admin/moderation/log?user={uuid}&limit=N
+func (api *ModerationAPI) HandlerLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+
+	var target *uuid.UUID
+	if u := query.Get("user"); u != "" {
+		userID, err := uuid.Parse(u)
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+		target = &userID
+	}
+	limit := defaultModerationLogLimit
+	if l := query.Get("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit <= 0 || limit > maxModerationLogLimit {
+			http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	entries, err := api.moderationServis.Log(r.Context(), target, limit)
+	if err != nil {
+		log.Printf("Error fetching moderation log: %v", err)
+		http.Error(w, "Failed to fetch moderation log", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(mappers.ModerationLogFromDomainToWeb(entries)); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+func writeModerationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, moderation.ErrReasonRequired), errors.Is(err, moderation.ErrExpiryInPast):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, userService.ErrUserNotFound):
+		http.Error(w, "User not found", http.StatusNotFound)
+	case errors.Is(err, moderation.ErrNotSanctioned):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, moderation.ErrCannotModerateSelf):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("Error during moderation: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
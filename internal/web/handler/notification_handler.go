@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	service "tic-tac-toe/internal/service/notification_service"
+	dto "tic-tac-toe/internal/web/dto"
+	"tic-tac-toe/internal/web/mappers"
+	"tic-tac-toe/internal/web/middleware"
+)
+
+const (
+	// размер списка уведомлений по умолчанию и максимальный
+	defaultNotificationsLimit = 50
+	maxNotificationsLimit     = 200
+	// как часто в поток уведомлений пишется комментарий, чтобы прокси не закрывали соединение
+	streamKeepAlive = 25 * time.Second
+)
+
+type NotificationAPI struct {
+	notificationServis service.NotificationService
+}
+
+func NewNotificationAPI(servis service.NotificationService) *NotificationAPI {
+	return &NotificationAPI{
+		notificationServis: servis,
+	}
+}
+
+// уведомления: GET /notifications?unread=true&limit=N
+func (api *NotificationAPI) HandlerGetNotifications(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	query := r.URL.Query()
+	unreadOnly := query.Get("unread") == "true"
+	limit := defaultNotificationsLimit
+	if l := query.Get("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit <= 0 || limit > maxNotificationsLimit {
+			http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	notifications, err := api.notificationServis.List(r.Context(), userID, unreadOnly, limit)
+	if err != nil {
+		log.Printf("Error fetching notifications of %s: %v", userID, err)
+		http.Error(w, "Failed to fetch notifications", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, mappers.NotificationsFromDomainToWeb(notifications))
+}
+
+// отметка прочитанными: POST /notifications/read, без тела или без ids - все
+func (api *NotificationAPI) HandlerMarkRead(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req dto.MarkNotificationsReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	marked, err := api.notificationServis.MarkRead(r.Context(), userID, req.IDs)
+	if err != nil {
+		log.Printf("Error marking notifications of %s: %v", userID, err)
+		http.Error(w, "Failed to mark notifications", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, dto.MarkNotificationsReadResponse{Marked: marked})
+}
+
+// поток новых уведомлений (Server-Sent Events): GET /notifications/stream
+func (api *NotificationAPI) HandlerStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events, cancel := api.notificationServis.Subscribe(userID)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case notification := <-events:
+			data, err := json.Marshal(mappers.NotificationFromDomainToWeb(notification))
+			if err != nil {
+				log.Printf("Error encoding notification %s: %v", notification.UUID, err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: notification\ndata: %s\n\n", notification.UUID, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
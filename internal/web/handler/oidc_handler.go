@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	authModel "tic-tac-toe/internal/domain/model/auth"
+	auth "tic-tac-toe/internal/service/auth_service"
+	oidc "tic-tac-toe/internal/service/oidc_service"
+	"tic-tac-toe/internal/web/middleware"
+)
+
+type OIDCAPI struct {
+	oidcServis oidc.OIDCService
+	authServis auth.AuthService
+	cookies    middleware.CookieConfig
+}
+
+func NewOIDCAPI(oidcServis oidc.OIDCService, authServis auth.AuthService, cookies middleware.CookieConfig) *OIDCAPI {
+	return &OIDCAPI{
+		oidcServis: oidcServis,
+		authServis: authServis,
+		cookies:    cookies,
+	}
+}
+
+// начало входа через OIDC: редирект на страницу входа провайдера
+func (api *OIDCAPI) HandlerLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !api.oidcServis.Enabled() {
+		http.Error(w, "OIDC login is not configured", http.StatusNotFound)
+		return
+	}
+
+	authURL, state, err := api.oidcServis.AuthorizationURL(r.Context())
+	if err != nil {
+		log.Printf("Error starting OIDC login: %v", err)
+		http.Error(w, "Identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+	api.cookies.SetOIDCStateCookie(w, state, oidc.LoginStateTTL)
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// возврат от провайдера: обмен code на токены и вход (та же пара JWT, что у POST /auth)
+func (api *OIDCAPI) HandlerCallback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !api.oidcServis.Enabled() {
+		http.Error(w, "OIDC login is not configured", http.StatusNotFound)
+		return
+	}
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		http.Error(w, "Login rejected by identity provider: "+providerErr, http.StatusUnauthorized)
+		return
+	}
+	code, state := query.Get("code"), query.Get("state")
+	if code == "" || state == "" {
+		http.Error(w, "code and state are required", http.StatusBadRequest)
+		return
+	}
+	// state действует только в браузере, который начал вход (защита от login CSRF)
+	validState := middleware.ValidOIDCState(r, state)
+	api.cookies.ClearOIDCStateCookie(w)
+	if !validState {
+		http.Error(w, "Login was started in another browser, please start again", http.StatusBadRequest)
+		return
+	}
+
+	claims, err := api.oidcServis.Exchange(r.Context(), code, state)
+	if err != nil {
+		switch {
+		case errors.Is(err, oidc.ErrInvalidState):
+			http.Error(w, "Login session expired, please start again", http.StatusBadRequest)
+		case errors.Is(err, oidc.ErrInvalidToken):
+			log.Printf("Rejected OIDC id token: %v", err)
+			http.Error(w, "Invalid identity token", http.StatusUnauthorized)
+		default:
+			log.Printf("Error completing OIDC login: %v", err)
+			http.Error(w, "Identity provider unavailable", http.StatusBadGateway)
+		}
+		return
+	}
+
+	preferredLogin := claims.PreferredUsername
+	if preferredLogin == "" {
+		preferredLogin = claims.Email
+	}
+	client := authModel.Client{
+		UserAgent: r.UserAgent(),
+		IP:        middleware.ClientIP(r),
+	}
+	response, err := api.authServis.LoginExternal(r.Context(), claims.Issuer, claims.Subject, preferredLogin, client)
+	if err != nil {
+		if errors.Is(err, auth.ErrUserBanned) {
+			http.Error(w, "User is banned", http.StatusForbidden)
+			return
+		}
+		log.Printf("Error during OIDC login for %s/%s: %v", claims.Issuer, claims.Subject, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	// вход через браузер: при включённом режиме cookie токены не попадают в тело
+	if response.JwtResponse != nil {
+		writeTokens(w, api.cookies, api.cookies.Enabled, *response.JwtResponse)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	service "tic-tac-toe/internal/service/presence_service"
+	"tic-tac-toe/internal/web/mappers"
+)
+
+// размер списка пользователей в сети по умолчанию и максимальный
+const (
+	defaultOnlineLimit = 100
+	maxOnlineLimit     = 500
+)
+
+type PresenceAPI struct {
+	presenceServis service.PresenceService
+}
+
+func NewPresenceAPI(servis service.PresenceService) *PresenceAPI {
+	return &PresenceAPI{
+		presenceServis: servis,
+	}
+}
+
+// heartbeat клиента без других запросов; активность отмечает middleware.TrackPresence
+func (api *PresenceAPI) HandlerHeartbeat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// пользователи в сети: GET /users/online?limit=N
+func (api *PresenceAPI) HandlerGetOnline(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	limit := defaultOnlineLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit <= 0 || limit > maxOnlineLimit {
+			http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	online, err := api.presenceServis.ListOnline(r.Context(), limit)
+	if err != nil {
+		log.Printf("Error fetching online users: %v", err)
+		http.Error(w, "Failed to fetch online users", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, mappers.OnlineUsersFromDomainToWeb(online))
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	service "tic-tac-toe/internal/service/season_service"
+	dto "tic-tac-toe/internal/web/dto"
+	webMappers "tic-tac-toe/internal/web/mappers"
+
+	"github.com/google/uuid"
+)
+
+// размер таблицы сезона по умолчанию
+const defaultLeadersCount = 10
+
+type SeasonAPI struct {
+	seasonServis service.SeasonService
+}
+
+func NewSeasonAPI(servis service.SeasonService) *SeasonAPI {
+	return &SeasonAPI{
+		seasonServis: servis,
+	}
+}
+
+// список сезонов
+func (api *SeasonAPI) HandlerGetSeasons(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	seasons, err := api.seasonServis.List(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to fetch seasons", http.StatusInternalServerError)
+		return
+	}
+	response := make([]dto.SeasonResponse, 0, len(seasons))
+	for _, s := range seasons {
+		response = append(response, webMappers.SeasonFromDomainToWeb(s))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// таблица сезона: /seasons/{id|current}/leaderboard?count=N
+func (api *SeasonAPI) HandlerGetSeasonLeaderBoard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	seasonID, err := api.seasonUUIDFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	count := defaultLeadersCount
+	if c := r.URL.Query().Get("count"); c != "" {
+		count, err = strconv.Atoi(c)
+		if err != nil || count <= 0 {
+			http.Error(w, "Invalid count parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	leaders, err := api.seasonServis.GetLeaderBoard(ctx, seasonID, count)
+	if err != nil {
+		if errors.Is(err, service.ErrSeasonNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to fetch leaderboard", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := make([]dto.LeaderResponse, 0, len(leaders))
+	for _, l := range leaders {
+		response = append(response, webMappers.LeaderFromDomainToWeb(l))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////
+func (api *SeasonAPI) seasonUUIDFromPath(r *http.Request) (uuid.UUID, error) {
+	// Парсим путь: /seasons/{id}/leaderboard
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 {
+		return uuid.Nil, fmt.Errorf("Invalid path format")
+	}
+	if parts[1] == "current" {
+		season, err := api.seasonServis.Current(r.Context())
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("Current season not available")
+		}
+		return season.UUID, nil
+	}
+
+	parseUUID, err := uuid.Parse(parts[1])
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("Invalid format UUID")
+	}
+	return parseUUID, nil
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	service "tic-tac-toe/internal/service/season_service"
+
+	"github.com/google/uuid"
+)
+
+// fixedLeaders отдаёт одну и ту же таблицу для любого сезона
+type fixedLeaders struct {
+	service.SeasonService
+	leaders []gameModel.UserLeaders
+}
+
+func (s fixedLeaders) GetLeaderBoard(ctx context.Context, seasonID uuid.UUID, count int) ([]gameModel.UserLeaders, error) {
+	return s.leaders, nil
+}
+
+func TestSeasonLeaderBoardJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		leaders []gameModel.UserLeaders
+		want    string
+	}{
+		{
+			name: "leaders",
+			leaders: []gameModel.UserLeaders{
+				{Login: "alice", UserId: uuid.MustParse("00000000-0000-0000-0000-000000000001"), WinRate: "75.00"},
+			},
+			want: `[{"login":"alice","uuid":"00000000-0000-0000-0000-000000000001","win_rate":"75.00"}]` + "\n",
+		},
+		// пустой сезон - пустой массив, а не null
+		{name: "empty season", want: "[]\n"},
+	}
+	for _, tt := range tests {
+		api := NewSeasonAPI(fixedLeaders{leaders: tt.leaders})
+		r := httptest.NewRequest(http.MethodGet, "/seasons/"+uuid.NewString()+"/leaderboard", nil)
+		w := httptest.NewRecorder()
+		api.HandlerGetSeasonLeaderBoard(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200", tt.name, w.Code)
+		}
+		if w.Body.String() != tt.want {
+			t.Errorf("%s: body = %s, want %s", tt.name, w.Body.String(), tt.want)
+		}
+	}
+}
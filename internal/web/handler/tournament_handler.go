@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	model "tic-tac-toe/internal/domain/model/tournament"
+	service "tic-tac-toe/internal/service/tournament_service"
+	dto "tic-tac-toe/internal/web/dto"
+	webMappers "tic-tac-toe/internal/web/mappers"
+	"tic-tac-toe/internal/web/middleware"
+
+	"github.com/google/uuid"
+)
+
+type TournamentAPI struct {
+	tournamentServis service.TournamentService
+}
+
+func NewTournamentAPI(servis service.TournamentService) *TournamentAPI {
+	return &TournamentAPI{
+		tournamentServis: servis,
+	}
+}
+
+// создание турнира
+func (api *TournamentAPI) HandlerNewTournament(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.NewTournamentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	tournament, err := api.tournamentServis.Create(ctx, userID, req.Name, model.Format(req.Format), req.Rounds)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidName) || errors.Is(err, service.ErrUnknownFormat) || errors.Is(err, service.ErrInvalidRounds) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to create tournament", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := webMappers.TournamentFromDomainToWeb(tournament, nil)
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// список турниров
+func (api *TournamentAPI) HandlerGetTournaments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tournaments, err := api.tournamentServis.List(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to fetch tournaments", http.StatusInternalServerError)
+		return
+	}
+	response := make([]dto.TournamentResponse, 0, len(tournaments))
+	for _, t := range tournaments {
+		response = append(response, webMappers.TournamentFromDomainToWeb(t, nil))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// информация о турнире с участниками
+func (api *TournamentAPI) HandlerGetTournament(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tournamentID, err := api.tournamentUUIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tournament, participants, err := api.tournamentServis.Get(r.Context(), tournamentID)
+	if err != nil {
+		api.writeError(w, err)
+		return
+	}
+
+	response := webMappers.TournamentFromDomainToWeb(tournament, participants)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// регистрация в турнире
+func (api *TournamentAPI) HandlerJoinTournament(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	tournamentID, err := api.tournamentUUIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := api.tournamentServis.Join(ctx, tournamentID, userID); err != nil {
+		api.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// старт турнира владельцем
+func (api *TournamentAPI) HandlerStartTournament(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	tournamentID, err := api.tournamentUUIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	tournament, err := api.tournamentServis.Start(ctx, tournamentID, userID)
+	if err != nil {
+		api.writeError(w, err)
+		return
+	}
+
+	response := webMappers.TournamentFromDomainToWeb(tournament, nil)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// турнирная таблица
+func (api *TournamentAPI) HandlerGetStandings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tournamentID, err := api.tournamentUUIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	standings, err := api.tournamentServis.GetStandings(r.Context(), tournamentID)
+	if err != nil {
+		api.writeError(w, err)
+		return
+	}
+
+	response := webMappers.StandingsFromDomainToWeb(standings)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// сетка/расписание по турам
+func (api *TournamentAPI) HandlerGetBracket(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tournamentID, err := api.tournamentUUIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rounds, err := api.tournamentServis.GetBracket(r.Context(), tournamentID)
+	if err != nil {
+		api.writeError(w, err)
+		return
+	}
+
+	response := webMappers.BracketFromDomainToWeb(rounds)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////
+func (api *TournamentAPI) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrTournamentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrNotOwner):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrNotRegistration),
+		errors.Is(err, service.ErrAlreadyJoined),
+		errors.Is(err, service.ErrNotEnoughPlayers):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (api *TournamentAPI) tournamentUUIDFromPath(path string) (uuid.UUID, error) {
+	// Парсим путь: /tournament/{uuid}[/action]
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 {
+		return uuid.Nil, fmt.Errorf("Invalid path format")
+	}
+
+	parseUUID, err := uuid.Parse(parts[1])
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("Invalid format UUID")
+	}
+	return parseUUID, nil
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	gameModel "tic-tac-toe/internal/domain/model/game"
+	userModel "tic-tac-toe/internal/domain/model/user"
+	service "tic-tac-toe/internal/service/webhook_service"
+	dto "tic-tac-toe/internal/web/dto"
+	"tic-tac-toe/internal/web/mappers"
+	"tic-tac-toe/internal/web/middleware"
+
+	"github.com/google/uuid"
+)
+
+// размер журнала доставки по умолчанию и максимальный
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 200
+)
+
+type WebhookAPI struct {
+	webhookServis service.WebhookService
+}
+
+func NewWebhookAPI(servis service.WebhookService) *WebhookAPI {
+	return &WebhookAPI{
+		webhookServis: servis,
+	}
+}
+
+// вебхуки пользователя
+func (api *WebhookAPI) HandlerGetWebhooks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	webhooks, err := api.webhookServis.List(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching webhooks of %s: %v", userID, err)
+		http.Error(w, "Failed to fetch webhooks", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, mappers.WebhooksFromDomainToWeb(webhooks))
+}
+
+// регистрация вебхука; ключ подписи возвращается только в этом ответе
+func (api *WebhookAPI) HandlerCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req dto.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	events := make([]gameModel.EventType, 0, len(req.Events))
+	for _, e := range req.Events {
+		events = append(events, gameModel.EventType(e))
+	}
+	isAdmin := middleware.HasRole(r.Context(), string(userModel.RoleAdmin))
+
+	webhook, err := api.webhookServis.Create(r.Context(), userID, isAdmin, req.URL, events, req.AllGames)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	response := mappers.WebhookFromDomainToWeb(webhook)
+	response.Secret = webhook.Secret
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// удаление вебхука: DELETE /webhooks/{uuid}
+func (api *WebhookAPI) HandlerDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, webhookID, ok := webhookFromPath(w, r, "")
+	if !ok {
+		return
+	}
+	if err := api.webhookServis.Delete(r.Context(), webhookID, userID); err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// журнал доставки: GET /webhooks/{uuid}/deliveries?limit=N
+func (api *WebhookAPI) HandlerGetDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, webhookID, ok := webhookFromPath(w, r, "/deliveries")
+	if !ok {
+		return
+	}
+	limit := defaultDeliveriesLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit <= 0 || limit > maxDeliveriesLimit {
+			http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	deliveries, err := api.webhookServis.Deliveries(r.Context(), webhookID, userID, limit)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+	writeJSON(w, mappers.WebhookDeliveriesFromDomainToWeb(deliveries))
+}
+
+func webhookFromPath(w http.ResponseWriter, r *http.Request, suffix string) (userID, webhookID uuid.UUID, ok bool) {
+	userID, ok = middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return userID, webhookID, false
+	}
+	webhookID, err := uuid.Parse(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/webhooks/"), suffix))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return userID, webhookID, false
+	}
+	return userID, webhookID, true
+}
+
+func writeWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidURL), errors.Is(err, service.ErrInvalidEvents):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrAllGamesForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrWebhookNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrTooManyWebhooks):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("Error handling webhooks request: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,26 @@
+package mappers
+
+import (
+	model "tic-tac-toe/internal/domain/model/achievement"
+	dto "tic-tac-toe/internal/web/dto"
+)
+
+// достижения пользователя с описанием из каталога
+func AchievementsFromDomainToWeb(achievements []model.UserAchievement, catalog []model.Achievement) []dto.AchievementResponse {
+	byCode := make(map[model.Code]model.Achievement, len(catalog))
+	for _, a := range catalog {
+		byCode[a.Code] = a
+	}
+
+	response := make([]dto.AchievementResponse, 0, len(achievements))
+	for _, a := range achievements {
+		info := byCode[a.Code]
+		response = append(response, dto.AchievementResponse{
+			Code:        string(a.Code),
+			Title:       info.Title,
+			Description: info.Description,
+			UnlockedAt:  a.UnlockedAt,
+		})
+	}
+	return response
+}
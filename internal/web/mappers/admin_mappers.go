@@ -0,0 +1,20 @@
+package mappers
+
+import (
+	model "tic-tac-toe/internal/domain/model/admin"
+	dto "tic-tac-toe/internal/web/dto"
+)
+
+func SystemStatsFromDomainToWeb(s model.SystemStats) dto.SystemStatsResponse {
+	return dto.SystemStatsResponse{
+		Users:          s.Users,
+		Admins:         s.Admins,
+		GamesWaiting:   s.GamesWaiting,
+		GamesPlaying:   s.GamesPlaying,
+		GamesFinished:  s.GamesFinished,
+		ActiveSessions: s.ActiveSessions,
+		Tournaments:    s.Tournaments,
+		LockedLogins:   s.LockedLogins,
+		CollectedAt:    s.CollectedAt,
+	}
+}
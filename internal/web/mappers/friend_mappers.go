@@ -0,0 +1,47 @@
+package mappers
+
+import (
+	model "tic-tac-toe/internal/domain/model/user"
+	dto "tic-tac-toe/internal/web/dto"
+)
+
+func FriendsFromDomainToWeb(friends []model.Friend) []dto.FriendResponse {
+	response := make([]dto.FriendResponse, 0, len(friends))
+	for _, f := range friends {
+		response = append(response, dto.FriendResponse{
+			UUID:  f.UserID,
+			Login: f.Login,
+			Since: f.Since,
+		})
+	}
+	return response
+}
+
+func FriendRequestsFromDomainToWeb(requests []model.FriendRequest) []dto.FriendRequestResponse {
+	response := make([]dto.FriendRequestResponse, 0, len(requests))
+	for _, r := range requests {
+		direction := "outgoing"
+		if r.Incoming {
+			direction = "incoming"
+		}
+		response = append(response, dto.FriendRequestResponse{
+			UUID:      r.UserID,
+			Login:     r.Login,
+			Direction: direction,
+			CreatedAt: r.CreatedAt,
+		})
+	}
+	return response
+}
+
+func BlockedUsersFromDomainToWeb(blocked []model.BlockedUser) []dto.BlockedUserResponse {
+	response := make([]dto.BlockedUserResponse, 0, len(blocked))
+	for _, b := range blocked {
+		response = append(response, dto.BlockedUserResponse{
+			UUID:      b.UserID,
+			Login:     b.Login,
+			CreatedAt: b.CreatedAt,
+		})
+	}
+	return response
+}
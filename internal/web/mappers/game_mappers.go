@@ -32,6 +32,35 @@ func CurrentGameFromDomainToWeb(model model.Game, status model.GameStatus) dto.G
 	}
 }
 
+func HeadToHeadFromDomainToWeb(h model.HeadToHead) dto.HeadToHeadResponse {
+	lastGames := make([]dto.GameResponse, 0, len(h.LastGames))
+	for _, game := range h.LastGames {
+		lastGames = append(lastGames, CurrentGameFromDomainToWeb(game, game.Status))
+	}
+	return dto.HeadToHeadResponse{
+		PlayerID:     h.PlayerID,
+		OpponentID:   h.OpponentID,
+		Total:        h.Total,
+		PlayerWins:   h.PlayerWins,
+		OpponentWins: h.OpponentWins,
+		Draws:        h.Draws,
+		LastGames:    lastGames,
+		Streak: dto.StreakResponse{
+			Winner: h.Streak.Winner,
+			Count:  h.Streak.Count,
+		},
+	}
+}
+
+// строка таблицы лидеров
+func LeaderFromDomainToWeb(l model.UserLeaders) dto.LeaderResponse {
+	return dto.LeaderResponse{
+		Login:   l.Login,
+		UserId:  l.UserId,
+		WinRate: l.WinRate,
+	}
+}
+
 // func CurrentGameFromDomainToWeb(model model.UserLeaders) dto.GameResponse {
 // 	return dto.GameResponse{
 // 		UUID:  model.UUID,
@@ -57,6 +86,8 @@ func stringStatus(status model.GameStatus) string {
 		return "won_X"
 	case model.Draw:
 		return "draw"
+	case model.Cancelled:
+		return "cancelled"
 	default:
 		return "unknown"
 	}
@@ -0,0 +1,40 @@
+package mappers
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+
+	jwt "tic-tac-toe/internal/service/jwt_service"
+	dto "tic-tac-toe/internal/web/dto"
+)
+
+func JWKSFromDomainToWeb(keys []jwt.Key) dto.JWKSResponse {
+	result := dto.JWKSResponse{Keys: make([]dto.JWKResponse, 0, len(keys))}
+	for _, key := range keys {
+		jwk := dto.JWKResponse{
+			Kid: key.ID,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+		}
+		switch public := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64URL(public.N.Bytes())
+			jwk.E = base64URL(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64URL(public)
+		default:
+			continue
+		}
+		result.Keys = append(result.Keys, jwk)
+	}
+	return result
+}
+
+func base64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
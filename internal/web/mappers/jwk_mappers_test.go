@@ -0,0 +1,49 @@
+package mappers
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	jwt "tic-tac-toe/internal/service/jwt_service"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+func TestJWKSFromDomainToWeb(t *testing.T) {
+	// тестовый ключ из RFC 8037, приложение A
+	seed, _ := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	edKey := ed25519.NewKeyFromSeed(seed).Public()
+	// модуль 0xC0FFEE взят для краткости: форма ответа не зависит от размера ключа
+	rsaKey := &rsa.PublicKey{N: big.NewInt(0xC0FFEE), E: 65537}
+
+	response := JWKSFromDomainToWeb([]jwt.Key{
+		{ID: "rsa-1", Method: gojwt.SigningMethodRS256, Public: rsaKey},
+		{ID: "ed-1", Method: gojwt.SigningMethodEdDSA, Public: edKey},
+	})
+	got, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	want := `{"keys":[` +
+		`{"kty":"RSA","kid":"rsa-1","use":"sig","alg":"RS256","n":"wP_u","e":"AQAB"},` +
+		`{"kty":"OKP","kid":"ed-1","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}` +
+		`]}`
+	if string(got) != want {
+		t.Errorf("JWKS =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJWKSEmpty(t *testing.T) {
+	// HS256: ключей для публикации нет, но список остаётся массивом
+	got, err := json.Marshal(JWKSFromDomainToWeb(nil))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(got) != `{"keys":[]}` {
+		t.Errorf("JWKS = %s, want empty keys array", got)
+	}
+}
@@ -0,0 +1,33 @@
+package mappers
+
+import (
+	model "tic-tac-toe/internal/domain/model/moderation"
+	dto "tic-tac-toe/internal/web/dto"
+)
+
+func SanctionFromDomainToWeb(s model.Sanction) dto.SanctionResponse {
+	return dto.SanctionResponse{
+		UserID:    s.UserID,
+		Kind:      string(s.Kind),
+		Reason:    s.Reason,
+		CreatedBy: s.CreatedBy,
+		CreatedAt: s.CreatedAt,
+		ExpiresAt: s.ExpiresAt,
+	}
+}
+
+func ModerationLogFromDomainToWeb(entries []model.LogEntry) []dto.ModerationLogEntryResponse {
+	response := make([]dto.ModerationLogEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		response = append(response, dto.ModerationLogEntryResponse{
+			UUID:      e.UUID,
+			ActorID:   e.ActorID,
+			TargetID:  e.TargetID,
+			Action:    string(e.Action),
+			Reason:    e.Reason,
+			ExpiresAt: e.ExpiresAt,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	return response
+}
@@ -0,0 +1,24 @@
+package mappers
+
+import (
+	model "tic-tac-toe/internal/domain/model/notification"
+	dto "tic-tac-toe/internal/web/dto"
+)
+
+func NotificationFromDomainToWeb(n model.Notification) dto.NotificationResponse {
+	return dto.NotificationResponse{
+		UUID:      n.UUID,
+		Kind:      string(n.Kind),
+		Data:      n.Data,
+		Read:      n.Read(),
+		CreatedAt: n.CreatedAt,
+	}
+}
+
+func NotificationsFromDomainToWeb(notifications []model.Notification) []dto.NotificationResponse {
+	response := make([]dto.NotificationResponse, 0, len(notifications))
+	for _, n := range notifications {
+		response = append(response, NotificationFromDomainToWeb(n))
+	}
+	return response
+}
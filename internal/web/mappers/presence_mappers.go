@@ -0,0 +1,19 @@
+package mappers
+
+import (
+	model "tic-tac-toe/internal/domain/model/presence"
+	dto "tic-tac-toe/internal/web/dto"
+)
+
+func OnlineUsersFromDomainToWeb(online []model.Presence) []dto.PresenceResponse {
+	response := make([]dto.PresenceResponse, 0, len(online))
+	for _, p := range online {
+		response = append(response, dto.PresenceResponse{
+			UUID:     p.UserID,
+			Login:    p.Login,
+			InGame:   p.InGame,
+			LastSeen: p.LastSeen,
+		})
+	}
+	return response
+}
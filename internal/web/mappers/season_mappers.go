@@ -0,0 +1,20 @@
+package mappers
+
+import (
+	model "tic-tac-toe/internal/domain/model/season"
+	dto "tic-tac-toe/internal/web/dto"
+)
+
+func SeasonFromDomainToWeb(s model.Season) dto.SeasonResponse {
+	status := "active"
+	if s.Status == model.Archived {
+		status = "archived"
+	}
+	return dto.SeasonResponse{
+		UUID:     s.UUID,
+		Name:     s.Name,
+		StartsAt: s.StartsAt,
+		EndsAt:   s.EndsAt,
+		Status:   status,
+	}
+}
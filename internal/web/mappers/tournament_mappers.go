@@ -0,0 +1,93 @@
+package mappers
+
+import (
+	model "tic-tac-toe/internal/domain/model/tournament"
+	dto "tic-tac-toe/internal/web/dto"
+
+	"github.com/google/uuid"
+)
+
+func TournamentFromDomainToWeb(t model.Tournament, participants []model.Participant) dto.TournamentResponse {
+	var ids []uuid.UUID
+	for _, p := range participants {
+		ids = append(ids, p.UserID)
+	}
+	return dto.TournamentResponse{
+		UUID:         t.UUID,
+		Name:         t.Name,
+		Format:       string(t.Format),
+		Status:       tournamentStatus(t.Status),
+		OwnerID:      t.OwnerID,
+		CurrentRound: t.CurrentRound,
+		Rounds:       t.Rounds,
+		CreatedAt:    t.CreatedAt,
+		Participants: ids,
+	}
+}
+
+func StandingsFromDomainToWeb(standings []model.Standing) []dto.StandingResponse {
+	response := make([]dto.StandingResponse, 0, len(standings))
+	for i, s := range standings {
+		response = append(response, dto.StandingResponse{
+			Place:    i + 1,
+			UserID:   s.UserID,
+			Played:   s.Played,
+			Wins:     s.Wins,
+			Draws:    s.Draws,
+			Losses:   s.Losses,
+			Byes:     s.Byes,
+			Points:   s.Points,
+			Buchholz: s.Buchholz,
+		})
+	}
+	return response
+}
+
+func BracketFromDomainToWeb(rounds [][]model.Pairing) []dto.RoundResponse {
+	response := make([]dto.RoundResponse, 0, len(rounds))
+	for i, pairings := range rounds {
+		round := dto.RoundResponse{Round: i + 1, Pairings: make([]dto.PairingResponse, 0, len(pairings))}
+		for _, p := range pairings {
+			round.Pairings = append(round.Pairings, dto.PairingResponse{
+				Board:   p.Board,
+				PlayerX: p.PlayerX,
+				PlayerO: p.PlayerO,
+				GameID:  p.GameID,
+				Result:  pairingResult(p.Result),
+				Winner:  p.Winner(),
+			})
+		}
+		response = append(response, round)
+	}
+	return response
+}
+
+func tournamentStatus(status model.Status) string {
+	switch status {
+	case model.Registration:
+		return "registration"
+	case model.Running:
+		return "running"
+	case model.Finished:
+		return "finished"
+	default:
+		return "unknown"
+	}
+}
+
+func pairingResult(result model.Result) string {
+	switch result {
+	case model.Pending:
+		return "pending"
+	case model.WonX:
+		return "won_X"
+	case model.WonO:
+		return "won_O"
+	case model.Draw:
+		return "draw"
+	case model.Bye:
+		return "bye"
+	default:
+		return "unknown"
+	}
+}
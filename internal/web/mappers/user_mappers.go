@@ -2,6 +2,7 @@ package mappers
 
 import (
 	model "tic-tac-toe/internal/domain/model/user"
+	db "tic-tac-toe/internal/storage/postgres/dto"
 	dto "tic-tac-toe/internal/web/dto"
 )
 
@@ -10,6 +11,7 @@ func UserFromDomainToWeb(u model.User) dto.UserResponse {
 	return dto.UserResponse{
 		UUID:  u.UUID,
 		Login: u.Login,
+		Roles: u.RoleNames(),
 	}
 }
 
@@ -20,3 +22,14 @@ func UserFromWebToDomain(dbU dto.UserResponse) model.User {
 		// Password не передается из DTO по соображениям безопасности
 	}
 }
+
+func SessionFromDBToWeb(t db.RefreshToken) dto.SessionResponse {
+	return dto.SessionResponse{
+		ID:         t.FamilyID,
+		CreatedAt:  t.CreatedAt,
+		LastUsedAt: t.LastUsedAt,
+		ExpiresAt:  t.ExpiresAt,
+		UserAgent:  t.UserAgent,
+		IP:         t.IP,
+	}
+}
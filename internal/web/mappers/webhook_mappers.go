@@ -0,0 +1,51 @@
+package mappers
+
+import (
+	model "tic-tac-toe/internal/domain/model/webhook"
+	dto "tic-tac-toe/internal/web/dto"
+)
+
+func WebhookFromDomainToWeb(w model.Webhook) dto.WebhookResponse {
+	events := make([]string, 0, len(w.Events))
+	for _, e := range w.Events {
+		events = append(events, string(e))
+	}
+	return dto.WebhookResponse{
+		UUID:      w.UUID,
+		URL:       w.URL,
+		Events:    events,
+		AllGames:  w.AllGames,
+		CreatedAt: w.CreatedAt,
+	}
+}
+
+func WebhooksFromDomainToWeb(webhooks []model.Webhook) []dto.WebhookResponse {
+	response := make([]dto.WebhookResponse, 0, len(webhooks))
+	for _, w := range webhooks {
+		response = append(response, WebhookFromDomainToWeb(w))
+	}
+	return response
+}
+
+func WebhookDeliveriesFromDomainToWeb(deliveries []model.Delivery) []dto.WebhookDeliveryResponse {
+	response := make([]dto.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		delivery := dto.WebhookDeliveryResponse{
+			UUID:         d.UUID,
+			EventID:      d.EventID,
+			Event:        string(d.Event),
+			Status:       string(d.Status),
+			Attempts:     d.Attempts,
+			ResponseCode: d.ResponseCode,
+			LastError:    d.LastError,
+			CreatedAt:    d.CreatedAt,
+			DeliveredAt:  d.DeliveredAt,
+		}
+		if d.Status == model.DeliveryPending {
+			next := d.NextAttemptAt
+			delivery.NextAttemptAt = &next
+		}
+		response = append(response, delivery)
+	}
+	return response
+}
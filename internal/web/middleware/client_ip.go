@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ключ адреса клиента, определённого RealIP
+const clientIPKey contextKey = "clientIP"
+
+// TrustedProxies - сети прокси, которым разрешено передавать адрес клиента
+// в X-Forwarded-For. Пустой список - заголовок не учитывается
+type TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies разбирает список сетей в формате CIDR;
+// одиночный адрес означает сеть из одного адреса
+func ParseTrustedProxies(values []string) (TrustedProxies, error) {
+	proxies := make(TrustedProxies, 0, len(values))
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, fmt.Errorf("неверный адрес прокси %q: %w", value, err)
+			}
+			proxies = append(proxies, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("неверная сеть прокси %q: %w", value, err)
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+func (p TrustedProxies) trusted(addr netip.Addr) bool {
+	for _, prefix := range p {
+		if prefix.Contains(addr.Unmap()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve определяет адрес клиента. X-Forwarded-For учитывается, только если
+// соединение пришло от доверенного прокси: цепочка проходится справа налево,
+// доверенные прокси пропускаются, и первый недоверенный адрес считается клиентом.
+// Адреса левее него мог подставить сам клиент
+func (p TrustedProxies) Resolve(r *http.Request) string {
+	remote := remoteIP(r)
+	addr, err := netip.ParseAddr(remote)
+	if err != nil || !p.trusted(addr) {
+		return remote
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for hop := range strings.SplitSeq(header, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(hops[i])
+		if err != nil {
+			// дальше цепочке верить нельзя - клиентом считается последний прокси
+			break
+		}
+		client = hop.Unmap().String()
+		if !p.trusted(hop) {
+			break
+		}
+	}
+	return client
+}
+
+// RealIP сохраняет в контексте адрес клиента для ClientIP;
+// оборачивает весь обработчик сервера
+func RealIP(proxies TrustedProxies) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), clientIPKey, proxies.Resolve(r))
+			next(w, r.WithContext(ctx))
+		}
+	}
+}
+
+// ClientIP возвращает адрес клиента, определённый RealIP,
+// или адрес соединения, если RealIP не применялся
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey).(string); ok {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrustedProxiesResolve(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{"no header", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"untrusted peer sends header", "203.0.113.7:5000", []string{"1.2.3.4"}, "203.0.113.7"},
+		{"trusted proxy", "10.0.0.2:5000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"spoofed left entries ignored", "10.0.0.2:5000", []string{"1.1.1.1, 2.2.2.2, 198.51.100.1"}, "198.51.100.1"},
+		{"chain of trusted proxies", "10.0.0.2:5000", []string{"198.51.100.1, 192.168.1.1, 10.1.2.3"}, "198.51.100.1"},
+		{"several headers", "10.0.0.2:5000", []string{"1.1.1.1", "198.51.100.1"}, "198.51.100.1"},
+		{"all hops trusted", "10.0.0.2:5000", []string{"10.0.0.5"}, "10.0.0.5"},
+		{"garbage hop", "10.0.0.2:5000", []string{"198.51.100.1, not-an-ip"}, "10.0.0.2"},
+		{"single address is not a network", "192.168.1.2:5000", []string{"1.2.3.4"}, "192.168.1.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if got := proxies.Resolve(r); got != tt.want {
+				t.Errorf("Resolve = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIPWithoutTrustedProxies(t *testing.T) {
+	var got string
+	handler := RealIP(nil)(func(w http.ResponseWriter, r *http.Request) {
+		got = ClientIP(r)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "203.0.113.7:5000"
+	r.Header.Set("X-Forwarded-For", "1.2.3.4")
+	handler(httptest.NewRecorder(), r)
+
+	if got != "203.0.113.7" {
+		t.Errorf("ClientIP = %q, want connection address", got)
+	}
+}
+
+func TestParseTrustedProxiesInvalid(t *testing.T) {
+	for _, value := range []string{"10.0.0.0/33", "proxy.local"} {
+		if _, err := ParseTrustedProxies([]string{value}); err == nil {
+			t.Errorf("ParseTrustedProxies(%q): want error", value)
+		}
+	}
+}
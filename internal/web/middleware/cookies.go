@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"time"
+)
+
+// имена cookie и заголовков режима авторизации через cookie
+const (
+	AccessTokenCookie  = "access_token"
+	RefreshTokenCookie = "refresh_token"
+	CSRFCookie         = "csrf_token"
+	CSRFHeader         = "X-CSRF-Token"
+	// клиент выбирает режим cookie этим заголовком при входе
+	AuthModeHeader = "X-Auth-Mode"
+	AuthModeCookie = "cookie"
+	// refresh cookie отправляется только на эндпоинты /auth/...
+	refreshCookiePath = "/auth"
+	// хэш state входа через OIDC, отправляется только на /auth/oidc/...
+	OIDCStateCookie     = "oidc_state"
+	oidcStateCookiePath = "/auth/oidc"
+)
+
+// настройки авторизации через HttpOnly cookie
+type CookieConfig struct {
+	Enabled  bool
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// CookieMode - запрос входа просит выдать токены в cookie
+func (c CookieConfig) CookieMode(r *http.Request) bool {
+	return c.Enabled && r.Header.Get(AuthModeHeader) == AuthModeCookie
+}
+
+// SetAuthCookies выставляет токены в HttpOnly cookie и новый CSRF токен
+// (его cookie читается JS и возвращается в заголовке X-CSRF-Token)
+func (c CookieConfig) SetAuthCookies(w http.ResponseWriter, accessToken, refreshToken string, accessTTL, refreshTTL time.Duration) string {
+	csrf := newCSRFToken()
+	http.SetCookie(w, c.cookie(AccessTokenCookie, accessToken, "/", accessTTL, true))
+	http.SetCookie(w, c.cookie(RefreshTokenCookie, refreshToken, refreshCookiePath, refreshTTL, true))
+	http.SetCookie(w, c.cookie(CSRFCookie, csrf, "/", refreshTTL, false))
+	return csrf
+}
+
+// ClearAuthCookies удаляет cookie авторизации (выход)
+func (c CookieConfig) ClearAuthCookies(w http.ResponseWriter) {
+	http.SetCookie(w, c.cookie(AccessTokenCookie, "", "/", -1, true))
+	http.SetCookie(w, c.cookie(RefreshTokenCookie, "", refreshCookiePath, -1, true))
+	http.SetCookie(w, c.cookie(CSRFCookie, "", "/", -1, false))
+}
+
+// RefreshTokenFromCookie возвращает refresh токен из cookie, если режим включён
+func (c CookieConfig) RefreshTokenFromCookie(r *http.Request) (string, bool) {
+	if !c.Enabled {
+		return "", false
+	}
+	cookie, err := r.Cookie(RefreshTokenCookie)
+	if err != nil || cookie.Value == "" {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+// ValidCSRF - double-submit: заголовок X-CSRF-Token совпадает с cookie csrf_token.
+// Чужой сайт может заставить браузер отправить cookie, но не может их прочитать
+func ValidCSRF(r *http.Request) bool {
+	if isSafeMethod(r.Method) {
+		return true
+	}
+	cookie, err := r.Cookie(CSRFCookie)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+	header := r.Header.Get(CSRFHeader)
+	return subtle.ConstantTimeCompare([]byte(header), []byte(cookie.Value)) == 1
+}
+
+// SetOIDCStateCookie привязывает вход через OIDC к браузеру: callback
+// принимается, только если пришёл из браузера, который начал вход.
+// Cookie выставляется и без режима cookie: SameSite=Lax, иначе браузер
+// не отправит её при возврате со страницы провайдера
+func (c CookieConfig) SetOIDCStateCookie(w http.ResponseWriter, state string, ttl time.Duration) {
+	cookie := c.cookie(OIDCStateCookie, hashState(state), oidcStateCookiePath, ttl, true)
+	cookie.SameSite = http.SameSiteLaxMode
+	http.SetCookie(w, cookie)
+}
+
+// ClearOIDCStateCookie удаляет cookie state после callback
+func (c CookieConfig) ClearOIDCStateCookie(w http.ResponseWriter) {
+	cookie := c.cookie(OIDCStateCookie, "", oidcStateCookiePath, -1, true)
+	cookie.SameSite = http.SameSiteLaxMode
+	http.SetCookie(w, cookie)
+}
+
+// ValidOIDCState - state из callback совпадает с выданным этому браузеру
+func ValidOIDCState(r *http.Request, state string) bool {
+	cookie, err := r.Cookie(OIDCStateCookie)
+	if err != nil || cookie.Value == "" || state == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hashState(state)), []byte(cookie.Value)) == 1
+}
+
+func hashState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (c CookieConfig) cookie(name, value, path string, ttl time.Duration, httpOnly bool) *http.Cookie {
+	maxAge := int(ttl.Seconds())
+	if ttl < 0 {
+		maxAge = -1
+	}
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		MaxAge:   maxAge,
+		HttpOnly: httpOnly,
+		Secure:   c.Secure,
+		SameSite: c.SameSite,
+	}
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func newCSRFToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
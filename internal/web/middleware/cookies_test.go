@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOIDCStateCookie(t *testing.T) {
+	// режим cookie для токенов выключен и SameSite строгий: cookie state всё равно нужна
+	cookies := CookieConfig{Secure: true, SameSite: http.SameSiteStrictMode}
+	rec := httptest.NewRecorder()
+	cookies.SetOIDCStateCookie(rec, "state-1", time.Minute)
+
+	set := rec.Result().Cookies()
+	if len(set) != 1 {
+		t.Fatalf("set %d cookies, want 1", len(set))
+	}
+	cookie := set[0]
+	if cookie.Name != OIDCStateCookie || !cookie.HttpOnly || !cookie.Secure ||
+		cookie.SameSite != http.SameSiteLaxMode || cookie.Path != "/auth/oidc" || cookie.MaxAge != 60 {
+		t.Errorf("cookie = %+v, want HttpOnly Secure Lax on /auth/oidc for 60s", cookie)
+	}
+	if cookie.Value == "state-1" {
+		t.Error("cookie stores the state itself, want its hash")
+	}
+
+	tests := []struct {
+		name   string
+		cookie *http.Cookie
+		state  string
+		want   bool
+	}{
+		{"same browser", cookie, "state-1", true},
+		{"other state", cookie, "state-2", false},
+		{"no cookie", nil, "state-1", false},
+		{"empty state", cookie, "", false},
+		{"raw state in cookie", &http.Cookie{Name: OIDCStateCookie, Value: "state-1"}, "state-1", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/auth/oidc/callback", nil)
+		if tt.cookie != nil {
+			r.AddCookie(tt.cookie)
+		}
+		if got := ValidOIDCState(r, tt.state); got != tt.want {
+			t.Errorf("%s: ValidOIDCState = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	idempotency "tic-tac-toe/internal/domain/model/idempotency"
+)
+
+const (
+	// IdempotencyKeyHeader - заголовок с ключом идемпотентности запроса
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader отмечает ответ, повторённый из сохранённого
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// больше тела запроса с ключом не читаем
+	maxIdempotentBody = 1 << 20
+)
+
+// Idempotency повторяет сохранённый ответ на повтор изменяющего запроса с тем же
+// заголовком Idempotency-Key: клиент на нестабильной сети может безопасно переотправить
+// запрос, не создав вторую игру и не получив "not your turn" на уже сделанный ход.
+// Ключ действует для пользователя из контекста (middleware должен стоять после MiddlewareAuth)
+// в течение idempotency.TTL. Тот же ключ с другим запросом - 422, пока первый запрос
+// выполняется - 409. Ответы 5xx и 409 (запрос не применён из-за параллельного изменения)
+// не сохраняются: такой запрос можно повторить с тем же ключом.
+// При ошибке хранилища запрос выполняется без ключа, чтобы не останавливать сервис.
+func Idempotency(repo idempotency.Repository) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || r.Method == http.MethodGet || r.Method == http.MethodOptions {
+				next(w, r)
+				return
+			}
+			userID, ok := GetUserIDFromContext(r.Context())
+			if !ok {
+				next(w, r)
+				return
+			}
+			if len(key) > idempotency.MaxKeyLength {
+				http.Error(w, "Idempotency-Key is too long", http.StatusBadRequest)
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBody+1))
+			if err != nil {
+				http.Error(w, "Failed to read request body", http.StatusBadRequest)
+				return
+			}
+			if len(body) > maxIdempotentBody {
+				http.Error(w, "Request body is too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			now := time.Now()
+			record, created, err := repo.Reserve(r.Context(), idempotency.Record{
+				UserID:      userID,
+				Key:         key,
+				RequestHash: requestHash(r, body),
+				ExpiresAt:   now.Add(idempotency.TTL),
+			}, now)
+			if err != nil {
+				log.Printf("Idempotency store error for user %s: %v", userID, err)
+				next(w, r)
+				return
+			}
+			if !created {
+				switch {
+				case record.RequestHash != requestHash(r, body):
+					http.Error(w, "Idempotency-Key was already used for a different request", http.StatusUnprocessableEntity)
+				case record.Response == nil:
+					http.Error(w, "A request with this Idempotency-Key is in progress", http.StatusConflict)
+				default:
+					replay(w, *record.Response)
+				}
+				return
+			}
+
+			// ответ уже отправлен клиенту, результат сохраняем и при его отключении
+			ctx := context.WithoutCancel(r.Context())
+			defer func() {
+				if p := recover(); p != nil {
+					if err := repo.Release(ctx, userID, key); err != nil {
+						log.Printf("Idempotency store error for user %s: %v", userID, err)
+					}
+					panic(p)
+				}
+			}()
+
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next(rec, r)
+
+			if rec.status >= http.StatusInternalServerError || rec.status == http.StatusConflict {
+				err = repo.Release(ctx, userID, key)
+			} else {
+				err = repo.Complete(ctx, userID, key, idempotency.Response{
+					StatusCode:  rec.status,
+					ContentType: w.Header().Get("Content-Type"),
+					Body:        rec.body.Bytes(),
+				})
+			}
+			if err != nil {
+				log.Printf("Idempotency store error for user %s: %v", userID, err)
+			}
+		}
+	}
+}
+
+// requestHash - отпечаток запроса: метод, путь и тело
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.Path+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replay(w http.ResponseWriter, response idempotency.Response) {
+	if response.ContentType != "" {
+		w.Header().Set("Content-Type", response.ContentType)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.Header().Set("Content-Length", strconv.Itoa(len(response.Body)))
+	w.WriteHeader(response.StatusCode)
+	if _, err := w.Write(response.Body); err != nil {
+		log.Printf("Error writing replayed response: %v", err)
+	}
+}
+
+// responseRecorder пропускает ответ клиенту и запоминает его для повтора
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"tic-tac-toe/internal/storage/memory"
+
+	"github.com/google/uuid"
+)
+
+// countingHandler отвечает заданным статусом и считает вызовы
+type countingHandler struct {
+	calls  atomic.Int32
+	status atomic.Int32
+}
+
+func newCountingHandler(status int) *countingHandler {
+	h := &countingHandler{}
+	h.status.Store(int32(status))
+	return h
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := h.calls.Add(1)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(int(h.status.Load()))
+	w.Write([]byte(`{"call":` + strconv.Itoa(int(n)) + `}`))
+}
+
+func idempotentRequest(userID uuid.UUID, key, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/game", strings.NewReader(body))
+	r.Header.Set(IdempotencyKeyHeader, key)
+	return r.WithContext(context.WithValue(r.Context(), UserIDKey, userID))
+}
+
+func serveIdempotent(handler http.HandlerFunc, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	next := newCountingHandler(http.StatusCreated)
+	handler := Idempotency(memory.NewIdempotencyRepository(memory.NewStore()))(next.ServeHTTP)
+	userID := uuid.New()
+
+	first := serveIdempotent(handler, idempotentRequest(userID, "key-1", `{"x":1}`))
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first status = %d, want 201", first.Code)
+	}
+	if got := first.Header().Get(IdempotentReplayedHeader); got != "" {
+		t.Errorf("first %s = %q, want empty", IdempotentReplayedHeader, got)
+	}
+
+	second := serveIdempotent(handler, idempotentRequest(userID, "key-1", `{"x":1}`))
+	if second.Code != http.StatusCreated {
+		t.Errorf("replay status = %d, want 201", second.Code)
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Errorf("replay body = %q, want %q", second.Body.String(), first.Body.String())
+	}
+	if got := second.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("replay Content-Type = %q, want application/json", got)
+	}
+	if got := second.Header().Get(IdempotentReplayedHeader); got != "true" {
+		t.Errorf("replay %s = %q, want true", IdempotentReplayedHeader, got)
+	}
+	if calls := next.calls.Load(); calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
+
+func TestIdempotencyDifferentBody(t *testing.T) {
+	next := newCountingHandler(http.StatusCreated)
+	handler := Idempotency(memory.NewIdempotencyRepository(memory.NewStore()))(next.ServeHTTP)
+	userID := uuid.New()
+
+	serveIdempotent(handler, idempotentRequest(userID, "key-1", `{"x":1}`))
+	w := serveIdempotent(handler, idempotentRequest(userID, "key-1", `{"x":2}`))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want 422", w.Code)
+	}
+	if calls := next.calls.Load(); calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := Idempotency(memory.NewIdempotencyRepository(memory.NewStore()))(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+	userID := uuid.New()
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- serveIdempotent(handler, idempotentRequest(userID, "key-1", `{}`))
+	}()
+	<-started
+
+	w := serveIdempotent(handler, idempotentRequest(userID, "key-1", `{}`))
+	if w.Code != http.StatusConflict {
+		t.Errorf("concurrent status = %d, want 409", w.Code)
+	}
+
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("first status = %d, want 201", first.Code)
+	}
+}
+
+func TestIdempotencyReleasesKey(t *testing.T) {
+	for _, status := range []int{http.StatusInternalServerError, http.StatusConflict} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			next := newCountingHandler(status)
+			handler := Idempotency(memory.NewIdempotencyRepository(memory.NewStore()))(next.ServeHTTP)
+			userID := uuid.New()
+
+			if w := serveIdempotent(handler, idempotentRequest(userID, "key-1", `{}`)); w.Code != status {
+				t.Fatalf("first status = %d, want %d", w.Code, status)
+			}
+
+			// ответ не сохранён: повтор с тем же ключом выполняется заново
+			next.status.Store(http.StatusCreated)
+			w := serveIdempotent(handler, idempotentRequest(userID, "key-1", `{}`))
+			if w.Code != http.StatusCreated {
+				t.Errorf("retry status = %d, want 201", w.Code)
+			}
+			if got := w.Header().Get(IdempotentReplayedHeader); got != "" {
+				t.Errorf("retry %s = %q, want empty", IdempotentReplayedHeader, got)
+			}
+			if calls := next.calls.Load(); calls != 2 {
+				t.Errorf("handler called %d times, want 2", calls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyPerUser(t *testing.T) {
+	next := newCountingHandler(http.StatusCreated)
+	handler := Idempotency(memory.NewIdempotencyRepository(memory.NewStore()))(next.ServeHTTP)
+
+	serveIdempotent(handler, idempotentRequest(uuid.New(), "key-1", `{}`))
+	w := serveIdempotent(handler, idempotentRequest(uuid.New(), "key-1", `{}`))
+	if w.Code != http.StatusCreated {
+		t.Errorf("status = %d, want 201", w.Code)
+	}
+	if got := w.Header().Get(IdempotentReplayedHeader); got != "" {
+		t.Errorf("%s = %q, want empty for another user", IdempotentReplayedHeader, got)
+	}
+	if calls := next.calls.Load(); calls != 2 {
+		t.Errorf("handler called %d times, want 2", calls)
+	}
+}
@@ -2,7 +2,9 @@ package middleware
 
 import (
 	"context"
+	"log"
 	"net/http"
+	"slices"
 	"strings"
 	jwtService "tic-tac-toe/internal/service/jwt_service"
 
@@ -14,7 +16,7 @@ func EnableCORS(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, X-CSRF-Token, X-Auth-Mode, Idempotency-Key")
 		w.Header().Set("Access-Control-Max-Age", "86400")
 
 		if r.Method == "OPTIONS" {
@@ -48,11 +50,21 @@ func Chain(handler http.HandlerFunc, middlewares ...func(http.HandlerFunc) http.
 // ключ для хранения UUID пользователя в контексте
 type contextKey string
 
-const UserIDKey contextKey = "userID"
+const (
+	UserIDKey contextKey = "userID"
+	RolesKey  contextKey = "roles"
+)
+
+// BanChecker - быстрая (кэшируемая) проверка бана пользователя
+type BanChecker interface {
+	IsBanned(ctx context.Context, userID uuid.UUID) (bool, error)
+}
 
 // для проверки авторизации пользователя
-// Ожидает заголовок Authorization с UUID пользователя
-func MiddlewareAuth(jwt jwtService.JwtProvider) func(http.HandlerFunc) http.HandlerFunc {
+// Ожидает заголовок Authorization: Bearer <access token>; в режиме cookie
+// токен берётся из HttpOnly cookie, а изменяющие запросы проверяются на CSRF.
+// Уже выданные токены забаненного пользователя отклоняются
+func MiddlewareAuth(jwt jwtService.JwtProvider, cookies CookieConfig, bans BanChecker) func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 
@@ -61,18 +73,25 @@ func MiddlewareAuth(jwt jwtService.JwtProvider) func(http.HandlerFunc) http.Hand
 				return
 			}
 
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
+			var token string
+			if authHeader := r.Header.Get("Authorization"); authHeader != "" {
+				parts := strings.Split(authHeader, " ")
+				if len(parts) != 2 || parts[0] != "Bearer" {
+					http.Error(w, "invalid authorization header", http.StatusUnauthorized)
+					return
+				}
+				token = parts[1]
+			} else if cookie, err := r.Cookie(AccessTokenCookie); cookies.Enabled && err == nil && cookie.Value != "" {
+				// браузер отправляет cookie сам, поэтому нужна защита от CSRF
+				if !ValidCSRF(r) {
+					http.Error(w, "invalid CSRF token", http.StatusForbidden)
+					return
+				}
+				token = cookie.Value
+			} else {
 				http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
 				return
 			}
-			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				http.Error(w, "invalid authorization header", http.StatusUnauthorized)
-				return
-			}
-
-			token := parts[1]
 
 			claims, err := jwt.ValidateAccessToken(token)
 			if err != nil {
@@ -80,8 +99,20 @@ func MiddlewareAuth(jwt jwtService.JwtProvider) func(http.HandlerFunc) http.Hand
 				return
 			}
 
+			banned, err := bans.IsBanned(r.Context(), claims.UserID)
+			if err != nil {
+				log.Printf("Error checking ban for %s: %v", claims.UserID, err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			if banned {
+				http.Error(w, "User is banned", http.StatusForbidden)
+				return
+			}
+
 			// Добавляем UUID пользователя в контекст
 			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+			ctx = context.WithValue(ctx, RolesKey, claims.Roles)
 			r = r.WithContext(ctx)
 
 			next(w, r)
@@ -89,6 +120,50 @@ func MiddlewareAuth(jwt jwtService.JwtProvider) func(http.HandlerFunc) http.Hand
 	}
 }
 
+// PresenceTracker отмечает активность пользователя
+type PresenceTracker interface {
+	Touch(ctx context.Context, userID uuid.UUID) error
+}
+
+// TrackPresence отмечает активность авторизованного пользователя;
+// ставится в Chain перед MiddlewareAuth. Ошибка не прерывает запрос
+func TrackPresence(presence PresenceTracker) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if userID, ok := GetUserIDFromContext(r.Context()); ok {
+				if err := presence.Touch(r.Context(), userID); err != nil {
+					log.Printf("Error tracking presence for %s: %v", userID, err)
+				}
+			}
+			next(w, r)
+		}
+	}
+}
+
+// RequireRole пропускает только пользователей с ролью role из access-токена;
+// ставится в Chain перед MiddlewareAuth, чтобы выполняться после него
+func RequireRole(role string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions {
+				next(w, r)
+				return
+			}
+			if !HasRole(r.Context(), role) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// HasRole сообщает, есть ли роль в access-токене запроса
+func HasRole(ctx context.Context, role string) bool {
+	roles, _ := ctx.Value(RolesKey).([]string)
+	return slices.Contains(roles, role)
+}
+
 // для извлечения UUID пользователя из контекста
 func GetUserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
 	userID, ok := ctx.Value(UserIDKey).(uuid.UUID)
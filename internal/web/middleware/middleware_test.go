@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	userModel "tic-tac-toe/internal/domain/model/user"
+	jwtService "tic-tac-toe/internal/service/jwt_service"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// noBans никого не считает забаненным
+type noBans struct{}
+
+func (noBans) IsBanned(ctx context.Context, userID uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func newJwtProvider(t *testing.T) jwtService.JwtProvider {
+	t.Helper()
+	keys, err := jwtService.LoadKeySet("", nil, "", []byte("secret"))
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+	return jwtService.NewJwtProvider(keys)
+}
+
+// legacyAccessToken - access-токен, выданный до появления ролей: без клейма roles
+func legacyAccessToken(t *testing.T, userID uuid.UUID) string {
+	t.Helper()
+	now := time.Now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID.String(),
+		"iss":     jwtService.Issuer,
+		"sub":     jwtService.TokenTypeAccess,
+		"jti":     uuid.NewString(),
+		"iat":     now.Unix(),
+		"exp":     now.Add(time.Minute).Unix(),
+	}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return token
+}
+
+func accessToken(t *testing.T, provider jwtService.JwtProvider, user userModel.User) string {
+	t.Helper()
+	token, err := provider.GenerateAccessToken(user)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	return token
+}
+
+// serveAuth пропускает запрос через MiddlewareAuth и возвращает пользователя из контекста
+func serveAuth(provider jwtService.JwtProvider, cookies CookieConfig, r *http.Request) (*httptest.ResponseRecorder, uuid.UUID) {
+	var userID uuid.UUID
+	handler := Chain(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ = GetUserIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}, MiddlewareAuth(provider, cookies, noBans{}))
+
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w, userID
+}
+
+func TestValidCSRF(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		cookie string
+		header string
+		want   bool
+	}{
+		{"matching header", http.MethodPost, "csrf-1", "csrf-1", true},
+		{"mismatched header", http.MethodPost, "csrf-1", "csrf-2", false},
+		{"missing header", http.MethodPost, "csrf-1", "", false},
+		{"missing cookie", http.MethodPost, "", "csrf-1", false},
+		{"empty cookie and header", http.MethodDelete, "", "", false},
+		{"safe GET", http.MethodGet, "", "", true},
+		{"safe HEAD", http.MethodHead, "csrf-1", "csrf-2", true},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, "/game", nil)
+		if tt.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: CSRFCookie, Value: tt.cookie})
+		}
+		if tt.header != "" {
+			r.Header.Set(CSRFHeader, tt.header)
+		}
+		if got := ValidCSRF(r); got != tt.want {
+			t.Errorf("%s: ValidCSRF = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMiddlewareAuthCookie(t *testing.T) {
+	provider := newJwtProvider(t)
+	user := userModel.User{UUID: uuid.New(), Login: "alice"}
+	token := accessToken(t, provider, user)
+	cookies := CookieConfig{Enabled: true}
+
+	request := func(method, csrfHeader string) *http.Request {
+		r := httptest.NewRequest(method, "/game", nil)
+		r.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: token})
+		r.AddCookie(&http.Cookie{Name: CSRFCookie, Value: "csrf-1"})
+		if csrfHeader != "" {
+			r.Header.Set(CSRFHeader, csrfHeader)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name string
+		r    *http.Request
+		want int
+	}{
+		{"matching CSRF header", request(http.MethodPost, "csrf-1"), http.StatusOK},
+		{"missing CSRF header", request(http.MethodPost, ""), http.StatusForbidden},
+		{"mismatched CSRF header", request(http.MethodDelete, "csrf-2"), http.StatusForbidden},
+		{"GET without CSRF header", request(http.MethodGet, ""), http.StatusOK},
+	}
+	for _, tt := range tests {
+		w, userID := serveAuth(provider, cookies, tt.r)
+		if w.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.want)
+			continue
+		}
+		if tt.want == http.StatusOK && userID != user.UUID {
+			t.Errorf("%s: user in context = %s, want %s", tt.name, userID, user.UUID)
+		}
+	}
+
+	// без режима cookie токен из cookie не принимается
+	w, _ := serveAuth(provider, CookieConfig{}, request(http.MethodPost, "csrf-1"))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("cookie mode disabled: status = %d, want 401", w.Code)
+	}
+}
+
+func TestMiddlewareAuthBearerFirst(t *testing.T) {
+	provider := newJwtProvider(t)
+	bearer := userModel.User{UUID: uuid.New(), Login: "alice"}
+	cookie := userModel.User{UUID: uuid.New(), Login: "bob"}
+
+	// заголовок Authorization важнее cookie: CSRF не проверяется, пользователь из Bearer
+	r := httptest.NewRequest(http.MethodPost, "/game", nil)
+	r.Header.Set("Authorization", "Bearer "+accessToken(t, provider, bearer))
+	r.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: accessToken(t, provider, cookie)})
+
+	w, userID := serveAuth(provider, CookieConfig{Enabled: true}, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if userID != bearer.UUID {
+		t.Errorf("user in context = %s, want Bearer user %s", userID, bearer.UUID)
+	}
+
+	// неверный Bearer не подменяется валидной cookie
+	r = httptest.NewRequest(http.MethodGet, "/game", nil)
+	r.Header.Set("Authorization", "Bearer broken")
+	r.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: accessToken(t, provider, cookie)})
+	if w, _ := serveAuth(provider, CookieConfig{Enabled: true}, r); w.Code != http.StatusUnauthorized {
+		t.Errorf("broken Bearer: status = %d, want 401", w.Code)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	provider := newJwtProvider(t)
+	player := userModel.User{UUID: uuid.New(), Login: "player"}
+	admin := userModel.User{UUID: uuid.New(), Login: "admin", Roles: []userModel.Role{userModel.RoleAdmin}}
+
+	handler := Chain(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}, RequireRole(string(userModel.RoleAdmin)), MiddlewareAuth(provider, CookieConfig{}, noBans{}))
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"without role", accessToken(t, provider, player), http.StatusForbidden},
+		{"with role", accessToken(t, provider, admin), http.StatusOK},
+		{"token without roles claim", legacyAccessToken(t, admin.UUID), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/admin/users", nil)
+		r.Header.Set("Authorization", "Bearer "+tt.token)
+		w := httptest.NewRecorder()
+		handler(w, r)
+		if w.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	ratelimit "tic-tac-toe/internal/domain/model/ratelimit"
+)
+
+// RateLimit ограничивает частоту запросов группы маршрутов group.
+// Ключ - пользователь из контекста (middleware должен стоять после MiddlewareAuth)
+// или IP клиента для запросов без авторизации.
+// При ошибке хранилища запрос пропускается, чтобы не останавливать сервис.
+func RateLimit(limiter ratelimit.Limiter, group string, limit ratelimit.Limit) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions {
+				next(w, r)
+				return
+			}
+
+			key := group + ":ip:" + ClientIP(r)
+			if userID, ok := GetUserIDFromContext(r.Context()); ok {
+				key = group + ":user:" + userID.String()
+			}
+
+			allowed, retryAfter, err := limiter.Allow(r.Context(), key, limit)
+			if err != nil {
+				log.Printf("Rate limiter error for %s: %v", key, err)
+				next(w, r)
+				return
+			}
+			if !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				http.Error(w, "Too many requests", http.StatusTooManyRequests)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// ByMethod применяет read к GET-запросам, а write - ко всем остальным
+// (например, разные лимиты для чтения и ходов на одном маршруте)
+func ByMethod(read, write func(http.HandlerFunc) http.HandlerFunc) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		readHandler, writeHandler := read(next), write(next)
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				readHandler(w, r)
+				return
+			}
+			writeHandler(w, r)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ratelimit "tic-tac-toe/internal/domain/model/ratelimit"
+
+	"github.com/google/uuid"
+)
+
+// fakeLimiter запоминает ключи и отвечает заданным решением
+type fakeLimiter struct {
+	keys       []string
+	allowed    bool
+	retryAfter time.Duration
+	err        error
+}
+
+func (l *fakeLimiter) Allow(ctx context.Context, key string, limit ratelimit.Limit) (bool, time.Duration, error) {
+	l.keys = append(l.keys, key)
+	return l.allowed, l.retryAfter, l.err
+}
+
+func (l *fakeLimiter) Cleanup(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+func serveLimited(limiter ratelimit.Limiter, r *http.Request) *httptest.ResponseRecorder {
+	limit := ratelimit.Limit{Requests: 1, Period: time.Minute}
+	handler := Chain(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}, RateLimit(limiter, "auth", limit), RealIP(nil))
+
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w
+}
+
+func TestRateLimitRejectsWithRetryAfter(t *testing.T) {
+	limiter := &fakeLimiter{allowed: false, retryAfter: 1500 * time.Millisecond}
+	w := serveLimited(limiter, httptest.NewRequest(http.MethodPost, "/auth", nil))
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+	// дробные секунды округляются вверх
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+}
+
+func TestRateLimitKeys(t *testing.T) {
+	limiter := &fakeLimiter{allowed: true}
+
+	r := httptest.NewRequest(http.MethodPost, "/auth", nil)
+	r.RemoteAddr = "203.0.113.7:5000"
+	// без доверенных прокси подменённый X-Forwarded-For не меняет ключ
+	r.Header.Set("X-Forwarded-For", "1.2.3.4")
+	if w := serveLimited(limiter, r); w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", w.Code)
+	}
+
+	userID := uuid.New()
+	r = httptest.NewRequest(http.MethodPost, "/auth", nil)
+	r = r.WithContext(context.WithValue(r.Context(), UserIDKey, userID))
+	serveLimited(limiter, r)
+
+	want := []string{"auth:ip:203.0.113.7", "auth:user:" + userID.String()}
+	if len(limiter.keys) != 2 || limiter.keys[0] != want[0] || limiter.keys[1] != want[1] {
+		t.Errorf("keys = %v, want %v", limiter.keys, want)
+	}
+}
+
+func TestRateLimitPassesOnLimiterError(t *testing.T) {
+	limiter := &fakeLimiter{err: errors.New("db is down")}
+	if w := serveLimited(limiter, httptest.NewRequest(http.MethodPost, "/auth", nil)); w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want request to pass", w.Code)
+	}
+}
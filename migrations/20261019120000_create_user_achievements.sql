@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS user_achievements(
+    user_id UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    code TEXT NOT NULL,
+    unlocked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, code)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_achievements;
+-- +goose StatementEnd
@@ -0,0 +1,52 @@
+-- +goose Up
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tournaments(
+    uuid UUID PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    format VARCHAR(32) NOT NULL,
+    status INTEGER NOT NULL DEFAULT 0,
+    owner_id UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    current_round INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tournament_participants(
+    tournament_id UUID NOT NULL REFERENCES tournaments(uuid) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    seed INTEGER NOT NULL,
+    joined_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (tournament_id, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tournament_pairings(
+    uuid UUID PRIMARY KEY,
+    tournament_id UUID NOT NULL REFERENCES tournaments(uuid) ON DELETE CASCADE,
+    round INTEGER NOT NULL,
+    board INTEGER NOT NULL,
+    player_x UUID NOT NULL,
+    player_o UUID,
+    game_id UUID,
+    result INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (tournament_id, round, board)
+);
+CREATE INDEX IF NOT EXISTS idx_tournament_pairings_game_id ON tournament_pairings(game_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tournament_pairings;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tournament_participants;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tournaments;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- +goose StatementBegin
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS rounds INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tournaments DROP COLUMN IF EXISTS rounds;
+-- +goose StatementEnd
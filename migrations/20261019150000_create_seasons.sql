@@ -0,0 +1,57 @@
+-- +goose Up
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS seasons(
+    uuid UUID PRIMARY KEY,
+    name VARCHAR(32) NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    status INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+-- активным может быть только один сезон
+CREATE UNIQUE INDEX IF NOT EXISTS idx_seasons_active ON seasons(status) WHERE status = 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS season_standings(
+    season_id UUID NOT NULL REFERENCES seasons(uuid) ON DELETE CASCADE,
+    place INTEGER NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    login VARCHAR(32) NOT NULL,
+    win_rate NUMERIC NOT NULL,
+    PRIMARY KEY (season_id, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE games ADD COLUMN IF NOT EXISTS season_id UUID REFERENCES seasons(uuid);
+CREATE INDEX IF NOT EXISTS idx_games_season_id ON games(season_id);
+-- +goose StatementEnd
+
+-- уже сыгранные игры относятся к первому сезону (текущий месяц)
+-- +goose StatementBegin
+INSERT INTO seasons (uuid, name, starts_at, ends_at, status)
+VALUES (
+    gen_random_uuid(),
+    to_char(date_trunc('month', NOW()), 'YYYY-MM'),
+    date_trunc('month', NOW()),
+    date_trunc('month', NOW()) + INTERVAL '1 month',
+    0
+);
+UPDATE games SET season_id = (SELECT uuid FROM seasons WHERE status = 0)
+WHERE status IN (2, 3, 4);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE games DROP COLUMN IF EXISTS season_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS season_standings;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS seasons;
+-- +goose StatementEnd
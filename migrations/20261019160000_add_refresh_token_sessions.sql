@@ -0,0 +1,20 @@
+-- +goose Up
+
+-- +goose StatementBegin
+ALTER TABLE refresh_tokens
+    ADD COLUMN IF NOT EXISTS id UUID NOT NULL DEFAULT gen_random_uuid(),
+    ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ip TEXT NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_id ON refresh_tokens(id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_refresh_tokens_id;
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS id,
+    DROP COLUMN IF EXISTS last_used_at,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS ip;
+-- +goose StatementEnd
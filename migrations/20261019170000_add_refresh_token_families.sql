@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- каждый существующий токен становится отдельным семейством (сессией)
+ALTER TABLE refresh_tokens
+    ADD COLUMN IF NOT EXISTS family_id UUID NOT NULL DEFAULT gen_random_uuid(),
+    ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS family_id,
+    DROP COLUMN IF EXISTS rotated_at;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS roles TEXT[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS roles;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- действующие санкции: не больше одной каждого вида на пользователя
+CREATE TABLE IF NOT EXISTS user_sanctions (
+    user_id UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL,
+    reason TEXT NOT NULL,
+    created_by UUID REFERENCES users(uuid) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- NULL - бессрочно
+    expires_at TIMESTAMPTZ,
+    PRIMARY KEY (user_id, kind)
+);
+
+-- журнал модерации: кто, что, с кем и почему
+CREATE TABLE IF NOT EXISTS moderation_log (
+    uuid UUID PRIMARY KEY,
+    actor_id UUID REFERENCES users(uuid) ON DELETE SET NULL,
+    target_id UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    action VARCHAR(16) NOT NULL,
+    reason TEXT NOT NULL,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_moderation_log_target_id ON moderation_log(target_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_moderation_log_created_at ON moderation_log(created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS moderation_log;
+DROP TABLE IF EXISTS user_sanctions;
+-- +goose StatementEnd
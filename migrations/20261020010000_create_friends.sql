@@ -0,0 +1,34 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- заявки в друзья и дружба: одна строка на пару, направление - от requester
+CREATE TABLE IF NOT EXISTS friendships (
+    requester_id UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    addressee_id UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    accepted_at TIMESTAMPTZ,
+    PRIMARY KEY (requester_id, addressee_id),
+    CHECK (requester_id <> addressee_id)
+);
+-- встречные заявки пары невозможны
+CREATE UNIQUE INDEX IF NOT EXISTS idx_friendships_pair
+    ON friendships (LEAST(requester_id, addressee_id), GREATEST(requester_id, addressee_id));
+CREATE INDEX IF NOT EXISTS idx_friendships_addressee_id ON friendships(addressee_id);
+
+-- чёрный список
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker_id UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked_id ON user_blocks(blocked_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_blocks;
+DROP TABLE IF EXISTS friendships;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- последняя активность пользователя (общая для всех инстансов)
+CREATE TABLE IF NOT EXISTS user_presence (
+    user_id UUID PRIMARY KEY REFERENCES users(uuid) ON DELETE CASCADE,
+    last_seen_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_user_presence_last_seen_at ON user_presence(last_seen_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_presence;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- входящие уведомления пользователя; read_at NULL - не прочитано
+CREATE TABLE IF NOT EXISTS notifications (
+    uuid UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    kind VARCHAR(32) NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    read_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notifications;
+-- +goose StatementEnd
@@ -0,0 +1,54 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- события игр, записанные в одной транзакции с игрой; processed_at NULL - ещё не разосланы
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(32) NOT NULL,
+    game_id UUID NOT NULL,
+    players UUID[] NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_unprocessed ON outbox(id) WHERE processed_at IS NULL;
+
+-- all_games - все игры (только администратор), иначе игры владельца
+CREATE TABLE IF NOT EXISTS webhooks (
+    uuid UUID PRIMARY KEY,
+    owner_id UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    events TEXT[] NOT NULL,
+    all_games BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_webhooks_owner_id ON webhooks(owner_id);
+
+-- журнал доставки: одна запись на пару вебхук-событие
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    uuid UUID PRIMARY KEY,
+    webhook_id UUID NOT NULL REFERENCES webhooks(uuid) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL,
+    event_type VARCHAR(32) NOT NULL,
+    payload JSONB NOT NULL,
+    -- pending, delivered, failed
+    status VARCHAR(16) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    response_code INT,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ,
+    UNIQUE (webhook_id, event_id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+DROP TABLE IF EXISTS outbox;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- outbox разбирает диспетчер событий: done - подписчики, уже обработавшие событие,
+-- processed_at - обработано всеми
+ALTER TABLE outbox
+    ADD COLUMN IF NOT EXISTS done TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_error TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+DROP INDEX IF EXISTS idx_outbox_unprocessed;
+CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox(next_attempt_at, id) WHERE processed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_processed_at ON outbox(processed_at) WHERE processed_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_outbox_processed_at;
+DROP INDEX IF EXISTS idx_outbox_due;
+CREATE INDEX IF NOT EXISTS idx_outbox_unprocessed ON outbox(id) WHERE processed_at IS NULL;
+ALTER TABLE outbox
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS done;
+-- +goose StatementEnd
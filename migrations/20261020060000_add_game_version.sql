@@ -0,0 +1,11 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- версия игры для оптимистической блокировки: каждое изменение увеличивает её на 1
+ALTER TABLE games ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE games DROP COLUMN IF EXISTS version;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- счётчики для правил достижений; initialized = FALSE - строка только создана
+-- и ещё не заполнена по истории игр
+CREATE TABLE IF NOT EXISTS achievement_stats(
+    user_id UUID PRIMARY KEY REFERENCES users(uuid) ON DELETE CASCADE,
+    initialized BOOLEAN NOT NULL DEFAULT FALSE,
+    games_played INT NOT NULL DEFAULT 0,
+    wins INT NOT NULL DEFAULT 0,
+    draws INT NOT NULL DEFAULT 0,
+    bot_wins INT NOT NULL DEFAULT 0,
+    win_streak INT NOT NULL DEFAULT 0,
+    fastest_win INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- игры, уже учтённые в счётчиках: повторная доставка события их не учитывает
+CREATE TABLE IF NOT EXISTS achievement_games(
+    user_id UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    game_id UUID NOT NULL,
+    PRIMARY KEY (user_id, game_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS achievement_games;
+DROP TABLE IF EXISTS achievement_stats;
+-- +goose StatementEnd
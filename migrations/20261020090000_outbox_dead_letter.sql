@@ -0,0 +1,19 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- status: pending - ждёт обработки, processed - обработано всеми подписчиками,
+-- dead - попытки исчерпаны, событие больше не выдаётся и хранится для разбора
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'pending';
+UPDATE outbox SET status = 'processed' WHERE processed_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_outbox_due;
+CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox(next_attempt_at, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_outbox_dead ON outbox(id) WHERE status = 'dead';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_outbox_dead;
+DROP INDEX IF EXISTS idx_outbox_due;
+CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox(next_attempt_at, id) WHERE processed_at IS NULL;
+ALTER TABLE outbox DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
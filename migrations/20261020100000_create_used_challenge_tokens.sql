@@ -0,0 +1,16 @@
+-- +goose Up
+
+-- +goose StatementBegin
+-- погашенные challenge токены входа с 2FA: один токен обменивается на пару токенов один раз
+CREATE TABLE IF NOT EXISTS used_challenge_tokens (
+    jti TEXT PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_used_challenge_tokens_user_id ON used_challenge_tokens(user_id, expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS used_challenge_tokens;
+-- +goose StatementEnd